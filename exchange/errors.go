@@ -0,0 +1,121 @@
+package exchange
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInsufficientMargin is returned when an order is rejected because the
+// account does not have enough margin (perps) or balance (spot) to place it.
+// Use errors.Is to check for it.
+var ErrInsufficientMargin = errors.New("insufficient margin")
+
+// ErrInvalidPrice is returned by ValidateOrder when a price has more
+// significant figures or decimals than the asset allows
+var ErrInvalidPrice = errors.New("invalid price")
+
+// ErrInvalidSize is returned by ValidateOrder when a size is not positive or
+// has more decimals than the asset's szDecimals
+var ErrInvalidSize = errors.New("invalid size")
+
+// insufficientMarginMarkers are the status codes and error message fragments
+// that the API uses when rejecting an order for lack of margin or balance
+var insufficientMarginMarkers = []string{
+	"perpMarginRejected",
+	"insufficientSpotBalanceRejected",
+	"Insufficient margin",
+	"Insufficient spot balance",
+}
+
+// orderStatusError converts an order status error message into an error,
+// mapping well known rejections to their typed errors
+func orderStatusError(msg string) error {
+	for _, marker := range insufficientMarginMarkers {
+		if strings.Contains(msg, marker) {
+			return fmt.Errorf("%w: %s", ErrInsufficientMargin, msg)
+		}
+	}
+
+	return errors.New(msg)
+}
+
+// StatusError is returned when the exchange rejects an action, either as a
+// whole or for some of the orders in it. Use errors.As to inspect it.
+type StatusError struct {
+	// Action is the type of the rejected action
+	Action string
+	// Message is the reason given when the whole action was rejected
+	Message string
+	// Failures lists the orders that were rejected, in request order
+	Failures []StatusFailure
+	// Orders holds the status of every order in the request. Rejected
+	// orders are left as the zero value.
+	Orders BulkOrdersResponse
+	// Body is the raw response body
+	Body json.RawMessage
+}
+
+// StatusFailure is a single rejected order within a request
+type StatusFailure struct {
+	// Index is the position of the order in the request
+	Index int
+	// Err describes why the order was rejected
+	Err error
+}
+
+func (e *StatusError) Error() string {
+	if len(e.Failures) == 0 {
+		return fmt.Sprintf(
+			"exchange error (action: %v): %s",
+			e.Action,
+			e.Message,
+		)
+	}
+
+	reasons := make([]string, len(e.Failures))
+	for i, f := range e.Failures {
+		reasons[i] = fmt.Sprintf("order %d: %v", f.Index, f.Err)
+	}
+	return fmt.Sprintf(
+		"exchange error (action: %v): %s",
+		e.Action,
+		strings.Join(reasons, "; "),
+	)
+}
+
+// Unwrap exposes the per-order errors so errors.Is matches typed rejections
+// such as ErrInsufficientMargin
+func (e *StatusError) Unwrap() []error {
+	errs := make([]error, len(e.Failures))
+	for i, f := range e.Failures {
+		errs[i] = f.Err
+	}
+	return errs
+}
+
+// ReplaceError is returned when cancel-and-replace modifies fail after some
+// of their orders may have been cancelled, either by a later cancel action
+// or by placing the new orders. Cancelled orders are gone, so the account is
+// left without them. Use errors.As to inspect it.
+type ReplaceError struct {
+	// Canceled holds the statuses of the cancel actions that succeeded.
+	// Orders in a cancel action that failed may also have been cancelled.
+	Canceled BulkCancelResponse
+	// Err is the error that stopped the replace
+	Err error
+}
+
+func (e *ReplaceError) Error() string {
+	return fmt.Sprintf(
+		"replace failed after cancelling %d orders: %v",
+		len(e.Canceled),
+		e.Err,
+	)
+}
+
+// Unwrap returns the error that stopped the replace
+func (e *ReplaceError) Unwrap() error {
+	return e.Err
+}
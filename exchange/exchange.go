@@ -3,16 +3,26 @@ package exchange
 import (
 	"context"
 	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"math"
+	"math/big"
+	"net"
+	"net/http"
 	"slices"
+	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/banky/go-hyperliquid/constants"
 	"github.com/banky/go-hyperliquid/info"
 	"github.com/banky/go-hyperliquid/internal/utils"
+	"github.com/banky/go-hyperliquid/logging"
 	"github.com/banky/go-hyperliquid/rest"
+	"github.com/banky/go-hyperliquid/types"
+	"github.com/banky/go-hyperliquid/ws"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/samber/mo"
@@ -30,47 +40,137 @@ type Config struct {
 	Meta           *info.Meta
 	SpotMeta       *info.SpotMeta
 	PerpDexes      []string
+	// Info is an optional, already initialized info client to share with the
+	// exchange. If set, no new info client is created and SkipInfo, SkipWS,
+	// Meta, SpotMeta and PerpDexes are ignored. It must point at the same
+	// network as BaseURL.
+	Info *info.Info
+	// Retry is the retry policy for info queries, including those made by
+	// the exchange's own info client. If unset, requests are not retried.
+	Retry rest.RetryPolicy
+	// ExchangeRetry is the retry policy for actions. Only network errors are
+	// ever retried, so an action is never submitted twice. If unset, actions
+	// are not retried.
+	ExchangeRetry rest.RetryPolicy
+	// MaxOrdersPerAction is the most orders BulkOrders puts in one order
+	// action, and the most cancels CancelAll puts in one cancel action.
+	// Larger ungrouped batches are split into several actions. If unset,
+	// DefaultMaxOrdersPerAction is used.
+	MaxOrdersPerAction int
+	// MidCacheTTL is how long mid prices used to price market orders are
+	// reused before they are fetched again. It also bounds how stale a mid
+	// from StreamMids may be. If unset, mids are fetched for every order.
+	MidCacheTTL time.Duration
+	// Logger receives events such as nonce selection, submitted actions,
+	// failed posts and websocket reconnects. It is also passed to the
+	// exchange's own info client. If unset, events are discarded.
+	Logger logging.Logger
+	// Observer is notified of the duration and outcome of every /exchange
+	// request and every query made by the exchange's own info client. A
+	// WebSocketTransport reports its posts through its own observer. If
+	// unset, requests are not observed.
+	Observer logging.Observer
+	// SignatureChainID is the chain id that user-signed actions such as
+	// transfers and withdrawals are signed for. It is sent as the action's
+	// signatureChainId and used as the EIP-712 domain chain id. If unset,
+	// constants.SIGNATURE_CHAIN_ID is used, which mainnet and testnet both
+	// accept.
+	SignatureChainID int64
+	// HTTPClient is the client used for REST requests, including those of
+	// the exchange's own info client. If unset, a default client is used.
+	HTTPClient *http.Client
 }
 
 // Exchange provides access to trading operations via REST API
 type Exchange struct {
 	rest           rest.ClientInterface
 	info           *info.Info
+	ownsInfo       bool
 	privateKey     *ecdsa.PrivateKey
 	vaultAddress   mo.Option[common.Address]
 	accountAddress mo.Option[common.Address]
 	expiresAfter   mo.Option[time.Duration]
 	prevNonce      *atomic.Int64
+	wsTransport    WebSocketTransport
+	maxOrders      int
+	midCacheTTL    time.Duration
+	midsMu         sync.Mutex
+	mids           map[string]cachedMids
+	logger         logging.Logger
+	chainID        int64
+}
+
+// WebSocketTransport sends a request over an open websocket using the post
+// method. *ws.Client implements it.
+type WebSocketTransport interface {
+	Post(
+		ctx context.Context,
+		requestType string,
+		payload any,
+	) (json.RawMessage, error)
+}
+
+// Option configures an Exchange
+type Option func(*Exchange)
+
+// WithWebSocketTransport sends signed actions through the websocket post
+// method instead of REST, saving a round trip on order submission. Signing is
+// unchanged. Requests fall back to REST while the websocket is not
+// connected.
+func WithWebSocketTransport(transport WebSocketTransport) Option {
+	return func(e *Exchange) {
+		e.wsTransport = transport
+	}
 }
 
 // New creates a new Exchange client
-func New(cfg Config) (*Exchange, error) {
+func New(cfg Config, opts ...Option) (*Exchange, error) {
 	if cfg.PrivateKey == nil {
 		return nil, fmt.Errorf("private key is required")
 	}
 
 	// Create REST client
 	restClient := rest.New(rest.Config{
-		BaseUrl: cfg.BaseURL,
-		Timeout: cfg.Timeout,
+		BaseUrl:       cfg.BaseURL,
+		Timeout:       cfg.Timeout,
+		Retry:         cfg.Retry,
+		ExchangeRetry: cfg.ExchangeRetry,
+		Observer:      cfg.Observer,
+		HTTPClient:    cfg.HTTPClient,
 	})
 
 	var infoClient *info.Info
-	if !cfg.SkipInfo {
+	var ownsInfo bool
+	if cfg.Info != nil {
+		if cfg.Info.IsMainnet() != restClient.IsMainnet() {
+			return nil, fmt.Errorf(
+				"network mismatch: exchange is on %s but info is on %s",
+				restClient.NetworkName(),
+				cfg.Info.NetworkName(),
+			)
+		}
+
+		infoClient = cfg.Info
+	} else if !cfg.SkipInfo {
 		// Create Info client
 		i, err := info.New(info.Config{
-			BaseURL:  cfg.BaseURL,
-			Timeout:  cfg.Timeout,
-			SkipWS:   true,
-			Meta:     cfg.Meta,
-			SpotMeta: cfg.SpotMeta,
-			PerpDexs: cfg.PerpDexes,
+			BaseURL:    cfg.BaseURL,
+			Timeout:    cfg.Timeout,
+			SkipWS:     true,
+			Meta:       cfg.Meta,
+			SpotMeta:   cfg.SpotMeta,
+			PerpDexs:   cfg.PerpDexes,
+			Retry:      cfg.Retry,
+			Logger:     cfg.Logger,
+			Observer:   cfg.Observer,
+			HTTPClient: cfg.HTTPClient,
 		})
 		if err != nil {
 			return nil, fmt.Errorf("failed to create info client: %w", err)
 		}
 
 		infoClient = i
+		ownsInfo = true
 	}
 
 	var vaultAddress mo.Option[common.Address]
@@ -83,30 +183,47 @@ func New(cfg Config) (*Exchange, error) {
 		accountAddress = mo.Some(cfg.AccountAddress)
 	}
 
+	if cfg.MaxOrdersPerAction <= 0 {
+		cfg.MaxOrdersPerAction = DefaultMaxOrdersPerAction
+	}
+
 	prevNonce := new(atomic.Int64)
 	prevNonce.Store(time.Now().UnixMilli())
 
-	return &Exchange{
+	e := &Exchange{
 		rest:           restClient,
 		info:           infoClient,
+		ownsInfo:       ownsInfo,
 		privateKey:     cfg.PrivateKey,
 		accountAddress: accountAddress,
 		vaultAddress:   vaultAddress,
 		expiresAfter:   mo.None[time.Duration](),
 		prevNonce:      prevNonce,
-	}, nil
+		maxOrders:      cfg.MaxOrdersPerAction,
+		midCacheTTL:    cfg.MidCacheTTL,
+		mids:           make(map[string]cachedMids),
+		logger:         logging.OrNop(cfg.Logger),
+		chainID:        cfg.SignatureChainID,
+	}
+
+	for _, opt := range opts {
+		opt(e)
+	}
+
+	return e, nil
 }
 
-// Close cleans up the Exchange instance
+// Close cleans up the Exchange instance. An info client passed in through
+// Config.Info is left open, since it may be shared.
 func (e *Exchange) Close() {
-	if e.info != nil {
+	if e.info != nil && e.ownsInfo {
 		e.info.Close()
 	}
 }
 
 // SetExpiresAfter sets the expiration time for actions (in milliseconds)
-// This is not supported on user-signed actions and must be None for those to
-// work
+// It only applies to L1 actions. User-signed actions such as transfers and
+// withdrawals do not support it and are always sent without it.
 func (e *Exchange) SetExpiresAfter(expiresAfter time.Duration) {
 	e.expiresAfter = mo.Some(expiresAfter)
 }
@@ -116,6 +233,10 @@ func (e *Exchange) ClearExpiresAfter() {
 	e.expiresAfter = mo.None[time.Duration]()
 }
 
+// SignMultisigPayload signs req as an authorized user of multisigUser. The
+// returned signature is passed to MultiSigRequest. privateKey is also taken
+// to be the outer signer, so use SignForMultiSig when the co-signer is not
+// the account that submits the transaction.
 func SignMultisigPayload[T request](
 	ctx context.Context,
 	e *Exchange,
@@ -133,21 +254,76 @@ func SignMultisigPayload[T request](
 	}
 
 	outerSigner := crypto.PubkeyToAddress(privateKey.PublicKey)
+	return signMultiSigInner(
+		e,
+		action,
+		timestamp,
+		privateKey,
+		multisigUser,
+		outerSigner,
+	)
+}
+
+// SignForMultiSig returns the signature of one authorized user of
+// multiSigUser over innerRequest. Each co-signer calls it with their own
+// signerKey and the same innerRequest and nonce, and the collected
+// signatures are passed to MultiSigRequest. The outer signer, which must
+// later call MultiSig, is the signing key of e.
+//
+// For L1 actions such as orders and cancels, each signer signs the L1
+// action [lower(multiSigUser), lower(outerSigner), action] with nonce, using
+// e's vault address and expiresAfter, exactly as a normal L1 action would be
+// signed. For user-signed actions such as usdSend, each signer signs the
+// action's usual EIP-712 message with payloadMultiSigUser and outerSigner
+// (both addresses) inserted after hyperliquidChain.
+func SignForMultiSig[T request](
+	ctx context.Context,
+	e *Exchange,
+	innerRequest T,
+	multiSigUser common.Address,
+	nonce int64,
+	signerKey *ecdsa.PrivateKey,
+) (signature, error) {
+	action, err := innerRequest.toAction(ctx, e, nonce)
+	if err != nil {
+		return signature{}, fmt.Errorf(
+			"failed to convert request to action: %w",
+			err,
+		)
+	}
 
-	// Check if this is a user-signed action or L1 action
-	actionMap := action.getMap()
-	if actionMap != nil {
-		// User-signed action - use signMultiSigUserSignedActionPayload
-		payloadTypes := action.getPayloadTypes()
-		primaryType := action.getPrimaryType()
+	return signMultiSigInner(
+		e,
+		action,
+		nonce,
+		signerKey,
+		multiSigUser,
+		crypto.PubkeyToAddress(e.privateKey.PublicKey),
+	)
+}
 
+// signMultiSigInner signs the inner action of a multi-sig transaction. L1
+// actions such as orders and cancels are signed as the L1 action
+// [multiSigUser, outerSigner, action]. User-signed actions such as usdSend
+// are signed as their own typed data with payloadMultiSigUser and outerSigner
+// added after hyperliquidChain.
+func signMultiSigInner(
+	e *Exchange,
+	action action,
+	nonce int64,
+	privateKey *ecdsa.PrivateKey,
+	multiSigUser common.Address,
+	outerSigner common.Address,
+) (signature, error) {
+	if primaryType := action.getPrimaryType(); primaryType != "" {
 		sig, err := signMultiSigUserSignedActionPayload(
 			action,
 			privateKey,
-			payloadTypes,
+			action.getPayloadTypes(),
 			primaryType,
-			multisigUser,
+			multiSigUser,
 			outerSigner,
+			e.getSignatureChainId(),
 		)
 		if err != nil {
 			return signature{}, fmt.Errorf(
@@ -158,15 +334,14 @@ func SignMultisigPayload[T request](
 		return sig, nil
 	}
 
-	// L1 action - use signMultisigL1ActionPayload
 	sig, err := signMultisigL1ActionPayload(
 		action,
-		uint64(timestamp),
+		uint64(nonce),
 		privateKey,
 		e.vaultAddress,
 		e.expiresAfter,
 		e.rest.IsMainnet(),
-		multisigUser,
+		multiSigUser,
 		outerSigner,
 	)
 	if err != nil {
@@ -176,9 +351,104 @@ func SignMultisigPayload[T request](
 	return sig, nil
 }
 
+// SignedAction is an action signed by an Exchange that has not been posted
+type SignedAction struct {
+	// Action is the action exactly as it is signed and sent
+	Action    action
+	Nonce     int64
+	Signature signature
+	// Payload is the exact body that would be posted to /exchange
+	Payload map[string]any
+}
+
+// Preview converts request to an action and signs it with e's key, vault
+// address and expiresAfter exactly as the matching Exchange method would, but
+// does not post it. It is meant for inspecting or auditing an action before
+// it is broadcast, or for signing offline and posting the Payload elsewhere
+// with SubmitSignedPayload. Each call takes a fresh nonce.
+func Preview[T request](
+	ctx context.Context,
+	e *Exchange,
+	request T,
+) (SignedAction, error) {
+	return preview(ctx, e, request, e.nextNonce())
+}
+
+// preview signs request with nonce and builds its payload without posting it
+func preview[T request](
+	ctx context.Context,
+	e *Exchange,
+	request T,
+	nonce int64,
+) (SignedAction, error) {
+	action, err := request.toAction(ctx, e, nonce)
+	if err != nil {
+		return SignedAction{}, fmt.Errorf(
+			"failed to convert request to action: %w",
+			err,
+		)
+	}
+
+	sig, err := action.sign(e.privateKey, nonce, e)
+	if err != nil {
+		return SignedAction{}, fmt.Errorf("failed to sign action: %w", err)
+	}
+
+	return SignedAction{
+		Action:    action,
+		Nonce:     nonce,
+		Signature: sig,
+		Payload:   buildPayload(e, action, nonce, sig),
+	}, nil
+}
+
+// SubmitSignedPayload posts the Payload of a SignedAction to /exchange using
+// client and decodes the response. It does not sign anything, so it can run
+// on a machine that holds no keys.
+//
+// To sign offline, call Preview on the machine that holds the key, carry
+// SignedAction.Payload over as JSON, decode it into a map[string]any and pass
+// it here.
+func SubmitSignedPayload[T any](
+	ctx context.Context,
+	client rest.ClientInterface,
+	payload map[string]any,
+) (T, error) {
+	var zero T
+	actionType := payloadActionType(payload)
+
+	var body json.RawMessage
+	if err := client.Post(ctx, "/exchange", payload, &body); err != nil {
+		return zero, fmt.Errorf(
+			"failed to post to /exchange. Type: %v: %w",
+			actionType,
+			err,
+		)
+	}
+
+	return decodeResponse[T](actionType, body)
+}
+
+// payloadActionType returns the type of the action in payload, whether it
+// holds the action itself or its decoded JSON
+func payloadActionType(payload map[string]any) string {
+	switch a := payload["action"].(type) {
+	case action:
+		return a.getType()
+	case map[string]any:
+		actionType, _ := a["type"].(string)
+		return actionType
+	}
+	return ""
+}
+
 // DEFAULT_SLIPPAGE is the default max slippage for market orders (5%)
 const DEFAULT_SLIPPAGE = 0.05
 
+// DefaultMaxOrdersPerAction is the default number of orders BulkOrders sends
+// in a single order action
+const DefaultMaxOrdersPerAction = 1000
+
 // Order creates a single order
 func (e *Exchange) Order(
 	ctx context.Context,
@@ -195,7 +465,99 @@ func (e *Exchange) Order(
 	return OrderResponse(responses[0]), nil
 }
 
-// BulkOrders creates multiple orders in a single transaction
+// OrderIdempotent places an order that must carry a cloid. If submitting
+// fails in a way that leaves it unknown whether the order reached the
+// exchange, such as a timeout or dropped connection, the order is looked up
+// by cloid first. A known order is returned from that lookup, with a filled
+// order reported as Filled and any other status as Resting; the order is
+// only resubmitted if the exchange has no record of the cloid.
+//
+// An order recovered from the lookup has no average fill price, so
+// Filled.AvgPx is empty and Filled.TotalSz holds the original size.
+func (e *Exchange) OrderIdempotent(
+	ctx context.Context,
+	request orderRequest,
+	opts ...orderOption,
+) (OrderResponse, error) {
+	cloid, ok := request.cloid.Get()
+	if !ok {
+		return OrderResponse{}, fmt.Errorf("idempotent order requires a cloid")
+	}
+
+	resp, err := e.Order(ctx, request, opts...)
+	if err == nil || !isUncertainOrderError(err) {
+		return resp, err
+	}
+
+	cfg := orderConfig{}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	status, lookupErr := e.info.QueryOrderByCloid(
+		ctx,
+		e.orderUser(cfg),
+		cloid.Hex(),
+	)
+	if lookupErr != nil {
+		return OrderResponse{}, errors.Join(
+			err,
+			fmt.Errorf("failed to look up order by cloid: %w", lookupErr),
+		)
+	}
+	if status.Status != "order" {
+		return e.Order(ctx, request, opts...)
+	}
+
+	order := status.Order
+	if order.Status == info.OrderStatusFilled {
+		return OrderResponse{
+			Filled: &OrderStatusFilled{
+				TotalSz: order.Order.OrigSz.String(),
+				Oid:     order.Order.Oid,
+			},
+		}, nil
+	}
+	return OrderResponse{
+		Resting: &OrderStatusResting{
+			Oid:      order.Order.Oid,
+			ClientId: &cloid,
+			Status:   string(order.Status),
+		},
+	}, nil
+}
+
+// isUncertainOrderError reports whether err from Order leaves it unknown
+// whether the order reached the exchange. Only transport failures are
+// uncertain: network errors, timeouts, 5xx responses, a websocket that
+// dropped with the request in flight, and responses that cannot be decoded.
+// Rejections, 4xx responses and errors raised before anything is sent, such
+// as an unknown coin or an invalid tif, are definite.
+//
+// With Config.ExchangeRetry set, a retry after a lost response is rejected
+// for reusing the nonce. That rejection is a *StatusError, so it is treated
+// as definite even though the first attempt may have placed the order.
+func isUncertainOrderError(err error) bool {
+	var netErr net.Error
+	var serverErr *rest.ServerError
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.Is(err, context.DeadlineExceeded),
+		errors.Is(err, ws.ErrConnectionLost):
+		return true
+	case errors.As(err, &netErr), errors.As(err, &serverErr):
+		return true
+	case errors.As(err, &syntaxErr), errors.As(err, &typeErr):
+		return true
+	}
+	return false
+}
+
+// BulkOrders creates multiple orders in a single transaction. Ungrouped
+// batches larger than Config.MaxOrdersPerAction are split into several
+// transactions, submitted in order, and their statuses are returned in the
+// order of requests.
 func (e *Exchange) BulkOrders(
 	ctx context.Context,
 	requests []orderRequest,
@@ -206,14 +568,57 @@ func (e *Exchange) BulkOrders(
 		opt(&cfg)
 	}
 
-	return e.bulkOrders(ctx, requests, cfg.builder, cfg.grouping)
+	if err := e.validateBuilder(ctx, cfg); err != nil {
+		return BulkOrdersResponse{}, err
+	}
+
+	return e.bulkOrders(ctx, requests, cfg)
+}
+
+// validateBuilder checks the builder fee of an order config. The approved
+// max fee is only looked up when WithBuilderFeeCheck is set.
+func (e *Exchange) validateBuilder(ctx context.Context, cfg orderConfig) error {
+	builder, ok := cfg.builder.Get()
+	if !ok {
+		return nil
+	}
+
+	if builder.FeeAmount < 0 {
+		return fmt.Errorf(
+			"builder fee must not be negative, got %d",
+			builder.FeeAmount,
+		)
+	}
+
+	if !cfg.checkBuilderFee || e.info == nil {
+		return nil
+	}
+
+	maxFee, err := e.info.MaxBuilderFee(
+		ctx,
+		e.orderUser(cfg),
+		builder.PublicAddress,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to get max builder fee: %w", err)
+	}
+
+	if builder.FeeAmount > maxFee {
+		return fmt.Errorf(
+			"builder fee %d exceeds approved max %d for builder %s",
+			builder.FeeAmount,
+			maxFee,
+			builder.PublicAddress,
+		)
+	}
+
+	return nil
 }
 
 func (e *Exchange) bulkOrders(
 	ctx context.Context,
 	requests []orderRequest,
-	builder mo.Option[BuilderInfo],
-	grouping mo.Option[OrderGrouping],
+	cfg orderConfig,
 ) (BulkOrdersResponse, error) {
 	if len(requests) == 0 {
 		return BulkOrdersResponse{}, fmt.Errorf(
@@ -221,9 +626,28 @@ func (e *Exchange) bulkOrders(
 		)
 	}
 
+	// Orders sharing a cloid are rejected by the exchange, so catch them
+	// before submitting
+	seen := make(map[types.Cloid]int, len(requests))
+	for i, order := range requests {
+		cloid, ok := order.cloid.Get()
+		if !ok {
+			continue
+		}
+		if j, exists := seen[cloid]; exists {
+			return BulkOrdersResponse{}, fmt.Errorf(
+				"duplicate cloid %s in orders %d and %d",
+				cloid,
+				j,
+				i,
+			)
+		}
+		seen[cloid] = i
+	}
+
 	orderWires := make([]orderWire, len(requests))
 	for i, order := range requests {
-		assetId, ok := e.info.GetAsset(order.coin)
+		assetId, ok := e.getAsset(order.coin)
 		if !ok {
 			return BulkOrdersResponse{}, fmt.Errorf(
 				"unknown coin: %s",
@@ -242,10 +666,75 @@ func (e *Exchange) bulkOrders(
 		orderWires[i] = wire
 	}
 
-	action := ordersToAction(orderWires, builder, grouping)
+	// Grouped orders only make sense together, so they are never split
+	g := cfg.grouping.OrElse(OrderGroupingNA)
+	if g != OrderGroupingNA || e.maxOrders <= 0 ||
+		len(orderWires) <= e.maxOrders {
+		return e.postOrders(ctx, orderWires, cfg)
+	}
+
+	// Submit in chunks, each as its own action with a fresh nonce. Rejected
+	// orders are collected across chunks and reported by their index in
+	// requests.
+	orders := make(BulkOrdersResponse, 0, len(orderWires))
+	var failures []StatusFailure
+	for start := 0; start < len(orderWires); start += e.maxOrders {
+		end := min(start+e.maxOrders, len(orderWires))
+
+		resp, err := e.postOrders(ctx, orderWires[start:end], cfg)
+		var statusErr *StatusError
+		if errors.As(err, &statusErr) && len(statusErr.Failures) > 0 {
+			resp = statusErr.Orders
+			for _, f := range statusErr.Failures {
+				f.Index += start
+				failures = append(failures, f)
+			}
+		} else if err != nil {
+			return orders, fmt.Errorf(
+				"failed to submit orders %d to %d: %w",
+				start,
+				end-1,
+				err,
+			)
+		}
+		orders = append(orders, resp...)
+	}
+
+	if len(failures) > 0 {
+		return orders, &StatusError{
+			Action:   "order",
+			Failures: failures,
+			Orders:   orders,
+		}
+	}
+
+	return orders, nil
+}
+
+// postOrders signs and submits orderWires as a single order action. A vault
+// set with WithVault replaces the exchange's vault address in both the
+// signature and the payload.
+func (e *Exchange) postOrders(
+	ctx context.Context,
+	orderWires []orderWire,
+	cfg orderConfig,
+) (BulkOrdersResponse, error) {
+	action := ordersToAction(orderWires, cfg.builder, cfg.grouping)
+
+	vaultAddress := e.vaultAddress
+	if cfg.vault.IsPresent() {
+		vaultAddress = cfg.vault
+	}
 
 	timestamp := e.nextNonce()
-	sig, err := action.sign(e.privateKey, timestamp, e)
+	sig, err := signL1Action(
+		action,
+		uint64(timestamp),
+		e.privateKey,
+		vaultAddress,
+		e.expiresAfter,
+		e.rest.IsMainnet(),
+	)
 	if err != nil {
 		return BulkOrdersResponse{}, fmt.Errorf(
 			"failed to sign action: %w",
@@ -253,7 +742,121 @@ func (e *Exchange) bulkOrders(
 		)
 	}
 
-	return post[BulkOrdersResponse](ctx, e, action, timestamp, sig)
+	payload := buildPayload(e, action, timestamp, sig)
+	if v, ok := vaultAddress.Get(); ok {
+		payload["vaultAddress"] = v
+	}
+
+	return postPayload[BulkOrdersResponse](ctx, e, action.getType(), payload)
+}
+
+// PlaceWithTpSl places an entry order together with a take profit and/or a
+// stop loss, grouped as normalTpsl so the children only activate once the
+// entry fills. Either tp or sl may be nil, but not both. The children close
+// the entry's size on the opposite side and are reduce only; their tpsl field
+// is set from the argument they are passed in.
+func (e *Exchange) PlaceWithTpSl(
+	ctx context.Context,
+	entry orderRequest,
+	tp *TriggerOrder,
+	sl *TriggerOrder,
+	opts ...orderOption,
+) (BulkOrdersResponse, error) {
+	children, err := tpSlOrders(entry.coin, !entry.isBuy, entry.sz, tp, sl)
+	if err != nil {
+		return BulkOrdersResponse{}, err
+	}
+
+	cfg := orderConfig{}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if err := e.validateBuilder(ctx, cfg); err != nil {
+		return BulkOrdersResponse{}, err
+	}
+
+	cfg.grouping = mo.Some[OrderGrouping](OrderGroupingNormalTpSl)
+	return e.bulkOrders(ctx, append([]orderRequest{entry}, children...), cfg)
+}
+
+// SetPositionTpSl attaches a take profit and/or a stop loss to the current
+// position in coin, grouped as positionTpsl. Either tp or sl may be nil, but
+// not both. The orders are reduce only and sized to the full position, which
+// is read for the same address MarketClose acts on.
+func (e *Exchange) SetPositionTpSl(
+	ctx context.Context,
+	coin string,
+	tp *TriggerOrder,
+	sl *TriggerOrder,
+	opts ...orderOption,
+) (BulkOrdersResponse, error) {
+	cfg := orderConfig{}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	positionSize, err := e.positionSize(ctx, e.orderUser(cfg), coin)
+	if err != nil {
+		return BulkOrdersResponse{}, err
+	}
+	if positionSize == 0 {
+		return BulkOrdersResponse{}, fmt.Errorf(
+			"no position found for coin: %s",
+			coin,
+		)
+	}
+
+	// Closing orders trade against the position
+	isBuy := positionSize < 0
+	orders, err := tpSlOrders(coin, isBuy, math.Abs(positionSize), tp, sl)
+	if err != nil {
+		return BulkOrdersResponse{}, err
+	}
+
+	if err := e.validateBuilder(ctx, cfg); err != nil {
+		return BulkOrdersResponse{}, err
+	}
+
+	cfg.grouping = mo.Some[OrderGrouping](OrderGroupingPositionTpSl)
+	return e.bulkOrders(ctx, orders, cfg)
+}
+
+// tpSlOrders builds the reduce only trigger orders for a take profit and a
+// stop loss. A nil trigger is skipped. Trigger orders are priced at their
+// trigger price.
+func tpSlOrders(
+	coin string,
+	isBuy bool,
+	sz float64,
+	tp *TriggerOrder,
+	sl *TriggerOrder,
+) ([]orderRequest, error) {
+	if tp == nil && sl == nil {
+		return nil, fmt.Errorf("at least one of tp or sl is required")
+	}
+
+	var orders []orderRequest
+	for _, leg := range []struct {
+		trigger *TriggerOrder
+		tpsl    string
+	}{{tp, "tp"}, {sl, "sl"}} {
+		if leg.trigger == nil {
+			continue
+		}
+
+		trigger := *leg.trigger
+		trigger.TpSl = leg.tpsl
+		orders = append(orders, OrderRequest(
+			coin,
+			isBuy,
+			sz,
+			trigger.TriggerPx,
+			WithTriggerOrder(trigger),
+			WithReduceOnly(true),
+		))
+	}
+
+	return orders, nil
 }
 
 // ModifyOrder modifies a single order with Order ID
@@ -283,27 +886,309 @@ func (e *Exchange) modifySingleOrder(
 	if len(responses) == 0 {
 		return OrderResponse{}, fmt.Errorf("empty response from modify order")
 	}
-	return OrderResponse(responses[0]), nil
+	return responses[0].OrderResponse, nil
+}
+
+// ModifyOrderSize changes the size of a resting order, keeping everything
+// else about it. The order is looked up by oid and resubmitted with its
+// price, side, time in force, trigger, reduce-only flag and cloid unchanged.
+// coin is the name the order was placed with, such as "BTC" or "PURR/USDC".
+// An error is returned if the order is not found, is no longer open or is
+// for a different coin.
+func (e *Exchange) ModifyOrderSize(
+	ctx context.Context,
+	oid int64,
+	coin string,
+	newSz float64,
+) (OrderResponse, error) {
+	wireCoin, ok := e.info.NameToCoin(coin)
+	if !ok {
+		return OrderResponse{}, fmt.Errorf("unknown coin: %s", coin)
+	}
+
+	result, err := e.info.QueryOrderByOid(ctx, e.userAddress(), oid)
+	if err != nil {
+		return OrderResponse{}, fmt.Errorf("failed to query order: %w", err)
+	}
+	if result.Status != "order" {
+		return OrderResponse{}, fmt.Errorf("order %d not found", oid)
+	}
+	if result.Order.Status != info.OrderStatusOpen {
+		return OrderResponse{}, fmt.Errorf(
+			"order %d is %s, not open",
+			oid,
+			result.Order.Status,
+		)
+	}
+	if result.Order.Order.Coin != wireCoin {
+		return OrderResponse{}, fmt.Errorf(
+			"order %d is for %s, not %s",
+			oid,
+			result.Order.Order.Coin,
+			coin,
+		)
+	}
+
+	order, err := restingOrderRequest(coin, result.Order.Order, newSz)
+	if err != nil {
+		return OrderResponse{}, err
+	}
+
+	responses, err := e.bulkModify(ctx, []modifyRequest{
+		ModifyRequest(order, WithModifyOrderId(oid)),
+	})
+	if err != nil {
+		return OrderResponse{}, err
+	}
+	if len(responses) == 0 {
+		return OrderResponse{}, fmt.Errorf("empty response from modify order")
+	}
+	return responses[0], nil
+}
+
+// restingOrderRequest rebuilds the order request of a resting order with a
+// new size
+func restingOrderRequest(
+	coin string,
+	order info.OrderData,
+	sz float64,
+) (orderRequest, error) {
+	opts := []orderRequestOption{WithReduceOnly(order.ReduceOnly)}
+	if order.Cloid != nil {
+		opts = append(opts, WithCloid(*order.Cloid))
+	}
+
+	if order.IsTrigger {
+		tpsl := "sl"
+		if strings.HasPrefix(order.OrderType, "Take Profit") {
+			tpsl = "tp"
+		}
+		opts = append(opts, WithTriggerOrder(TriggerOrder{
+			IsMarket:  strings.HasSuffix(order.OrderType, "Market"),
+			TriggerPx: order.TriggerPx.Raw(),
+			TpSl:      tpsl,
+		}))
+	} else {
+		tif := order.Tif
+		if tif == "" {
+			tif = TifGtc
+		}
+		opts = append(opts, WithLimitOrder(LimitOrder{Tif: tif}))
+	}
+
+	return NewOrderRequest(
+		coin,
+		order.Side == "B",
+		sz,
+		order.LimitPx.Raw(),
+		opts...,
+	)
 }
 
-// BulkModifyOrders modifies multiple orders in a single transaction
+// BulkModifyOrders modifies multiple orders. batchModify cannot change an
+// order between limit and trigger, so each resting order is looked up first.
+// Modifies that keep the order type are sent as a single batchModify action,
+// while modifies that change it, or are made with WithModifyCancelReplace,
+// are sent as a cancel followed by a new order. The Path of each response
+// reports which of these was used.
+//
+// If cancel-and-replace modifies fail after their orders may have been
+// cancelled, a *ReplaceError is returned.
 func (e *Exchange) BulkModifyOrders(
 	ctx context.Context,
 	requests []modifyRequest,
-) (BulkOrdersResponse, error) {
+) (BulkModifyResponse, error) {
 	if len(requests) == 0 {
-		return BulkOrdersResponse{}, fmt.Errorf(
+		return BulkModifyResponse{}, fmt.Errorf(
 			"at least one modify request is required",
 		)
 	}
 
-	modifyWires := make([]modifyWire, len(requests))
+	var modifyIdxs, replaceIdxs []int
 	for i, modify := range requests {
-		assetId, ok := e.info.GetAsset(modify.Order.coin)
-		if !ok {
-			return BulkOrdersResponse{}, fmt.Errorf(
-				"unknown coin: %s",
-				modify.Order.coin,
+		changes := modify.cancelReplace
+		if !changes {
+			var err error
+			changes, err = e.modifyChangesOrderType(ctx, modify)
+			if err != nil {
+				return BulkModifyResponse{}, fmt.Errorf(
+					"failed to query order for modify %d: %w",
+					i,
+					err,
+				)
+			}
+		}
+
+		if changes {
+			replaceIdxs = append(replaceIdxs, i)
+		} else {
+			modifyIdxs = append(modifyIdxs, i)
+		}
+	}
+
+	results := make(BulkModifyResponse, len(requests))
+
+	if len(modifyIdxs) > 0 {
+		modifies := make([]modifyRequest, len(modifyIdxs))
+		for j, idx := range modifyIdxs {
+			modifies[j] = requests[idx]
+		}
+
+		responses, err := e.bulkModify(ctx, modifies)
+		if err != nil {
+			return BulkModifyResponse{}, err
+		}
+		if len(responses) != len(modifies) {
+			return BulkModifyResponse{}, fmt.Errorf(
+				"expected %d modify statuses, got %d",
+				len(modifies),
+				len(responses),
+			)
+		}
+
+		for j, idx := range modifyIdxs {
+			results[idx] = ModifyResponse{
+				OrderResponse: responses[j],
+				Path:          ModifyPathModify,
+			}
+		}
+	}
+
+	if len(replaceIdxs) > 0 {
+		replaces := make([]modifyRequest, len(replaceIdxs))
+		for j, idx := range replaceIdxs {
+			replaces[j] = requests[idx]
+		}
+
+		responses, err := e.cancelAndReplace(ctx, replaces)
+		if err != nil {
+			return BulkModifyResponse{}, err
+		}
+		if len(responses) != len(replaces) {
+			return BulkModifyResponse{}, fmt.Errorf(
+				"expected %d order statuses, got %d",
+				len(replaces),
+				len(responses),
+			)
+		}
+
+		for j, idx := range replaceIdxs {
+			results[idx] = ModifyResponse{
+				OrderResponse: responses[j],
+				Path:          ModifyPathCancelReplace,
+			}
+		}
+	}
+
+	return results, nil
+}
+
+// modifyChangesOrderType reports whether a modify turns a resting limit order
+// into a trigger order or vice versa. Orders that cannot be found are treated
+// as unchanged so that the exchange reports the error.
+func (e *Exchange) modifyChangesOrderType(
+	ctx context.Context,
+	modify modifyRequest,
+) (bool, error) {
+	user := e.userAddress()
+
+	var (
+		result info.QueryOrderResponse
+		err    error
+	)
+	if oid, ok := modify.Oid.Get(); ok {
+		result, err = e.info.QueryOrderByOid(ctx, user, oid)
+	} else if cloid, ok := modify.Cloid.Get(); ok {
+		result, err = e.info.QueryOrderByCloid(ctx, user, cloid.Hex())
+	} else {
+		return false, fmt.Errorf("either order ID or CLOID must be provided")
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if result.Status != "order" {
+		return false, nil
+	}
+
+	isTrigger := modify.Order.orderType.Trigger != nil
+	return result.Order.Order.IsTrigger != isTrigger, nil
+}
+
+// cancelAndReplace cancels the orders targeted by the modifies and places
+// their new orders
+func (e *Exchange) cancelAndReplace(
+	ctx context.Context,
+	requests []modifyRequest,
+) (BulkOrdersResponse, error) {
+	var cancels []cancelRequest
+	var cloidCancels []cancelByCloidRequest
+	orders := make([]orderRequest, len(requests))
+	for i, modify := range requests {
+		if oid, ok := modify.Oid.Get(); ok {
+			cancels = append(cancels, CancelRequest(modify.Order.coin, oid))
+		} else if cloid, ok := modify.Cloid.Get(); ok {
+			cloidCancels = append(
+				cloidCancels,
+				CancelByCloidRequest(modify.Order.coin, cloid),
+			)
+		}
+		orders[i] = modify.Order
+	}
+
+	var canceled BulkCancelResponse
+	if len(cancels) > 0 {
+		statuses, err := e.BulkCancel(ctx, cancels)
+		if err != nil {
+			return BulkOrdersResponse{}, &ReplaceError{
+				Canceled: canceled,
+				Err: fmt.Errorf(
+					"failed to cancel orders for replace: %w",
+					err,
+				),
+			}
+		}
+		canceled = append(canceled, statuses...)
+	}
+
+	if len(cloidCancels) > 0 {
+		statuses, err := e.BulkCancelByCloid(ctx, cloidCancels)
+		if err != nil {
+			return BulkOrdersResponse{}, &ReplaceError{
+				Canceled: canceled,
+				Err: fmt.Errorf(
+					"failed to cancel orders for replace: %w",
+					err,
+				),
+			}
+		}
+		canceled = append(canceled, statuses...)
+	}
+
+	placed, err := e.bulkOrders(ctx, orders, orderConfig{})
+	if err != nil {
+		return BulkOrdersResponse{}, &ReplaceError{
+			Canceled: canceled,
+			Err: fmt.Errorf(
+				"failed to place new orders for replace: %w",
+				err,
+			),
+		}
+	}
+	return placed, nil
+}
+
+func (e *Exchange) bulkModify(
+	ctx context.Context,
+	requests []modifyRequest,
+) (BulkOrdersResponse, error) {
+	modifyWires := make([]modifyWire, len(requests))
+	for i, modify := range requests {
+		assetId, ok := e.getAsset(modify.Order.coin)
+		if !ok {
+			return BulkOrdersResponse{}, fmt.Errorf(
+				"unknown coin: %s",
+				modify.Order.coin,
 			)
 		}
 
@@ -356,21 +1241,25 @@ func (e *Exchange) MarketOpen(
 		opt(&cfg)
 	}
 
-	px, err := e.getSlippagePrice(
+	nativeMarket, err := request.nativeMarket()
+	if err != nil {
+		return OrderResponse{}, err
+	}
+
+	px, tif, err := e.marketOrderPrice(
 		ctx,
 		request.coin,
 		request.isBuy,
-		request.slippage.OrElse(DEFAULT_SLIPPAGE),
+		nativeMarket,
+		request.slippage,
 		request.px,
 	)
 	if err != nil {
-		return OrderResponse{}, fmt.Errorf(
-			"failed to get slippage price: %w",
-			err,
-		)
+		return OrderResponse{}, err
 	}
 
-	// Market order is an aggressive limit order with IoC tif
+	// Market order is an aggressive limit order with IoC tif, unless the
+	// native market tif is requested
 	return e.Order(
 		ctx,
 		OrderRequest(
@@ -378,11 +1267,12 @@ func (e *Exchange) MarketOpen(
 			request.isBuy,
 			request.sz,
 			px,
-			WithLimitOrder(LimitOrder{Tif: "Ioc"}),
-			WithReduceOnly(false),
+			WithLimitOrder(LimitOrder{Tif: tif}),
+			WithReduceOnly(request.reduceOnly),
 			withCloid(request.cloid),
 		),
 		withBuilderInfo(cfg.builder),
+		withVault(cfg.vault),
 	)
 }
 
@@ -397,69 +1287,28 @@ func (e *Exchange) MarketClose(
 		opt(&cfg)
 	}
 
-	address := crypto.PubkeyToAddress(e.privateKey.PublicKey)
-
-	if a, ok := e.accountAddress.Get(); ok {
-		address = a
-	}
-	if v, ok := e.vaultAddress.Get(); ok {
-		address = v
-	}
+	address := e.orderUser(cfg)
 
-	// Get user state to find the position
-	dex := utils.GetDex(request.coin)
-	userState, err := e.info.UserState(ctx, address, dex)
+	closeSz, isBuy, err := e.closeSize(ctx, address, request.coin, request.sz)
 	if err != nil {
-		return OrderResponse{}, fmt.Errorf("failed to get user state: %w", err)
-	}
-
-	// Find the position for this coin
-	var position *info.Position
-	var positionSize float64
-	if userState.AssetPositions != nil {
-		for _, assetPos := range userState.AssetPositions {
-			if assetPos.Position.Coin == request.coin {
-				position = &assetPos.Position
-				positionSize = float64(assetPos.Position.Szi)
-				break
-			}
-		}
-	}
-
-	if position == nil {
-		return OrderResponse{}, fmt.Errorf(
-			"no position found for coin: %s",
-			request.coin,
-		)
-	}
-
-	// Determine size to close
-	var closeSz float64
-	if sz, ok := request.sz.Get(); ok {
-		closeSz = sz
-	} else {
-		// Close entire position
-		closeSz = math.Abs(positionSize)
+		return OrderResponse{}, err
 	}
 
-	// Determine buy/sell direction (opposite of current position)
-	isBuy := positionSize < 0
-
-	px, err := e.getSlippagePrice(
+	px, tif, err := e.marketOrderPrice(
 		ctx,
 		request.coin,
 		isBuy,
-		request.slippage.OrElse(DEFAULT_SLIPPAGE),
+		request.nativeMarket,
+		request.slippage,
 		request.px,
 	)
 	if err != nil {
-		return OrderResponse{}, fmt.Errorf(
-			"failed to get slippage price: %w",
-			err,
-		)
+		return OrderResponse{}, err
 	}
 
-	// Market order is an aggressive limit order with IoC tif
+	// Market order is an aggressive limit order with IoC tif, unless the
+	// native market tif is requested. The close is reduce only so it can never
+	// flip the position
 	return e.Order(
 		ctx,
 		OrderRequest(
@@ -467,14 +1316,132 @@ func (e *Exchange) MarketClose(
 			isBuy,
 			closeSz,
 			px,
-			WithLimitOrder(LimitOrder{Tif: "Ioc"}),
-			WithReduceOnly(false),
+			WithLimitOrder(LimitOrder{Tif: tif}),
+			WithReduceOnly(true),
 			withCloid(request.cloid),
 		),
 		withBuilderInfo(cfg.builder),
+		withVault(cfg.vault),
 	)
 }
 
+// PositionSize returns the signed position size for a coin. Longs are
+// positive, shorts are negative and a flat position returns 0. The position
+// is read for the same address MarketClose acts on.
+func (e *Exchange) PositionSize(
+	ctx context.Context,
+	coin string,
+) (float64, error) {
+	return e.positionSize(ctx, e.userAddress(), coin)
+}
+
+// positionSize returns the signed size of user's position in coin
+func (e *Exchange) positionSize(
+	ctx context.Context,
+	user common.Address,
+	coin string,
+) (float64, error) {
+	dex := utils.GetDex(coin)
+	userState, err := e.info.UserState(ctx, user, dex)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get user state: %w", err)
+	}
+
+	for _, assetPos := range userState.AssetPositions {
+		if assetPos.Position.Coin == coin {
+			return assetPos.Position.Szi.Raw(), nil
+		}
+	}
+
+	return 0, nil
+}
+
+// closeSize returns the size and side of a reduce only order closing user's
+// position in coin. The size defaults to the whole position and is rounded
+// down to the coin's szDecimals. An error is returned when there is no
+// position or the size rounds down to zero.
+func (e *Exchange) closeSize(
+	ctx context.Context,
+	user common.Address,
+	coin string,
+	sz mo.Option[float64],
+) (float64, bool, error) {
+	positionSize, err := e.positionSize(ctx, user, coin)
+	if err != nil {
+		return 0, false, err
+	}
+	if positionSize == 0 {
+		return 0, false, fmt.Errorf("no position found for coin: %s", coin)
+	}
+
+	closeSz, err := e.floorSize(coin, sz.OrElse(math.Abs(positionSize)))
+	if err != nil {
+		return 0, false, err
+	}
+	if closeSz <= 0 {
+		return 0, false, fmt.Errorf(
+			"close size for %s rounds down to zero",
+			coin,
+		)
+	}
+
+	// Closing orders trade against the position
+	return closeSz, positionSize < 0, nil
+}
+
+// TwapOrder starts a TWAP order. The returned response holds the TWAP id
+// which can be used to cancel it
+func (e *Exchange) TwapOrder(
+	ctx context.Context,
+	request twapOrderRequest,
+) (TwapOrderResponse, error) {
+	action, err := request.toAction(ctx, e)
+	if err != nil {
+		return TwapOrderResponse{}, fmt.Errorf(
+			"failed to convert request to action: %w",
+			err,
+		)
+	}
+
+	timestamp := e.nextNonce()
+	sig, err := action.sign(e.privateKey, timestamp, e)
+	if err != nil {
+		return TwapOrderResponse{}, fmt.Errorf(
+			"failed to sign action: %w",
+			err,
+		)
+	}
+
+	return post[TwapOrderResponse](ctx, e, action, timestamp, sig)
+}
+
+// TwapCancel cancels a running TWAP order
+func (e *Exchange) TwapCancel(
+	ctx context.Context,
+	coin string,
+	twapId int64,
+) (TwapCancelResponse, error) {
+	req := TwapCancelRequest(coin, twapId)
+	action, err := req.toAction(ctx, e)
+	if err != nil {
+		return TwapCancelResponse{}, fmt.Errorf(
+			"failed to convert request to action: %w",
+			err,
+		)
+	}
+
+	timestamp := e.nextNonce()
+	sig, err := action.sign(e.privateKey, timestamp, e)
+	if err != nil {
+		return TwapCancelResponse{}, fmt.Errorf(
+			"failed to sign action: %w",
+			err,
+		)
+	}
+
+	return post[TwapCancelResponse](ctx, e, action, timestamp, sig)
+}
+
 // Cancel cancels a single order by order ID
 func (e *Exchange) Cancel(
 	ctx context.Context,
@@ -485,7 +1452,7 @@ func (e *Exchange) Cancel(
 		return CancelResponse{}, err
 	}
 	if len(responses) == 0 {
-		return CancelResponse{}, fmt.Errorf("empty response from modify order")
+		return CancelResponse{}, fmt.Errorf("empty response from cancel")
 	}
 	return CancelResponse(responses[0]), nil
 }
@@ -504,7 +1471,7 @@ func (e *Exchange) BulkCancel(
 	cancelWires := make([]cancelWire, len(cancels))
 	for i, cancel := range cancels {
 		// Get asset ID for this cancel's coin
-		assetId, ok := e.info.GetAsset(cancel.Coin)
+		assetId, ok := e.getAsset(cancel.Coin)
 		if !ok {
 			return BulkCancelResponse{}, fmt.Errorf(
 				"unknown coin: %s",
@@ -529,17 +1496,80 @@ func (e *Exchange) BulkCancel(
 	return post[BulkCancelResponse](ctx, e, action, timestamp, sig)
 }
 
-// CancelByCloid cancels an order by its client order ID.
+// CancelAll cancels every open order of the account. If coin is set, only
+// orders for that coin are canceled; it may be a pair name such as
+// "PURR/USDC" or a builder dex coin such as "xyz:FOO". Without a coin, orders
+// on the main perp dex and spot are canceled. The cancels are sent in actions
+// of at most MaxOrdersPerAction each, and if one fails the statuses of the
+// earlier ones are returned with the error. No action is sent if there is
+// nothing to cancel, in which case the response is empty.
+func (e *Exchange) CancelAll(
+	ctx context.Context,
+	coin *string,
+) (BulkCancelResponse, error) {
+	dex := ""
+	wireCoin := ""
+	if coin != nil {
+		dex = utils.GetDex(*coin)
+		wireCoin = *coin
+		if c, ok := e.info.NameToCoin(*coin); ok {
+			wireCoin = c
+		}
+	}
+
+	orders, err := e.info.OpenOrders(ctx, e.userAddress(), dex)
+	if err != nil {
+		return BulkCancelResponse{}, fmt.Errorf(
+			"failed to fetch open orders: %w",
+			err,
+		)
+	}
+
+	var cancels []cancelRequest
+	for _, order := range orders {
+		if coin != nil && order.Coin != wireCoin {
+			continue
+		}
+		cancels = append(cancels, CancelRequest(order.Coin, order.Oid))
+	}
+	if len(cancels) == 0 {
+		return BulkCancelResponse{}, nil
+	}
+	if e.maxOrders <= 0 || len(cancels) <= e.maxOrders {
+		return e.BulkCancel(ctx, cancels)
+	}
+
+	// Cancel in chunks, each as its own action with a fresh nonce
+	statuses := make(BulkCancelResponse, 0, len(cancels))
+	for start := 0; start < len(cancels); start += e.maxOrders {
+		end := min(start+e.maxOrders, len(cancels))
+
+		resp, err := e.BulkCancel(ctx, cancels[start:end])
+		if err != nil {
+			return statuses, fmt.Errorf(
+				"failed to cancel orders %d to %d: %w",
+				start,
+				end-1,
+				err,
+			)
+		}
+		statuses = append(statuses, resp...)
+	}
+
+	return statuses, nil
+}
+
+// CancelByCloid cancels an order by its client order ID
 func (e *Exchange) CancelByCloid(
 	ctx context.Context,
 	request cancelByCloidRequest,
-) (any, error) {
+) (CancelResponse, error) {
 	responses, err := e.BulkCancelByCloid(ctx, []cancelByCloidRequest{request})
 	if err != nil {
 		return CancelResponse{}, err
 	}
 	if len(responses) == 0 {
-		return CancelResponse{}, fmt.Errorf("empty response from modify order")
+		return CancelResponse{}, fmt.Errorf("empty response from cancel")
 	}
 	return CancelResponse(responses[0]), nil
 }
@@ -557,7 +1587,7 @@ func (e *Exchange) BulkCancelByCloid(
 	cancelWires := make([]cancelByCloidWire, len(cancels))
 	for i, cancel := range cancels {
 		// Get asset ID for this cancel's coin
-		assetId, ok := e.info.GetAsset(cancel.Coin)
+		assetId, ok := e.getAsset(cancel.Coin)
 		if !ok {
 			return BulkCancelResponse{}, fmt.Errorf(
 				"unknown coin: %s",
@@ -730,6 +1760,28 @@ func (e *Exchange) CreateSubAccount(
 	)
 }
 
+// ClaimRewards claims the staking and referral rewards that have accrued to
+// the account
+func (e *Exchange) ClaimRewards(ctx context.Context) (UpdateResponse, error) {
+	req := ClaimRewardsRequest()
+	action, err := req.toAction(ctx, e)
+	if err != nil {
+		return UpdateResponse{}, fmt.Errorf(
+			"failed to convert request to action: %w",
+			err,
+		)
+	}
+
+	timestamp := e.nextNonce()
+	sig, err := action.sign(e.privateKey, timestamp, e)
+
+	if err != nil {
+		return UpdateResponse{}, fmt.Errorf("failed to sign action: %w", err)
+	}
+
+	return post[UpdateResponse](ctx, e, action, timestamp, sig)
+}
+
 func (e *Exchange) UsdClassTransfer(
 	ctx context.Context,
 	amount float64,
@@ -932,7 +1984,7 @@ func (e *Exchange) SpotTransfer(
 func (e *Exchange) TokenDelegate(
 	ctx context.Context,
 	validator common.Address,
-	wei int64,
+	wei *big.Int,
 	isUndelegate bool,
 ) (UpdateResponse, error) {
 	timestamp := e.nextNonce()
@@ -977,6 +2029,31 @@ func (e *Exchange) WithdrawFromBridge(
 	return post[UpdateResponse](ctx, e, action, timestamp, sig)
 }
 
+// BuildSignedWithdraw builds and signs a withdraw3 payload without submitting
+// it. The nonce is supplied by the caller, so the payload can be signed on an
+// offline machine and posted to /exchange later from an online one.
+func (e *Exchange) BuildSignedWithdraw(
+	amount float64,
+	destination common.Address,
+	nonce int64,
+) (map[string]any, error) {
+	req := WithdrawFromBridgeRequest(amount, destination)
+	action, err := req.toAction(context.Background(), e, nonce)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to convert request to action: %w",
+			err,
+		)
+	}
+
+	sig, err := action.sign(e.privateKey, nonce, e)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign action: %w", err)
+	}
+
+	return buildPayload(e, action, nonce, sig), nil
+}
+
 // ApproveAgent approves an agent and returns the response and the agent's
 // private key.
 func (e *Exchange) ApproveAgent(
@@ -1052,7 +2129,8 @@ func (e *Exchange) ConvertToMultiSigUser(
 	ctx context.Context,
 	request convertToMultiSigUserRequest,
 ) (UpdateResponse, error) {
-	action, err := request.toAction(ctx, e)
+	timestamp := e.nextNonce()
+	action, err := request.toAction(ctx, e, timestamp)
 	if err != nil {
 		return UpdateResponse{}, fmt.Errorf(
 			"failed to convert request to action: %w",
@@ -1060,7 +2138,6 @@ func (e *Exchange) ConvertToMultiSigUser(
 		)
 	}
 
-	timestamp := e.nextNonce()
 	sig, err := action.sign(e.privateKey, timestamp, e)
 	if err != nil {
 		return UpdateResponse{}, fmt.Errorf("failed to sign action: %w", err)
@@ -1069,78 +2146,149 @@ func (e *Exchange) ConvertToMultiSigUser(
 	return post[UpdateResponse](ctx, e, action, timestamp, sig)
 }
 
-// // SpotDeployRegisterToken registers a token for spot deployment
-// func (e *Exchange) SpotDeployRegisterToken(
-// 	ctx context.Context,
-// 	tokenName string,
-// 	szDecimals int64,
-// 	weiDecimals int64,
-// 	maxGas int64,
-// 	fullName string,
-// ) (Response, error) {
-//  timestamp := e.nextNonce()
-// 	action := map[string]any{
-// 		"type": "spotDeploy",
-// 		"registerToken2": map[string]any{
-// 			"spec": map[string]any{
-// 				"name":        tokenName,
-// 				"szDecimals":  szDecimals,
-// 				"weiDecimals": weiDecimals,
-// 			},
-// 			"maxGas":   maxGas,
-// 			"fullName": fullName,
-// 		},
-// 	}
-// 	sig, err := e.signL1Action(action, uint64(timestamp))
-// 	if err != nil {
-// 		return Response{}, fmt.Errorf("failed to sign action: %w", err)
-// 	}
-
-// 	return e.post(ctx, action, timestamp, sig)
-// }
-
-// // SpotDeployUserGenesis performs user genesis for spot deployment
-// func (e *Exchange) SpotDeployUserGenesis(
-// 	ctx context.Context,
-// 	token int64,
-// 	userAndWei []UserWeiPair,
-// 	existingTokenAndWei []TokenWeiPair,
-// ) (Response, error) {
-//  timestamp := e.nextNonce()
+// SpotDeployRegisterToken registers a token for spot deployment
+func (e *Exchange) SpotDeployRegisterToken(
+	ctx context.Context,
+	tokenName string,
+	szDecimals int64,
+	weiDecimals int64,
+	maxGas int64,
+	fullName string,
+) (UpdateResponse, error) {
+	req := SpotDeployRegisterTokenRequest(
+		tokenName,
+		szDecimals,
+		weiDecimals,
+		maxGas,
+		fullName,
+	)
+	action, err := req.toAction(ctx, e)
+	if err != nil {
+		return UpdateResponse{}, fmt.Errorf(
+			"failed to convert request to action: %w",
+			err,
+		)
+	}
 
-// 	// Convert userAndWei to lowercase addresses and string wei
-// 	userAndWeiAction := make([][]string, len(userAndWei))
-// 	for i, pair := range userAndWei {
-// 		userAndWeiAction[i] = []string{
-// 			strings.ToLower(pair.User.String()),
-// 			pair.Wei.String(),
-// 		}
-// 	}
+	timestamp := e.nextNonce()
+	sig, err := action.sign(e.privateKey, timestamp, e)
+	if err != nil {
+		return UpdateResponse{}, fmt.Errorf("failed to sign action: %w", err)
+	}
 
-// 	// Convert existingTokenAndWei to action format
-// 	existingTokenAndWeiAction := make([][]any, len(existingTokenAndWei))
-// 	for i, pair := range existingTokenAndWei {
-// 		existingTokenAndWeiAction[i] = []any{
-// 			pair.Token,
-// 			pair.Wei.String(),
-// 		}
-// 	}
+	return post[UpdateResponse](ctx, e, action, timestamp, sig)
+}
 
-// 	action := map[string]any{
-// 		"type": "spotDeploy",
-// 		"userGenesis": map[string]any{
-// 			"token":               token,
-// 			"userAndWei":          userAndWeiAction,
-// 			"existingTokenAndWei": existingTokenAndWeiAction,
-// 		},
-// 	}
-// 	sig, err := e.signL1Action(action, uint64(timestamp))
-// 	if err != nil {
-// 		return Response{}, fmt.Errorf("failed to sign action: %w", err)
-// 	}
+// SpotDeployUserGenesis performs user genesis for spot deployment
+func (e *Exchange) SpotDeployUserGenesis(
+	ctx context.Context,
+	token int64,
+	userAndWei []UserWeiPair,
+	existingTokenAndWei []TokenWeiPair,
+) (UpdateResponse, error) {
+	req := SpotDeployUserGenesisRequest(
+		token,
+		userAndWei,
+		existingTokenAndWei,
+	)
+	action, err := req.toAction(ctx, e)
+	if err != nil {
+		return UpdateResponse{}, fmt.Errorf(
+			"failed to convert request to action: %w",
+			err,
+		)
+	}
 
-// 	return e.post(ctx, action, timestamp, sig)
-// }
+	timestamp := e.nextNonce()
+	sig, err := action.sign(e.privateKey, timestamp, e)
+	if err != nil {
+		return UpdateResponse{}, fmt.Errorf("failed to sign action: %w", err)
+	}
+
+	return post[UpdateResponse](ctx, e, action, timestamp, sig)
+}
+
+// SpotDeployGenesis sets up genesis configuration for a token
+func (e *Exchange) SpotDeployGenesis(
+	ctx context.Context,
+	token int64,
+	maxSupply string,
+	noHyperliquidity bool,
+) (UpdateResponse, error) {
+	req := SpotDeployGenesisRequest(token, maxSupply, noHyperliquidity)
+	action, err := req.toAction(ctx, e)
+	if err != nil {
+		return UpdateResponse{}, fmt.Errorf(
+			"failed to convert request to action: %w",
+			err,
+		)
+	}
+
+	timestamp := e.nextNonce()
+	sig, err := action.sign(e.privateKey, timestamp, e)
+	if err != nil {
+		return UpdateResponse{}, fmt.Errorf("failed to sign action: %w", err)
+	}
+
+	return post[UpdateResponse](ctx, e, action, timestamp, sig)
+}
+
+// SpotDeployRegisterSpot registers a spot trading pair
+func (e *Exchange) SpotDeployRegisterSpot(
+	ctx context.Context,
+	baseToken int64,
+	quoteToken int64,
+) (UpdateResponse, error) {
+	req := SpotDeployRegisterSpotRequest(baseToken, quoteToken)
+	action, err := req.toAction(ctx, e)
+	if err != nil {
+		return UpdateResponse{}, fmt.Errorf(
+			"failed to convert request to action: %w",
+			err,
+		)
+	}
+
+	timestamp := e.nextNonce()
+	sig, err := action.sign(e.privateKey, timestamp, e)
+	if err != nil {
+		return UpdateResponse{}, fmt.Errorf("failed to sign action: %w", err)
+	}
+
+	return post[UpdateResponse](ctx, e, action, timestamp, sig)
+}
+
+// SpotDeployRegisterHyperliquidity registers hyperliquidity market maker
+func (e *Exchange) SpotDeployRegisterHyperliquidity(
+	ctx context.Context,
+	spot int64,
+	startPx float64,
+	orderSz float64,
+	nOrders int64,
+	nSeededLevels *int64,
+) (UpdateResponse, error) {
+	req := SpotDeployRegisterHyperliquidityRequest(
+		spot,
+		startPx,
+		orderSz,
+		nOrders,
+		nSeededLevels,
+	)
+	action, err := req.toAction(ctx, e)
+	if err != nil {
+		return UpdateResponse{}, fmt.Errorf(
+			"failed to convert request to action: %w",
+			err,
+		)
+	}
+
+	timestamp := e.nextNonce()
+	sig, err := action.sign(e.privateKey, timestamp, e)
+	if err != nil {
+		return UpdateResponse{}, fmt.Errorf("failed to sign action: %w", err)
+	}
+
+	return post[UpdateResponse](ctx, e, action, timestamp, sig)
+}
 
 // // spotDeployTokenActionInner is a helper for simple spot deploy token
 // actions
@@ -1210,82 +2358,6 @@ func (e *Exchange) ConvertToMultiSigUser(
 // 	return e.post(ctx, action, timestamp, sig)
 // }
 
-// // SpotDeployGenesis sets up genesis configuration for a token
-// func (e *Exchange) SpotDeployGenesis(
-// 	ctx context.Context,
-// 	token int64,
-// 	maxSupply string,
-// 	noHyperliquidity bool,
-// ) (Response, error) {
-//  timestamp := e.nextNonce()
-// 	genesis := map[string]any{
-// 		"token":     token,
-// 		"maxSupply": maxSupply,
-// 	}
-// 	if noHyperliquidity {
-// 		genesis["noHyperliquidity"] = true
-// 	}
-// 	action := map[string]any{
-// 		"type":    "spotDeploy",
-// 		"genesis": genesis,
-// 	}
-// 	sig, err := e.signL1Action(action, uint64(timestamp))
-// 	if err != nil {
-// 		return Response{}, fmt.Errorf("failed to sign action: %w", err)
-// 	}
-// 	return e.post(ctx, action, timestamp, sig)
-// }
-
-// // SpotDeployRegisterSpot registers a spot trading pair
-// func (e *Exchange) SpotDeployRegisterSpot(
-// 	ctx context.Context,
-// 	baseToken int64,
-// 	quoteToken int64,
-// ) (Response, error) {
-//  timestamp := e.nextNonce()
-// 	action := map[string]any{
-// 		"type": "spotDeploy",
-// 		"registerSpot": map[string]any{
-// 			"tokens": []int64{baseToken, quoteToken},
-// 		},
-// 	}
-// 	sig, err := e.signL1Action(action, uint64(timestamp))
-// 	if err != nil {
-// 		return Response{}, fmt.Errorf("failed to sign action: %w", err)
-// 	}
-// 	return e.post(ctx, action, timestamp, sig)
-// }
-
-// // SpotDeployRegisterHyperliquidity registers hyperliquidity market maker
-// func (e *Exchange) SpotDeployRegisterHyperliquidity(
-// 	ctx context.Context,
-// 	spot int64,
-// 	startPx float64,
-// 	orderSz float64,
-// 	nOrders int64,
-// 	nSeededLevels *int64,
-// ) (Response, error) {
-//  timestamp := e.nextNonce()
-// 	registerHyperliquidity := map[string]any{
-// 		"spot":    spot,
-// 		"startPx": fmt.Sprintf("%v", startPx),
-// 		"orderSz": fmt.Sprintf("%v", orderSz),
-// 		"nOrders": nOrders,
-// 	}
-// 	if nSeededLevels != nil {
-// 		registerHyperliquidity["nSeededLevels"] = *nSeededLevels
-// 	}
-// 	action := map[string]any{
-// 		"type":                   "spotDeploy",
-// 		"registerHyperliquidity": registerHyperliquidity,
-// 	}
-// 	sig, err := e.signL1Action(action, uint64(timestamp))
-// 	if err != nil {
-// 		return Response{}, fmt.Errorf("failed to sign action: %w", err)
-// 	}
-// 	return e.post(ctx, action, timestamp, sig)
-// }
-
 // // SpotDeploySetDeployerTradingFeeShare sets the deployer trading fee share
 // func (e *Exchange) SpotDeploySetDeployerTradingFeeShare(
 // 	ctx context.Context,
@@ -1307,282 +2379,190 @@ func (e *Exchange) ConvertToMultiSigUser(
 // 	return e.post(ctx, action, timestamp, sig)
 // }
 
-// // PerpDeploySchemaInput represents schema input for perp deployment
-// type PerpDeploySchemaInput struct {
-// 	FullName        string
-// 	CollateralToken string
-// 	OracleUpdater   *common.Address
-// }
-
-// // PerpDeployRegisterAsset registers a new perpetual asset
-// func (e *Exchange) PerpDeployRegisterAsset(
-// 	ctx context.Context,
-// 	dex string,
-// 	maxGas *int64,
-// 	coin string,
-// 	szDecimals int64,
-// 	oraclePx string,
-// 	marginTableID int64,
-// 	onlyIsolated bool,
-// 	schema *PerpDeploySchemaInput,
-// ) (Response, error) {
-//  timestamp := e.nextNonce()
+// PerpDeployRegisterAsset registers a new perpetual asset
+func (e *Exchange) PerpDeployRegisterAsset(
+	ctx context.Context,
+	dex string,
+	maxGas *int64,
+	coin string,
+	szDecimals int64,
+	oraclePx string,
+	marginTableID int64,
+	onlyIsolated bool,
+	schema *PerpDeploySchemaInput,
+) (UpdateResponse, error) {
+	req := PerpDeployRegisterAssetRequest(
+		dex,
+		maxGas,
+		coin,
+		szDecimals,
+		oraclePx,
+		marginTableID,
+		onlyIsolated,
+		schema,
+	)
+	action, err := req.toAction(ctx, e)
+	if err != nil {
+		return UpdateResponse{}, fmt.Errorf(
+			"failed to convert request to action: %w",
+			err,
+		)
+	}
 
-// 	var schemaWire map[string]any
-// 	if schema != nil {
-// 		schemaWire = map[string]any{
-// 			"fullName":        schema.FullName,
-// 			"collateralToken": schema.CollateralToken,
-// 		}
-// 		if schema.OracleUpdater != nil {
-// 			schemaWire["oracleUpdater"] = strings.ToLower(
-// 				schema.OracleUpdater.String(),
-// 			)
-// 		} else {
-// 			schemaWire["oracleUpdater"] = nil
-// 		}
-// 	}
+	timestamp := e.nextNonce()
+	sig, err := action.sign(e.privateKey, timestamp, e)
+	if err != nil {
+		return UpdateResponse{}, fmt.Errorf("failed to sign action: %w", err)
+	}
 
-// 	action := map[string]any{
-// 		"type": "perpDeploy",
-// 		"registerAsset": map[string]any{
-// 			"maxGas": maxGas,
-// 			"assetRequest": map[string]any{
-// 				"coin":          coin,
-// 				"szDecimals":    szDecimals,
-// 				"oraclePx":      oraclePx,
-// 				"marginTableId": marginTableID,
-// 				"onlyIsolated":  onlyIsolated,
-// 			},
-// 			"dex":    dex,
-// 			"schema": schemaWire,
-// 		},
-// 	}
-// 	sig, err := e.signL1Action(action, uint64(timestamp))
-// 	if err != nil {
-// 		return Response{}, fmt.Errorf("failed to sign action: %w", err)
-// 	}
-// 	return e.post(ctx, action, timestamp, sig)
-// }
+	return post[UpdateResponse](ctx, e, action, timestamp, sig)
+}
 
-// // PerpDeploySetOracle sets oracle prices for a DEX
-// func (e *Exchange) PerpDeploySetOracle(
-// 	ctx context.Context,
-// 	dex string,
-// 	oraclePxs map[string]string,
-// 	allMarkPxs []map[string]string,
-// 	externalPerpPxs map[string]string,
-// ) (Response, error) {
-//  timestamp := e.nextNonce()
+// PerpDeploySetOracle sets oracle prices for a DEX
+func (e *Exchange) PerpDeploySetOracle(
+	ctx context.Context,
+	dex string,
+	oraclePxs map[string]string,
+	allMarkPxs []map[string]string,
+	externalPerpPxs map[string]string,
+) (UpdateResponse, error) {
+	req := PerpDeploySetOracleRequest(
+		dex,
+		oraclePxs,
+		allMarkPxs,
+		externalPerpPxs,
+	)
+	action, err := req.toAction(ctx, e)
+	if err != nil {
+		return UpdateResponse{}, fmt.Errorf(
+			"failed to convert request to action: %w",
+			err,
+		)
+	}
 
-// 	// Convert maps to sorted key-value pairs
-// 	oraclePxsWire := sortStringMap(oraclePxs)
-// 	markPxsWire := make([][][]string, len(allMarkPxs))
-// 	for i, markPxs := range allMarkPxs {
-// 		markPxsWire[i] = sortStringMap(markPxs)
-// 	}
-// 	externalPerpPxsWire := sortStringMap(externalPerpPxs)
+	timestamp := e.nextNonce()
+	sig, err := action.sign(e.privateKey, timestamp, e)
+	if err != nil {
+		return UpdateResponse{}, fmt.Errorf("failed to sign action: %w", err)
+	}
 
-// 	action := map[string]any{
-// 		"type": "perpDeploy",
-// 		"setOracle": map[string]any{
-// 			"dex":             dex,
-// 			"oraclePxs":       oraclePxsWire,
-// 			"markPxs":         markPxsWire,
-// 			"externalPerpPxs": externalPerpPxsWire,
-// 		},
-// 	}
-// 	sig, err := e.signL1Action(action, uint64(timestamp))
-// 	if err != nil {
-// 		return Response{}, fmt.Errorf("failed to sign action: %w", err)
-// 	}
-// 	return e.post(ctx, action, timestamp, sig)
-// }
+	return post[UpdateResponse](ctx, e, action, timestamp, sig)
+}
 
-// // cSignerInner is a helper for c signer actions
-// func (e *Exchange) cSignerInner(
-// 	ctx context.Context,
-// 	variant string,
-// ) (Response, error) {
-//  timestamp := e.nextNonce()
-// 	action := map[string]any{
-// 		"type":  "CSignerAction",
-// 		variant: nil,
-// 	}
-// 	sig, err := e.signL1Action(action, uint64(timestamp))
-// 	if err != nil {
-// 		return Response{}, fmt.Errorf("failed to sign action: %w", err)
-// 	}
-// 	return e.post(ctx, action, timestamp, sig)
-// }
+// CSignerJailSelf jails the validator signer, for example during
+// maintenance
+func (e *Exchange) CSignerJailSelf(
+	ctx context.Context,
+) (UpdateResponse, error) {
+	return e.sendUpdate(ctx, CSignerJailSelfRequest())
+}
 
-// // CSignerUnjailSelf unjails the signer
-// func (e *Exchange) CSignerUnjailSelf(ctx context.Context) (Response, error) {
-// 	return e.cSignerInner(ctx, "unjailSelf")
-// }
+// CSignerUnjailSelf unjails the validator signer
+func (e *Exchange) CSignerUnjailSelf(
+	ctx context.Context,
+) (UpdateResponse, error) {
+	return e.sendUpdate(ctx, CSignerUnjailSelfRequest())
+}
 
-// // CSignerJailSelf jails the signer
-// func (e *Exchange) CSignerJailSelf(ctx context.Context) (Response, error) {
-// 	return e.cSignerInner(ctx, "jailSelf")
-// }
+// sendUpdate signs and posts an action that has no response data beyond its
+// status
+func (e *Exchange) sendUpdate(
+	ctx context.Context,
+	req request,
+) (UpdateResponse, error) {
+	timestamp := e.nextNonce()
+	action, err := req.toAction(ctx, e, timestamp)
+	if err != nil {
+		return UpdateResponse{}, fmt.Errorf(
+			"failed to convert request to action: %w",
+			err,
+		)
+	}
 
-// // CValidatorRegisterProfile represents validator profile configuration
-// type CValidatorRegisterProfile struct {
-// 	NodeIP              string
-// 	Name                string
-// 	Description         string
-// 	DelegationsDisabled bool
-// 	CommissionBps       int64
-// 	Signer              string
-// }
+	sig, err := action.sign(e.privateKey, timestamp, e)
+	if err != nil {
+		return UpdateResponse{}, fmt.Errorf("failed to sign action: %w", err)
+	}
 
-// // CValidatorRegister registers a new validator
-// func (e *Exchange) CValidatorRegister(
-// 	ctx context.Context,
-// 	profile CValidatorRegisterProfile,
-// 	unjailed bool,
-// 	initialWei int64,
-// ) (Response, error) {
-//  timestamp := e.nextNonce()
-// 	action := map[string]any{
-// 		"type": "CValidatorAction",
-// 		"register": map[string]any{
-// 			"profile": map[string]any{
-// 				"node_ip": map[string]any{
-// 					"Ip": profile.NodeIP,
-// 				},
-// 				"name":                 profile.Name,
-// 				"description":          profile.Description,
-// 				"delegations_disabled": profile.DelegationsDisabled,
-// 				"commission_bps":       profile.CommissionBps,
-// 				"signer":               profile.Signer,
-// 			},
-// 			"unjailed":    unjailed,
-// 			"initial_wei": initialWei,
-// 		},
-// 	}
-// 	sig, err := e.signL1Action(action, uint64(timestamp))
-// 	if err != nil {
-// 		return Response{}, fmt.Errorf("failed to sign action: %w", err)
-// 	}
-// 	return e.post(ctx, action, timestamp, sig)
-// }
+	return post[UpdateResponse](ctx, e, action, timestamp, sig)
+}
 
-// // CValidatorChangeProfileOptions represents optional changes to validator
-// // profile
-// type CValidatorChangeProfileOptions struct {
-// 	NodeIP             *string
-// 	Name               *string
-// 	Description        *string
-// 	DisableDelegations *bool
-// 	CommissionBps      *int64
-// 	Signer             *string
-// }
+// CValidatorRegister registers a new validator with the given profile
+func (e *Exchange) CValidatorRegister(
+	ctx context.Context,
+	profile CValidatorProfile,
+	unjailed bool,
+	initialWei int64,
+) (UpdateResponse, error) {
+	return e.sendUpdate(
+		ctx,
+		CValidatorRegisterRequest(profile, unjailed, initialWei),
+	)
+}
 
-// // CValidatorChangeProfile updates validator profile
-// func (e *Exchange) CValidatorChangeProfile(
-// 	ctx context.Context,
-// 	unjailed bool,
-// 	options CValidatorChangeProfileOptions,
-// ) (Response, error) {
-//  timestamp := e.nextNonce()
+// CValidatorChangeProfile updates the validator profile. Only the non-nil
+// fields of changes are updated.
+func (e *Exchange) CValidatorChangeProfile(
+	ctx context.Context,
+	unjailed bool,
+	changes CValidatorProfileChanges,
+) (UpdateResponse, error) {
+	return e.sendUpdate(
+		ctx,
+		CValidatorChangeProfileRequest(unjailed, changes),
+	)
+}
 
-// 	var nodeIP any
-// 	if options.NodeIP != nil {
-// 		nodeIP = map[string]any{"Ip": *options.NodeIP}
-// 	}
+// CValidatorUnregister unregisters the validator
+func (e *Exchange) CValidatorUnregister(
+	ctx context.Context,
+) (UpdateResponse, error) {
+	return e.sendUpdate(ctx, CValidatorUnregisterRequest())
+}
 
-// 	action := map[string]any{
-// 		"type": "CValidatorAction",
-// 		"changeProfile": map[string]any{
-// 			"node_ip":             nodeIP,
-// 			"name":                options.Name,
-// 			"description":         options.Description,
-// 			"unjailed":            unjailed,
-// 			"disable_delegations": options.DisableDelegations,
-// 			"commission_bps":      options.CommissionBps,
-// 			"signer":              options.Signer,
-// 		},
-// 	}
-// 	sig, err := e.signL1Action(action, uint64(timestamp))
-// 	if err != nil {
-// 		return Response{}, fmt.Errorf("failed to sign action: %w", err)
-// 	}
-// 	return e.post(ctx, action, timestamp, sig)
-// }
+// UseBigBlocks enables or disables big blocks for EVM user modifications
+func (e *Exchange) UseBigBlocks(
+	ctx context.Context,
+	enable bool,
+) (UpdateResponse, error) {
+	req := UseBigBlocksRequest(enable)
+	action, err := req.toAction(ctx, e)
+	if err != nil {
+		return UpdateResponse{}, fmt.Errorf(
+			"failed to convert request to action: %w",
+			err,
+		)
+	}
 
-// // CValidatorUnregister unregisters the validator
-// func (e *Exchange) CValidatorUnregister(ctx context.Context) (Response,
-// error) {
-//  timestamp := e.nextNonce()
-// 	action := map[string]any{
-// 		"type":       "CValidatorAction",
-// 		"unregister": nil,
-// 	}
-// 	sig, err := e.signL1Action(action, uint64(timestamp))
-// 	if err != nil {
-// 		return Response{}, fmt.Errorf("failed to sign action: %w", err)
-// 	}
-// 	return e.post(ctx, action, timestamp, sig)
-// }
+	timestamp := e.nextNonce()
+	sig, err := action.sign(e.privateKey, timestamp, e)
+	if err != nil {
+		return UpdateResponse{}, fmt.Errorf("failed to sign action: %w", err)
+	}
 
-// // UseBigBlocks enables or disables big blocks for EVM user modifications
-// func (e *Exchange) UseBigBlocks(
-// 	ctx context.Context,
-// 	enable bool,
-// ) (Response, error) {
-//  timestamp := e.nextNonce()
-// 	action := map[string]any{
-// 		"type":           "evmUserModify",
-// 		"usingBigBlocks": enable,
-// 	}
-// 	sig, err := e.signL1Action(action, uint64(timestamp))
-// 	if err != nil {
-// 		return Response{}, fmt.Errorf("failed to sign action: %w", err)
-// 	}
-// 	return e.post(ctx, action, timestamp, sig)
-// }
+	return post[UpdateResponse](ctx, e, action, timestamp, sig)
+}
 
-// // AgentEnableDexAbstraction enables DEX abstraction for the agent
-// func (e *Exchange) AgentEnableDexAbstraction(
-// 	ctx context.Context,
-// ) (Response, error) {
-//  timestamp := e.nextNonce()
-// 	action := map[string]any{
-// 		"type": "agentEnableDexAbstraction",
-// 	}
-// 	sig, err := e.signL1ActionWithVault(
-// 		action,
-// 		uint64(timestamp),
-// 		e.vaultAddress,
-// 	)
-// 	if err != nil {
-// 		return Response{}, fmt.Errorf("failed to sign action: %w", err)
-// 	}
-// 	return e.post(ctx, action, timestamp, sig)
-// }
+// AgentEnableDexAbstraction enables DEX abstraction for the account of the
+// signing agent
+func (e *Exchange) AgentEnableDexAbstraction(
+	ctx context.Context,
+) (UpdateResponse, error) {
+	return e.sendUpdate(ctx, AgentEnableDexAbstractionRequest())
+}
 
-// // UserDexAbstraction enables or disables DEX abstraction for a user
-// func (e *Exchange) UserDexAbstraction(
-// 	ctx context.Context,
-// 	user common.Address,
-// 	enabled bool,
-// ) (Response, error) {
-//  timestamp := e.nextNonce()
-// 	action := map[string]any{
-// 		"type":    "userDexAbstraction",
-// 		"user":    strings.ToLower(user.String()),
-// 		"enabled": enabled,
-// 		"nonce":   timestamp,
-// 	}
-// 	sig, err := e.signUserDexAbstractionAction(action)
-// 	if err != nil {
-// 		return Response{}, fmt.Errorf("failed to sign action: %w", err)
-// 	}
-// 	return e.post(ctx, action, timestamp, sig)
-// }
+// UserDexAbstraction enables or disables DEX abstraction for user
+func (e *Exchange) UserDexAbstraction(
+	ctx context.Context,
+	user common.Address,
+	enabled bool,
+) (UpdateResponse, error) {
+	return e.sendUpdate(ctx, UserDexAbstractionRequest(user, enabled))
+}
 
 // MultiSig executes a multi-signature transaction
+// The signatures in request come from SignForMultiSig, one per authorized
+// user.
 // Use the generic Resp to specify the response type of the action
 // and T to specify the type of the inner request
 func MultiSig[Resp any, T request](
@@ -1616,17 +2596,26 @@ func MultiSig[Resp any, T request](
 	return result, nil
 }
 
-// Noop sends a no-operation action
-// func (e *Exchange) Noop(ctx context.Context, nonce int64) (Response, error) {
-// 	action := map[string]any{
-// 		"type": "noop",
-// 	}
-// 	sig, err := e.signL1ActionWithVault(action, uint64(nonce), e.vaultAddress)
-// 	if err != nil {
-// 		return Response{}, fmt.Errorf("failed to sign action: %w", err)
-// 	}
-// 	return post(ctx, action, nonce, sig)
-// }
+// Noop sends a no-operation action. It uses up a nonce without doing
+// anything else, which makes it a cheap way to invalidate a nonce that was
+// handed out to a pending action.
+func (e *Exchange) Noop(ctx context.Context) (UpdateResponse, error) {
+	action, err := NoopRequest().toAction(ctx, e)
+	if err != nil {
+		return UpdateResponse{}, fmt.Errorf(
+			"failed to convert request to action: %w",
+			err,
+		)
+	}
+
+	timestamp := e.nextNonce()
+	sig, err := action.sign(e.privateKey, timestamp, e)
+	if err != nil {
+		return UpdateResponse{}, fmt.Errorf("failed to sign action: %w", err)
+	}
+
+	return post[UpdateResponse](ctx, e, action, timestamp, sig)
+}
 
 // sortStringMap converts a map to sorted key-value pairs
 func sortStringMap(m map[string]string) [][]string {
@@ -1642,13 +2631,14 @@ func sortStringMap(m map[string]string) [][]string {
 	return result
 }
 
-func post[T any, U action](
-	ctx context.Context,
+// buildPayload builds the body that is posted to /exchange for a signed
+// action
+func buildPayload[U action](
 	exchange *Exchange,
 	action U,
 	timestamp int64,
 	sig signature,
-) (T, error) {
+) map[string]any {
 	payload := map[string]any{
 		"action":    action,
 		"signature": sig,
@@ -1664,15 +2654,89 @@ func post[T any, U action](
 		payload["vaultAddress"] = nil
 	}
 
-	if e, ok := exchange.expiresAfter.Get(); ok {
-		payload["expiresAfter"] = e
-	} else {
-		payload["expiresAfter"] = nil
+	// Only L1 actions sign over expiresAfter. User-signed actions are
+	// rejected if it is present, so it is left out of their payload.
+	if action.getPrimaryType() == "" {
+		if e, ok := exchange.expiresAfter.Get(); ok {
+			payload["expiresAfter"] = e.Milliseconds()
+		} else {
+			payload["expiresAfter"] = nil
+		}
+	}
+
+	return payload
+}
+
+func post[T any, U action](
+	ctx context.Context,
+	exchange *Exchange,
+	action U,
+	timestamp int64,
+	sig signature,
+) (T, error) {
+	payload := buildPayload(exchange, action, timestamp, sig)
+	return postPayload[T](ctx, exchange, action.getType(), payload)
+}
+
+// postPayload posts a signed payload built by buildPayload and decodes the
+// response. Rejections and failures are logged once here.
+func postPayload[T any](
+	ctx context.Context,
+	exchange *Exchange,
+	actionType string,
+	payload map[string]any,
+) (T, error) {
+	nonce := payload["nonce"]
+	exchange.logger.Debug(
+		"posting signed action",
+		"type", actionType,
+		"nonce", nonce,
+	)
+
+	result, err := sendPayload[T](ctx, exchange, actionType, payload)
+
+	var statusErr *StatusError
+	switch {
+	case errors.As(err, &statusErr):
+		exchange.logger.Warn(
+			"exchange rejected action",
+			"type", actionType,
+			"nonce", nonce,
+			"err", err,
+		)
+	case err != nil:
+		exchange.logger.Error(
+			"exchange post failed",
+			"type", actionType,
+			"nonce", nonce,
+			"err", err,
+		)
 	}
 
+	return result, err
+}
+
+// sendPayload sends payload over the websocket transport if one is
+// connected, or REST otherwise, and decodes the response
+func sendPayload[T any](
+	ctx context.Context,
+	exchange *Exchange,
+	actionType string,
+	payload map[string]any,
+) (T, error) {
 	var zero T
-	var response response[T]
-	if err := exchange.rest.Post(ctx, "/exchange", payload, &response); err != nil {
+	body, sent, err := postWebSocket(ctx, exchange, payload)
+	if err != nil {
+		return zero, fmt.Errorf(
+			"failed to post over websocket. Type: %v: %w",
+			actionType,
+			err,
+		)
+	}
+	if !sent {
+		err = exchange.rest.Post(ctx, "/exchange", payload, &body)
+	}
+	if err != nil {
 		return zero, fmt.Errorf(
 			"failed to post to /exchange. Type: %v: %w",
 			actionType,
@@ -1680,17 +2744,127 @@ func post[T any, U action](
 		)
 	}
 
-	if response.IsErr() {
+	return decodeResponse[T](actionType, body)
+}
+
+// decodeResponse decodes an /exchange response body, returning a
+// *StatusError if the exchange rejected the action
+func decodeResponse[T any](
+	actionType string,
+	body json.RawMessage,
+) (T, error) {
+	var zero T
+	var response response[T]
+	if err := json.Unmarshal(body, &response); err != nil {
+		var statusErr *StatusError
+		if errors.As(err, &statusErr) {
+			statusErr.Action = actionType
+			statusErr.Body = body
+			return zero, statusErr
+		}
 		return zero, fmt.Errorf(
-			"exchange error (action: %v): %s",
+			"failed to decode /exchange response. Type: %v: %w",
 			actionType,
-			response.ErrorMessage,
+			err,
 		)
 	}
 
+	if response.IsErr() {
+		return zero, &StatusError{
+			Action:  actionType,
+			Message: response.ErrorMessage,
+			Body:    body,
+		}
+	}
+
 	return *response.Data, nil
 }
 
+// postWebSocket sends payload through the websocket transport, if one is
+// configured, and returns the raw response. It reports false when the
+// request was not sent so the caller can use REST instead. Only a missing
+// connection falls back; any other failure, including ws.ErrConnectionLost,
+// may have reached the server and retrying over REST could submit the action
+// twice.
+func postWebSocket(
+	ctx context.Context,
+	exchange *Exchange,
+	payload map[string]any,
+) (json.RawMessage, bool, error) {
+	if exchange.wsTransport == nil {
+		return nil, false, nil
+	}
+
+	raw, err := exchange.wsTransport.Post(ctx, ws.PostTypeAction, payload)
+	if errors.Is(err, ws.ErrNotConnected) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, true, err
+	}
+
+	return raw, true, nil
+}
+
+// userAddress returns the address whose state the exchange acts on. This is
+// the vault if one is set, then the account address, and finally the address
+// of the signing key.
+func (e *Exchange) userAddress() common.Address {
+	if v, ok := e.vaultAddress.Get(); ok {
+		return v
+	}
+	if a, ok := e.accountAddress.Get(); ok {
+		return a
+	}
+	return crypto.PubkeyToAddress(e.privateKey.PublicKey)
+}
+
+// orderUser returns the account an order call acts on, which is the vault
+// set with WithVault if any and userAddress otherwise
+func (e *Exchange) orderUser(cfg orderConfig) common.Address {
+	if v, ok := cfg.vault.Get(); ok {
+		return v
+	}
+	return e.userAddress()
+}
+
+// marketOrderPrice returns the limit price and tif to use for a market order.
+// Native market orders use the FrontendMarket tif with the caller's price as
+// the worst acceptable fill, while all others are priced off the mid with
+// slippage and sent as IoC.
+func (e *Exchange) marketOrderPrice(
+	ctx context.Context,
+	coin string,
+	isBuy bool,
+	nativeMarket bool,
+	slippage mo.Option[float64],
+	pxOverride mo.Option[float64],
+) (float64, string, error) {
+	if nativeMarket {
+		px, ok := pxOverride.Get()
+		if !ok {
+			return 0, "", fmt.Errorf(
+				"native market order for %s requires a limit price",
+				coin,
+			)
+		}
+		return px, TifFrontendMarket, nil
+	}
+
+	px, err := e.getSlippagePrice(
+		ctx,
+		coin,
+		isBuy,
+		slippage.OrElse(DEFAULT_SLIPPAGE),
+		pxOverride,
+	)
+	if err != nil {
+		return 0, "", fmt.Errorf("failed to get slippage price: %w", err)
+	}
+
+	return px, TifIoc, nil
+}
+
 func (e *Exchange) getSlippagePrice(
 	ctx context.Context,
 	coin string,
@@ -1709,9 +2883,7 @@ func (e *Exchange) getSlippagePrice(
 	if override, ok := pxOverride.Get(); ok {
 		px = override
 	} else {
-		dex := utils.GetDex(coin)
-
-		mids, err := e.info.AllMids(ctx, dex)
+		mids, err := e.allMids(ctx, utils.GetDex(coin))
 		if err != nil {
 			return 0, fmt.Errorf("failed to fetch mid prices: %w", err)
 		}
@@ -1724,15 +2896,6 @@ func (e *Exchange) getSlippagePrice(
 		px = midPrice
 	}
 
-	// 2. Map coin -> asset
-	asset, ok := e.info.CoinToAsset(coin)
-	if !ok {
-		return 0, fmt.Errorf("asset not found for coin: %s", coin)
-	}
-
-	// Spot assets start at 10000 (same logic as Python: asset >= 10_000)
-	isSpot := asset >= 10_000
-
 	// Apply slippage in the right direction
 	if isBuy {
 		px = px * (1 + slippage)
@@ -1740,26 +2903,166 @@ func (e *Exchange) getSlippagePrice(
 		px = px * (1 - slippage)
 	}
 
-	// 4. Round to 5 significant figures (Python: f"{px:.5g}")
-	px = utils.RoundToSigfig(px, 5)
+	_, pxDecimals, err := e.orderPrecision(coin)
+	if err != nil {
+		return 0, err
+	}
+
+	return roundPrice(px, pxDecimals), nil
+}
+
+// maxPriceSigFigs is the number of significant figures allowed in a
+// non-integer price
+const maxPriceSigFigs = 5
+
+// orderPrecision returns the number of decimals allowed in the size and in
+// the price of an order for coin. Prices may have up to 6 decimals for perps
+// and 8 for spot, less the asset's szDecimals.
+func (e *Exchange) orderPrecision(
+	coin string,
+) (szDecimals int64, pxDecimals int64, err error) {
+	asset, ok := e.getAsset(coin)
+	if !ok {
+		return 0, 0, fmt.Errorf("asset not found for coin: %s", coin)
+	}
+
+	szDecimals, ok = e.info.AssetToSzDecimals(asset)
+	if !ok {
+		return 0, 0, fmt.Errorf(
+			"asset sz decimals not found for asset: %d",
+			asset,
+		)
+	}
 
-	// 5. Final decimal rounding:
-	// Python: round(px_5sig, (6 if not is_spot else 8) -
-	// asset_to_sz_decimals[asset])
+	// Spot assets sit between 10000 and 100000. Builder-deployed perp dexs
+	// take ids from 100000 + n*10000 and use perp decimals
 	baseDecimals := int64(6)
-	if isSpot {
+	if asset >= 10_000 && asset < 100_000 {
 		baseDecimals = 8
 	}
 
+	return szDecimals, baseDecimals - szDecimals, nil
+}
+
+// roundPrice rounds px to 5 significant figures and then to pxDecimals, as
+// the Python SDK does with round(float(f"{px:.5g}"), decimals)
+func roundPrice(px float64, pxDecimals int64) float64 {
+	px = utils.RoundToSigfig(px, maxPriceSigFigs)
+	return utils.RoundToDecimals(px, pxDecimals)
+}
+
+// validPrice reports whether px is accepted by the exchange. Integer prices
+// are always valid; others are limited to 5 significant figures and
+// pxDecimals decimals.
+func validPrice(px float64, pxDecimals int64) bool {
+	if px <= 0 {
+		return false
+	}
+	if px == math.Trunc(px) {
+		return true
+	}
+	return utils.RoundToSigfig(px, maxPriceSigFigs) == px &&
+		utils.RoundToDecimals(px, pxDecimals) == px
+}
+
+// ValidateOrder checks that the prices and size of request conform to the
+// asset's precision, so that orders the exchange would reject with
+// tickRejected or a size error are caught before they are signed. The
+// returned error wraps ErrInvalidPrice or ErrInvalidSize. Use RoundOrder to
+// fix a request instead.
+func (e *Exchange) ValidateOrder(request orderRequest) error {
+	szDecimals, pxDecimals, err := e.orderPrecision(request.coin)
+	if err != nil {
+		return err
+	}
+
+	if !validPrice(request.limitPx, pxDecimals) {
+		return fmt.Errorf(
+			"%w: %v for %s allows %d significant figures and %d decimals",
+			ErrInvalidPrice,
+			request.limitPx,
+			request.coin,
+			maxPriceSigFigs,
+			pxDecimals,
+		)
+	}
+
+	if t := request.orderType.Trigger; t != nil &&
+		!validPrice(t.TriggerPx, pxDecimals) {
+		return fmt.Errorf(
+			"%w: trigger price %v for %s allows %d significant figures "+
+				"and %d decimals",
+			ErrInvalidPrice,
+			t.TriggerPx,
+			request.coin,
+			maxPriceSigFigs,
+			pxDecimals,
+		)
+	}
+
+	if request.sz <= 0 ||
+		utils.RoundToDecimals(request.sz, szDecimals) != request.sz {
+		return fmt.Errorf(
+			"%w: %v for %s allows %d decimals",
+			ErrInvalidSize,
+			request.sz,
+			request.coin,
+			szDecimals,
+		)
+	}
+
+	return nil
+}
+
+// RoundOrder returns a copy of request with its prices rounded to the
+// asset's tick and its size rounded to szDecimals. It returns an error if the
+// rounded order is still invalid, for example because the size rounds to
+// zero.
+func (e *Exchange) RoundOrder(request orderRequest) (orderRequest, error) {
+	szDecimals, pxDecimals, err := e.orderPrecision(request.coin)
+	if err != nil {
+		return orderRequest{}, err
+	}
+
+	if !validPrice(request.limitPx, pxDecimals) {
+		request.limitPx = roundPrice(request.limitPx, pxDecimals)
+	}
+	if t := request.orderType.Trigger; t != nil &&
+		!validPrice(t.TriggerPx, pxDecimals) {
+		trigger := *t
+		trigger.TriggerPx = roundPrice(t.TriggerPx, pxDecimals)
+		request.orderType.Trigger = &trigger
+	}
+	request.sz = utils.RoundToDecimals(request.sz, szDecimals)
+
+	if err := e.ValidateOrder(request); err != nil {
+		return orderRequest{}, err
+	}
+
+	return request, nil
+}
+
+// floorSize rounds sz down to the coin's szDecimals. Position sizes can carry
+// more precision than the asset allows, and rounding up could exceed the
+// position being closed.
+func (e *Exchange) floorSize(coin string, sz float64) (float64, error) {
+	asset, ok := e.getAsset(coin)
+	if !ok {
+		return 0, fmt.Errorf("unknown coin: %s", coin)
+	}
+
 	szDecimals, ok := e.info.AssetToSzDecimals(asset)
 	if !ok {
 		return 0, fmt.Errorf("asset sz decimals not found for asset: %d", asset)
 	}
 
-	decimals := baseDecimals - szDecimals
-	px = utils.RoundToDecimals(px, decimals)
+	rounded := utils.RoundToDecimals(sz, szDecimals)
+	if rounded > sz {
+		step := math.Pow10(-int(szDecimals))
+		rounded = utils.RoundToDecimals(rounded-step, szDecimals)
+	}
 
-	return px, nil
+	return rounded, nil
 }
 
 // nextNonce returns a strictly increasing nonce suitable for Hyperliquid.
@@ -1777,11 +3080,24 @@ func (e *Exchange) nextNonce() int64 {
 		}
 
 		if e.prevNonce.CompareAndSwap(prev, curr) {
+			e.logger.Debug("selected nonce", "nonce", curr)
 			return curr
 		}
 	}
 }
 
-func getSignatureChainId() string {
-	return fmt.Sprintf("0x%x", constants.SIGNATURE_CHAIN_ID)
+// getSignatureChainId returns the signatureChainId of user-signed actions as
+// a hex string
+func (e *Exchange) getSignatureChainId() string {
+	chainID := e.chainID
+	if chainID == 0 {
+		chainID = constants.SIGNATURE_CHAIN_ID
+	}
+	return fmt.Sprintf("0x%x", chainID)
+}
+
+// getAsset returns the asset ID of coin, looked up on the perp dex named by
+// its "dex:" prefix
+func (e *Exchange) getAsset(coin string) (int64, bool) {
+	return e.info.GetAssetForDex(coin, utils.GetDex(coin))
 }
@@ -129,8 +129,7 @@ func (s *ExchangeIntegrationSuite) TestOrderWithCloid(assert, require *td.T) {
 		ctx,
 		CancelByCloidRequest("ETH", cloid),
 	)
-
-	require.NotNil(orderResponse.Resting)
+	require.CmpNoError(err)
 
 	require.TB.Log(cancelResponse)
 }
@@ -138,7 +137,7 @@ func (s *ExchangeIntegrationSuite) TestOrderWithCloid(assert, require *td.T) {
 func (s *ExchangeIntegrationSuite) TestModify(assert, require *td.T) {
 	ctx := context.Background()
 
-	cloid := types.HexToCloid("0x00000000000000000000000000000001")
+	cloid := types.MustHexToCloid("0x00000000000000000000000000000001")
 
 	orderResponse, err := s.exchange.Order(
 		ctx,
@@ -451,7 +450,7 @@ func (s *ExchangeIntegrationSuite) TestTokenDelegate(assert, require *td.T) {
 	response, err := s.exchange.TokenDelegate(
 		ctx,
 		validator,
-		100,
+		big.NewInt(100),
 		false,
 	)
 	require.CmpNoError(err)
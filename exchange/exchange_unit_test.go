@@ -0,0 +1,3236 @@
+package exchange
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/banky/go-hyperliquid/constants"
+	"github.com/banky/go-hyperliquid/info"
+	"github.com/banky/go-hyperliquid/types"
+	"github.com/banky/go-hyperliquid/ws"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/samber/mo"
+)
+
+// testInfo creates an info client for the given network without touching the
+// network
+func testInfo(t *testing.T, baseURL string) *info.Info {
+	t.Helper()
+
+	i, err := info.New(info.Config{
+		BaseURL:  baseURL,
+		SkipWS:   true,
+		Meta:     &info.Meta{},
+		SpotMeta: &info.SpotMeta{},
+	})
+	if err != nil {
+		t.Fatalf("failed to create info client: %v", err)
+	}
+
+	return i
+}
+
+func TestNewRejectsNetworkMismatch(t *testing.T) {
+	_, err := New(Config{
+		BaseURL:    constants.MAINNET_API_URL,
+		PrivateKey: testPrivateKey(),
+		Info:       testInfo(t, constants.TESTNET_API_URL),
+	})
+	if err == nil {
+		t.Fatal("expected network mismatch error, got nil")
+	}
+	if !strings.Contains(err.Error(), "network mismatch") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestNewUsesInjectedInfo(t *testing.T) {
+	i := testInfo(t, constants.TESTNET_API_URL)
+
+	e, err := New(Config{
+		BaseURL:    constants.TESTNET_API_URL,
+		PrivateKey: testPrivateKey(),
+		Info:       i,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if e.info != i {
+		t.Fatal("expected exchange to use the injected info client")
+	}
+	if e.ownsInfo {
+		t.Fatal("expected exchange not to close the injected info client")
+	}
+}
+
+func TestNewOwnsCreatedInfo(t *testing.T) {
+	e, err := New(Config{
+		BaseURL:    constants.TESTNET_API_URL,
+		PrivateKey: testPrivateKey(),
+		SkipWS:     true,
+		Meta:       &info.Meta{},
+		SpotMeta:   &info.SpotMeta{},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer e.Close()
+
+	if !e.ownsInfo {
+		t.Fatal("expected exchange to close the info client it created")
+	}
+}
+
+// testServer is a fake Hyperliquid API that records every request it receives
+// and answers using the handler
+type testServer struct {
+	mu       sync.Mutex
+	requests []testServerRequest
+	handler  func(path string, body map[string]any) any
+}
+
+type testServerRequest struct {
+	Path string
+	Body map[string]any
+}
+
+// actions returns the types of every action posted to /exchange
+func (s *testServer) actions() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var types []string
+	for _, r := range s.requests {
+		if r.Path != "/exchange" {
+			continue
+		}
+		action, _ := r.Body["action"].(map[string]any)
+		t, _ := action["type"].(string)
+		types = append(types, t)
+	}
+	return types
+}
+
+// newTestExchange creates an exchange backed by a fake API. BTC and ETH are
+// registered as perps 0 and 1.
+func newTestExchange(
+	t *testing.T,
+	handler func(path string, body map[string]any) any,
+) (*Exchange, *testServer) {
+	t.Helper()
+
+	ts := &testServer{handler: handler}
+	srv := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var body map[string]any
+			_ = json.NewDecoder(r.Body).Decode(&body)
+
+			ts.mu.Lock()
+			ts.requests = append(
+				ts.requests,
+				testServerRequest{Path: r.URL.Path, Body: body},
+			)
+			ts.mu.Unlock()
+
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(ts.handler(r.URL.Path, body))
+		}),
+	)
+	t.Cleanup(srv.Close)
+
+	e, err := New(Config{
+		BaseURL:    srv.URL,
+		SkipWS:     true,
+		PrivateKey: testPrivateKey(),
+		Meta: &info.Meta{
+			Universe: []info.AssetInfo{
+				{Name: "BTC", SzDecimals: 5},
+				{Name: "ETH", SzDecimals: 4},
+			},
+		},
+		SpotMeta: &info.SpotMeta{},
+	})
+	if err != nil {
+		t.Fatalf("failed to create exchange: %v", err)
+	}
+
+	return e, ts
+}
+
+// okStatuses builds an ok exchange response with the given statuses
+func okStatuses(responseType string, statuses ...any) map[string]any {
+	return map[string]any{
+		"status": "ok",
+		"response": map[string]any{
+			"type": responseType,
+			"data": map[string]any{"statuses": statuses},
+		},
+	}
+}
+
+// restingOrderStatus is the order status response for a resting order
+func restingOrderStatus(oid int64, isTrigger bool) map[string]any {
+	return map[string]any{
+		"status": "order",
+		"order": map[string]any{
+			"order": map[string]any{
+				"coin":      "BTC",
+				"oid":       oid,
+				"isTrigger": isTrigger,
+			},
+			"status": "open",
+		},
+	}
+}
+
+func TestBulkModifyOrdersPriceOnly(t *testing.T) {
+	var user any
+	e, ts := newTestExchange(t, func(path string, body map[string]any) any {
+		if path == "/info" {
+			user = body["user"]
+			return restingOrderStatus(1, false)
+		}
+		return okStatuses(
+			"order",
+			map[string]any{"resting": map[string]any{"oid": 1}},
+		)
+	})
+
+	responses, err := e.BulkModifyOrders(
+		context.Background(),
+		[]modifyRequest{
+			ModifyRequest(
+				OrderRequest(
+					"BTC",
+					true,
+					0.01,
+					50000,
+					WithLimitOrder(LimitOrder{Tif: "Gtc"}),
+				),
+				WithModifyOrderId(1),
+			),
+		},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(responses) != 1 {
+		t.Fatalf("expected 1 response, got %d", len(responses))
+	}
+	if responses[0].Path != ModifyPathModify {
+		t.Fatalf("expected modify path, got %q", responses[0].Path)
+	}
+	if responses[0].Resting == nil || responses[0].Resting.Oid != 1 {
+		t.Fatalf("expected resting oid 1, got %+v", responses[0])
+	}
+
+	// The order is looked up for the account the modify is signed for
+	ts.mu.Lock()
+	lookedUp := user
+	ts.mu.Unlock()
+	if want := strings.ToLower(e.userAddress().Hex()); lookedUp != want {
+		t.Fatalf("expected order lookup for %s, got %v", want, lookedUp)
+	}
+
+	actions := ts.actions()
+	if len(actions) != 1 || actions[0] != "batchModify" {
+		t.Fatalf("expected a single batchModify action, got %v", actions)
+	}
+}
+
+func TestBulkModifyOrdersTypeChange(t *testing.T) {
+	e, ts := newTestExchange(t, func(path string, body map[string]any) any {
+		if path == "/info" {
+			oid := int64(body["oid"].(float64))
+			return restingOrderStatus(oid, false)
+		}
+
+		action := body["action"].(map[string]any)
+		switch action["type"] {
+		case "cancel":
+			return okStatuses("cancel", "success")
+		case "batchModify":
+			return okStatuses(
+				"order",
+				map[string]any{"resting": map[string]any{"oid": 1}},
+			)
+		default:
+			return okStatuses(
+				"order",
+				map[string]any{"resting": map[string]any{"oid": 3}},
+			)
+		}
+	})
+
+	responses, err := e.BulkModifyOrders(
+		context.Background(),
+		[]modifyRequest{
+			ModifyRequest(
+				OrderRequest(
+					"BTC",
+					true,
+					0.01,
+					50000,
+					WithLimitOrder(LimitOrder{Tif: "Gtc"}),
+				),
+				WithModifyOrderId(1),
+			),
+			ModifyRequest(
+				OrderRequest(
+					"BTC",
+					false,
+					0.01,
+					40000,
+					WithTriggerOrder(TriggerOrder{
+						IsMarket:  true,
+						TriggerPx: 40000,
+						TpSl:      "sl",
+					}),
+				),
+				WithModifyOrderId(2),
+			),
+		},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(responses) != 2 {
+		t.Fatalf("expected 2 responses, got %d", len(responses))
+	}
+	if responses[0].Path != ModifyPathModify {
+		t.Fatalf("expected modify path for order 0, got %q", responses[0].Path)
+	}
+	if responses[1].Path != ModifyPathCancelReplace {
+		t.Fatalf(
+			"expected cancel/replace path for order 1, got %q",
+			responses[1].Path,
+		)
+	}
+	if responses[1].Resting == nil || responses[1].Resting.Oid != 3 {
+		t.Fatalf("expected replacement oid 3, got %+v", responses[1])
+	}
+
+	actions := ts.actions()
+	expected := []string{"batchModify", "cancel", "order"}
+	if strings.Join(actions, ",") != strings.Join(expected, ",") {
+		t.Fatalf("expected actions %v, got %v", expected, actions)
+	}
+}
+
+func TestModifyOrderSize(t *testing.T) {
+	cloid := "0x00000000000000000000000000000007"
+	e, ts := newTestExchange(t, func(path string, body map[string]any) any {
+		if path == "/info" {
+			return map[string]any{
+				"status": "order",
+				"order": map[string]any{
+					"order": map[string]any{
+						"coin":       "ETH",
+						"oid":        7,
+						"side":       "A",
+						"limitPx":    "3100.5",
+						"sz":         "0.2",
+						"tif":        "Alo",
+						"reduceOnly": true,
+						"cloid":      cloid,
+					},
+					"status": "open",
+				},
+			}
+		}
+		return okStatuses(
+			"order",
+			map[string]any{"resting": map[string]any{"oid": 7}},
+		)
+	})
+
+	resp, err := e.ModifyOrderSize(context.Background(), 7, "ETH", 0.5)
+	if err != nil {
+		t.Fatalf("ModifyOrderSize failed: %v", err)
+	}
+	if resp.Resting == nil || resp.Resting.Oid != 7 {
+		t.Fatalf("expected resting oid 7, got %+v", resp)
+	}
+
+	actions := ts.actions()
+	if strings.Join(actions, ",") != "batchModify" {
+		t.Fatalf("expected a single batchModify action, got %v", actions)
+	}
+
+	ts.mu.Lock()
+	body := ts.requests[len(ts.requests)-1].Body
+	ts.mu.Unlock()
+
+	modifies := body["action"].(map[string]any)["modifies"].([]any)
+	got, _ := json.Marshal(modifies[0])
+	expected := `{"oid":7,"order":{"a":1,"b":false,"c":"` + cloid + `",` +
+		`"p":"3100.5","r":true,"s":"0.5","t":{"limit":{"tif":"Alo"}}}}`
+	if string(got) != expected {
+		t.Errorf("expected modify %s, got %s", expected, got)
+	}
+}
+
+func TestModifyOrderSizeNotFound(t *testing.T) {
+	e, ts := newTestExchange(t, func(path string, body map[string]any) any {
+		return map[string]any{"status": "unknownOid"}
+	})
+
+	_, err := e.ModifyOrderSize(context.Background(), 7, "ETH", 0.5)
+	if err == nil || !strings.Contains(err.Error(), "order 7 not found") {
+		t.Fatalf("expected not found error, got %v", err)
+	}
+	if actions := ts.actions(); len(actions) != 0 {
+		t.Fatalf("expected no actions, got %v", actions)
+	}
+}
+
+func TestModifyOrderSizeCoinMismatch(t *testing.T) {
+	e, ts := newTestExchange(t, func(path string, body map[string]any) any {
+		return restingOrderStatus(7, false)
+	})
+
+	_, err := e.ModifyOrderSize(context.Background(), 7, "ETH", 0.5)
+	if err == nil || !strings.Contains(err.Error(), "is for BTC, not ETH") {
+		t.Fatalf("expected coin mismatch error, got %v", err)
+	}
+	if actions := ts.actions(); len(actions) != 0 {
+		t.Fatalf("expected no actions, got %v", actions)
+	}
+}
+
+func TestBulkModifyOrdersReplaceFailure(t *testing.T) {
+	e, _ := newTestExchange(t, func(path string, body map[string]any) any {
+		action := body["action"].(map[string]any)
+		if action["type"] == "cancel" {
+			return okStatuses("cancel", "success")
+		}
+		return map[string]any{"status": "err", "response": "Order rejected"}
+	})
+
+	_, err := e.BulkModifyOrders(
+		context.Background(),
+		[]modifyRequest{
+			ModifyRequest(
+				OrderRequest(
+					"BTC",
+					false,
+					0.01,
+					40000,
+					WithTriggerOrder(TriggerOrder{
+						IsMarket:  true,
+						TriggerPx: 40000,
+						TpSl:      "sl",
+					}),
+				),
+				WithModifyOrderId(2),
+				WithModifyCancelReplace(),
+			),
+		},
+	)
+
+	var replaceErr *ReplaceError
+	if !errors.As(err, &replaceErr) {
+		t.Fatalf("expected a ReplaceError, got %v", err)
+	}
+	if len(replaceErr.Canceled) != 1 ||
+		replaceErr.Canceled[0].Status != "success" {
+		t.Errorf("expected one successful cancel, got %v", replaceErr.Canceled)
+	}
+
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.Action != "order" {
+		t.Errorf("expected the order StatusError to be wrapped, got %v", err)
+	}
+}
+
+func TestBulkModifyOrdersPartialCancelFailure(t *testing.T) {
+	e, _ := newTestExchange(t, func(path string, body map[string]any) any {
+		action := body["action"].(map[string]any)
+		if action["type"] == "cancel" {
+			return okStatuses("cancel", "success")
+		}
+		return map[string]any{"status": "err", "response": "Cancel rejected"}
+	})
+
+	trigger := OrderRequest(
+		"BTC",
+		false,
+		0.01,
+		40000,
+		WithTriggerOrder(TriggerOrder{
+			IsMarket:  true,
+			TriggerPx: 40000,
+			TpSl:      "sl",
+		}),
+	)
+	_, err := e.BulkModifyOrders(
+		context.Background(),
+		[]modifyRequest{
+			ModifyRequest(
+				trigger,
+				WithModifyOrderId(2),
+				WithModifyCancelReplace(),
+			),
+			ModifyRequest(
+				trigger,
+				WithModifyCloid(
+					types.MustHexToCloid("0x00000000000000000000000000000003"),
+				),
+				WithModifyCancelReplace(),
+			),
+		},
+	)
+
+	var replaceErr *ReplaceError
+	if !errors.As(err, &replaceErr) {
+		t.Fatalf("expected a ReplaceError, got %v", err)
+	}
+	if len(replaceErr.Canceled) != 1 ||
+		replaceErr.Canceled[0].Status != "success" {
+		t.Errorf("expected one successful cancel, got %v", replaceErr.Canceled)
+	}
+
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.Action != "cancelByCloid" {
+		t.Errorf("expected the cancel StatusError to be wrapped, got %v", err)
+	}
+}
+
+func TestBulkOrdersResolvesAssetPerDex(t *testing.T) {
+	metas := map[string]any{
+		"abc": map[string]any{"universe": []any{
+			map[string]any{"name": "abc:FOO", "szDecimals": 2},
+		}},
+		"xyz": map[string]any{"universe": []any{
+			map[string]any{"name": "xyz:BAR", "szDecimals": 2},
+			map[string]any{"name": "xyz:FOO", "szDecimals": 2},
+		}},
+	}
+	e, ts := newTestExchange(t, func(path string, body map[string]any) any {
+		if path == "/info" {
+			if body["type"] == "perpDexs" {
+				return []any{
+					nil,
+					map[string]any{"name": "abc"},
+					map[string]any{"name": "xyz"},
+				}
+			}
+			return metas[body["dex"].(string)]
+		}
+		resting := map[string]any{"resting": map[string]any{"oid": 1}}
+		return okStatuses("order", resting, resting)
+	})
+	i, err := info.New(info.Config{
+		BaseURL:  e.rest.BaseUrl(),
+		SkipWS:   true,
+		Meta:     &info.Meta{Universe: []info.AssetInfo{{Name: "BTC"}}},
+		SpotMeta: &info.SpotMeta{},
+		PerpDexs: []string{"", "abc", "xyz"},
+	})
+	if err != nil {
+		t.Fatalf("failed to create info client: %v", err)
+	}
+	e.info = i
+
+	_, err = e.BulkOrders(
+		context.Background(),
+		[]orderRequest{
+			OrderRequest(
+				"abc:FOO",
+				true,
+				1,
+				10,
+				WithLimitOrder(LimitOrder{Tif: TifGtc}),
+			),
+			OrderRequest(
+				"xyz:FOO",
+				true,
+				1,
+				10,
+				WithLimitOrder(LimitOrder{Tif: TifGtc}),
+			),
+		},
+	)
+	if err != nil {
+		t.Fatalf("BulkOrders failed: %v", err)
+	}
+
+	ts.mu.Lock()
+	body := ts.requests[len(ts.requests)-1].Body
+	ts.mu.Unlock()
+
+	orders := body["action"].(map[string]any)["orders"].([]any)
+	var assets []float64
+	for _, order := range orders {
+		assets = append(assets, order.(map[string]any)["a"].(float64))
+	}
+	if len(assets) != 2 || assets[0] != 110000 || assets[1] != 120001 {
+		t.Errorf("expected assets [110000 120001], got %v", assets)
+	}
+}
+
+func TestBulkOrdersDuplicateCloid(t *testing.T) {
+	e, ts := newTestExchange(t, func(path string, body map[string]any) any {
+		return okStatuses("order")
+	})
+
+	cloid := types.MustHexToCloid("0x00000000000000000000000000000001")
+	_, err := e.BulkOrders(
+		context.Background(),
+		[]orderRequest{
+			OrderRequest(
+				"BTC",
+				true,
+				0.01,
+				50000,
+				WithLimitOrder(LimitOrder{Tif: "Gtc"}),
+				WithCloid(cloid),
+			),
+			OrderRequest(
+				"ETH",
+				true,
+				0.1,
+				3000,
+				WithLimitOrder(LimitOrder{Tif: "Gtc"}),
+			),
+			OrderRequest(
+				"BTC",
+				true,
+				0.01,
+				49000,
+				WithLimitOrder(LimitOrder{Tif: "Gtc"}),
+				WithCloid(cloid),
+			),
+		},
+	)
+	if err == nil {
+		t.Fatal("expected duplicate cloid error, got nil")
+	}
+	if !strings.Contains(err.Error(), cloid.Hex()) {
+		t.Fatalf("expected error to name the cloid, got %v", err)
+	}
+	if actions := ts.actions(); len(actions) != 0 {
+		t.Fatalf("expected no actions to be posted, got %v", actions)
+	}
+}
+
+func TestPositionSize(t *testing.T) {
+	e, _ := newTestExchange(t, func(path string, body map[string]any) any {
+		return map[string]any{
+			"assetPositions": []any{
+				map[string]any{
+					"type":     "oneWay",
+					"position": map[string]any{"coin": "BTC", "szi": "0.25"},
+				},
+				map[string]any{
+					"type":     "oneWay",
+					"position": map[string]any{"coin": "ETH", "szi": "-1.5"},
+				},
+			},
+		}
+	})
+
+	tests := []struct {
+		coin     string
+		expected float64
+	}{
+		{coin: "BTC", expected: 0.25},
+		{coin: "ETH", expected: -1.5},
+		{coin: "SOL", expected: 0},
+	}
+
+	for _, tt := range tests {
+		size, err := e.PositionSize(context.Background(), tt.coin)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.coin, err)
+		}
+		if size != tt.expected {
+			t.Fatalf("%s: expected size %v, got %v", tt.coin, tt.expected, size)
+		}
+	}
+}
+
+func TestMarketOpenNativeMarket(t *testing.T) {
+	e, ts := newTestExchange(t, func(path string, body map[string]any) any {
+		if path == "/info" {
+			t.Fatalf("unexpected info request: %v", body)
+		}
+		return okStatuses(
+			"order",
+			map[string]any{
+				"filled": map[string]any{
+					"totalSz": "0.01",
+					"avgPx":   "50000",
+					"oid":     1,
+				},
+			},
+		)
+	})
+
+	_, err := e.MarketOpen(
+		context.Background(),
+		MarketOpenRequest(
+			"BTC",
+			true,
+			0.01,
+			WithNativeMarket(),
+			WithMarketPrice(51000),
+		),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	if len(ts.requests) != 1 {
+		t.Fatalf("expected a single request, got %d", len(ts.requests))
+	}
+
+	action := ts.requests[0].Body["action"].(map[string]any)
+	order := action["orders"].([]any)[0].(map[string]any)
+	limit := order["t"].(map[string]any)["limit"].(map[string]any)
+	if limit["tif"] != "FrontendMarket" {
+		t.Fatalf("expected FrontendMarket tif, got %v", limit["tif"])
+	}
+	if order["p"] != "51000" {
+		t.Fatalf("expected price 51000, got %v", order["p"])
+	}
+}
+
+func TestMarketOpenNativeMarketRequiresPrice(t *testing.T) {
+	e, ts := newTestExchange(t, func(path string, body map[string]any) any {
+		return map[string]any{"BTC": "50000"}
+	})
+	req := MarketOpenRequest("BTC", true, 0.01, WithNativeMarket())
+
+	if _, err := e.MarketOpen(context.Background(), req); err == nil {
+		t.Fatal("expected MarketOpen to fail without a price")
+	}
+	if _, err := req.toAction(context.Background(), e); err == nil {
+		t.Fatal("expected toAction to fail without a price")
+	}
+
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	if len(ts.requests) != 0 {
+		t.Fatalf("expected no requests, got %d", len(ts.requests))
+	}
+}
+
+func TestMarketOpenWithMarketTif(t *testing.T) {
+	tests := []struct {
+		tif       string
+		wantMids  bool
+		wantPrice string
+	}{
+		{TifFrontendMarket, false, "49000"},
+		{TifIoc, true, "52500"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tif, func(t *testing.T) {
+			e, ts := newTestExchange(
+				t,
+				func(path string, body map[string]any) any {
+					if path == "/info" {
+						return map[string]any{"BTC": "50000"}
+					}
+					return okStatuses(
+						"order",
+						map[string]any{"resting": map[string]any{"oid": 1}},
+					)
+				},
+			)
+
+			opts := []marketOpenRequestOption{WithMarketTif(tt.tif)}
+			if tt.tif == TifFrontendMarket {
+				opts = append(opts, WithMarketPrice(49000))
+			}
+
+			_, err := e.MarketOpen(
+				context.Background(),
+				MarketOpenRequest("BTC", true, 0.01, opts...),
+			)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			ts.mu.Lock()
+			defer ts.mu.Unlock()
+
+			var midsFetched bool
+			var order map[string]any
+			for _, r := range ts.requests {
+				switch r.Path {
+				case "/info":
+					midsFetched = midsFetched || r.Body["type"] == "allMids"
+				case "/exchange":
+					action := r.Body["action"].(map[string]any)
+					order = action["orders"].([]any)[0].(map[string]any)
+				}
+			}
+
+			if midsFetched != tt.wantMids {
+				t.Errorf("mids fetched: %v, want %v", midsFetched, tt.wantMids)
+			}
+			limit := order["t"].(map[string]any)["limit"].(map[string]any)
+			if limit["tif"] != tt.tif {
+				t.Errorf("expected tif %s, got %v", tt.tif, limit["tif"])
+			}
+			if order["p"] != tt.wantPrice {
+				t.Errorf("expected price %s, got %v", tt.wantPrice, order["p"])
+			}
+		})
+	}
+}
+
+func TestMarketOpenReduceOnly(t *testing.T) {
+	for _, reduceOnly := range []bool{true, false} {
+		e, ts := newTestExchange(t, func(path string, body map[string]any) any {
+			return okStatuses(
+				"order",
+				map[string]any{"resting": map[string]any{"oid": 1}},
+			)
+		})
+
+		_, err := e.MarketOpen(
+			context.Background(),
+			MarketOpenRequest(
+				"BTC",
+				false,
+				0.01,
+				WithNativeMarket(),
+				WithMarketPrice(49000),
+				WithMarketReduceOnly(reduceOnly),
+			),
+		)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		ts.mu.Lock()
+		action := ts.requests[0].Body["action"].(map[string]any)
+		order := action["orders"].([]any)[0].(map[string]any)
+		ts.mu.Unlock()
+
+		if order["r"] != reduceOnly {
+			t.Errorf("expected r=%v, got %v", reduceOnly, order["r"])
+		}
+	}
+}
+
+func TestMarketOpenRejectsOtherTifs(t *testing.T) {
+	for _, tif := range []string{TifGtc, "Fok"} {
+		t.Run(tif, func(t *testing.T) {
+			e, ts := newTestExchange(
+				t,
+				func(path string, body map[string]any) any {
+					return map[string]any{"BTC": "50000"}
+				},
+			)
+			req := MarketOpenRequest("BTC", true, 0.01, WithMarketTif(tif))
+
+			if _, err := e.MarketOpen(context.Background(), req); err == nil {
+				t.Fatal("expected MarketOpen to fail")
+			}
+			if _, err := req.toAction(context.Background(), e); err == nil {
+				t.Fatal("expected toAction to fail")
+			}
+
+			ts.mu.Lock()
+			defer ts.mu.Unlock()
+
+			if len(ts.requests) != 0 {
+				t.Fatalf("expected no requests, got %d", len(ts.requests))
+			}
+		})
+	}
+}
+
+func TestTwapOrder(t *testing.T) {
+	e, ts := newTestExchange(t, func(path string, body map[string]any) any {
+		return map[string]any{
+			"status": "ok",
+			"response": map[string]any{
+				"type": "twapOrder",
+				"data": map[string]any{
+					"status": map[string]any{
+						"running": map[string]any{"twapId": 77738308},
+					},
+				},
+			},
+		}
+	})
+
+	resp, err := e.TwapOrder(
+		context.Background(),
+		TwapOrderRequest("ETH", true, 1.5, false, 30, true),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.TwapId != 77738308 {
+		t.Fatalf("expected twap id 77738308, got %d", resp.TwapId)
+	}
+
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	action, err := json.Marshal(ts.requests[0].Body["action"])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{"twap":{"a":1,"b":true,"m":30,"r":false,"s":"1.5","t":true},"type":"twapOrder"}`
+	if string(action) != expected {
+		t.Fatalf("wire shape mismatch:\nexpected %s\ngot      %s", expected, action)
+	}
+}
+
+func TestTwapCancel(t *testing.T) {
+	e, ts := newTestExchange(t, func(path string, body map[string]any) any {
+		return map[string]any{
+			"status": "ok",
+			"response": map[string]any{
+				"type": "twapCancel",
+				"data": map[string]any{"status": "success"},
+			},
+		}
+	})
+
+	resp, err := e.TwapCancel(context.Background(), "ETH", 77738308)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Status != "success" {
+		t.Fatalf("expected success status, got %q", resp.Status)
+	}
+
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	action, err := json.Marshal(ts.requests[0].Body["action"])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{"a":1,"t":77738308,"type":"twapCancel"}`
+	if string(action) != expected {
+		t.Fatalf("wire shape mismatch:\nexpected %s\ngot      %s", expected, action)
+	}
+}
+
+func TestMarketCloseIsReduceOnly(t *testing.T) {
+	e, ts := newTestExchange(t, func(path string, body map[string]any) any {
+		if path == "/info" {
+			return map[string]any{
+				"assetPositions": []any{
+					map[string]any{
+						"type":     "oneWay",
+						"position": map[string]any{"coin": "BTC", "szi": "0.25"},
+					},
+				},
+			}
+		}
+		return okStatuses(
+			"order",
+			map[string]any{
+				"filled": map[string]any{
+					"totalSz": "0.25",
+					"avgPx":   "50000",
+					"oid":     1,
+				},
+			},
+		)
+	})
+
+	req := MarketCloseRequest(
+		"BTC",
+		WithMarketCloseNativeMarket(),
+		WithMarketClosePrice(49000),
+	)
+
+	action, err := req.toAction(context.Background(), e)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	wire := action.(orderAction).Orders[0]
+	if !wire.R {
+		t.Fatal("expected toAction to build a reduce only order")
+	}
+
+	_, err = e.MarketClose(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	last := ts.requests[len(ts.requests)-1]
+	order := last.Body["action"].(map[string]any)["orders"].([]any)[0]
+	if order.(map[string]any)["r"] != true {
+		t.Fatalf("expected r to be true, got %v", order.(map[string]any)["r"])
+	}
+}
+
+// fakeTransport is a WebSocketTransport that records every payload it is
+// asked to post
+type fakeTransport struct {
+	payloads []any
+	err      error
+	response any
+}
+
+func (f *fakeTransport) Post(
+	ctx context.Context,
+	requestType string,
+	payload any,
+) (json.RawMessage, error) {
+	if requestType != ws.PostTypeAction {
+		return nil, fmt.Errorf("unexpected request type %q", requestType)
+	}
+	if f.err != nil {
+		return nil, f.err
+	}
+	f.payloads = append(f.payloads, payload)
+	return json.Marshal(f.response)
+}
+
+func TestWebSocketTransportMatchesREST(t *testing.T) {
+	resting := okStatuses(
+		"order",
+		map[string]any{"resting": map[string]any{"oid": 1}},
+	)
+	e, ts := newTestExchange(t, func(path string, body map[string]any) any {
+		return resting
+	})
+	transport := &fakeTransport{response: resting}
+	WithWebSocketTransport(transport)(e)
+
+	// Pin the nonce so both orders are signed identically
+	nonce := time.Now().Add(time.Hour).UnixMilli()
+	place := func() {
+		t.Helper()
+		e.prevNonce.Store(nonce)
+		_, err := e.Order(
+			context.Background(),
+			OrderRequest(
+				"BTC",
+				true,
+				0.01,
+				50000,
+				WithLimitOrder(LimitOrder{Tif: "Gtc"}),
+			),
+		)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	place()
+	if got := ts.actions(); len(got) != 0 {
+		t.Fatalf("expected no REST actions, got %v", got)
+	}
+	if len(transport.payloads) != 1 {
+		t.Fatalf("expected 1 websocket post, got %d", len(transport.payloads))
+	}
+
+	e.wsTransport = nil
+	place()
+
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	// Round trip through a map so both sides use the same key order
+	var wsBody map[string]any
+	wsJSON, _ := json.Marshal(transport.payloads[0])
+	_ = json.Unmarshal(wsJSON, &wsBody)
+
+	wsPayload, _ := json.Marshal(wsBody)
+	restPayload, _ := json.Marshal(ts.requests[len(ts.requests)-1].Body)
+	if string(wsPayload) != string(restPayload) {
+		t.Fatalf(
+			"payload mismatch:\nws   %s\nrest %s",
+			wsPayload,
+			restPayload,
+		)
+	}
+}
+
+func TestWebSocketTransportFallsBackWhenDisconnected(t *testing.T) {
+	e, ts := newTestExchange(t, func(path string, body map[string]any) any {
+		return okStatuses(
+			"order",
+			map[string]any{"resting": map[string]any{"oid": 1}},
+		)
+	})
+	WithWebSocketTransport(&fakeTransport{err: ws.ErrNotConnected})(e)
+
+	_, err := e.Order(
+		context.Background(),
+		OrderRequest(
+			"BTC",
+			true,
+			0.01,
+			50000,
+			WithLimitOrder(LimitOrder{Tif: "Gtc"}),
+		),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := ts.actions(); len(got) != 1 || got[0] != "order" {
+		t.Fatalf("expected order over REST, got %v", got)
+	}
+}
+
+func TestWebSocketTransportDoesNotFallBackWhenConnectionLost(t *testing.T) {
+	e, ts := newTestExchange(t, func(path string, body map[string]any) any {
+		return okStatuses(
+			"order",
+			map[string]any{"resting": map[string]any{"oid": 1}},
+		)
+	})
+	WithWebSocketTransport(&fakeTransport{err: ws.ErrConnectionLost})(e)
+
+	_, err := e.Order(
+		context.Background(),
+		OrderRequest(
+			"BTC",
+			true,
+			0.01,
+			50000,
+			WithLimitOrder(LimitOrder{Tif: "Gtc"}),
+		),
+	)
+	if !errors.Is(err, ws.ErrConnectionLost) {
+		t.Fatalf("expected ErrConnectionLost, got %v", err)
+	}
+
+	if got := ts.actions(); len(got) != 0 {
+		t.Fatalf("expected no REST fallback, got %v", got)
+	}
+}
+
+func TestPlaceWithTpSl(t *testing.T) {
+	tests := []struct {
+		name   string
+		tp     *TriggerOrder
+		sl     *TriggerOrder
+		tpsl   []string
+		orders int
+	}{
+		{
+			name:   "tp and sl",
+			tp:     &TriggerOrder{IsMarket: true, TriggerPx: 60000},
+			sl:     &TriggerOrder{IsMarket: true, TriggerPx: 45000},
+			tpsl:   []string{"tp", "sl"},
+			orders: 3,
+		},
+		{
+			name:   "tp only",
+			tp:     &TriggerOrder{IsMarket: true, TriggerPx: 60000},
+			tpsl:   []string{"tp"},
+			orders: 2,
+		},
+		{
+			name:   "sl only",
+			sl:     &TriggerOrder{IsMarket: false, TriggerPx: 45000},
+			tpsl:   []string{"sl"},
+			orders: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, ts := newTestExchange(
+				t,
+				func(path string, body map[string]any) any {
+					return okStatuses("order")
+				},
+			)
+
+			_, err := e.PlaceWithTpSl(
+				context.Background(),
+				OrderRequest(
+					"BTC",
+					true,
+					0.01,
+					50000,
+					WithLimitOrder(LimitOrder{Tif: "Gtc"}),
+				),
+				tt.tp,
+				tt.sl,
+			)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			ts.mu.Lock()
+			defer ts.mu.Unlock()
+
+			action := ts.requests[0].Body["action"].(map[string]any)
+			if action["grouping"] != "normalTpsl" {
+				t.Fatalf(
+					"expected normalTpsl grouping, got %v",
+					action["grouping"],
+				)
+			}
+
+			orders := action["orders"].([]any)
+			if len(orders) != tt.orders {
+				t.Fatalf(
+					"expected %d orders, got %d",
+					tt.orders,
+					len(orders),
+				)
+			}
+
+			for i, tpsl := range tt.tpsl {
+				child := orders[i+1].(map[string]any)
+				orderType := child["t"].(map[string]any)
+				trigger := orderType["trigger"].(map[string]any)
+				if trigger["tpsl"] != tpsl {
+					t.Fatalf(
+						"order %d: expected tpsl %s, got %v",
+						i+1,
+						tpsl,
+						trigger["tpsl"],
+					)
+				}
+				if child["b"] != false || child["r"] != true {
+					t.Fatalf(
+						"order %d: expected reduce only sell, got %v",
+						i+1,
+						child,
+					)
+				}
+				if child["s"] != "0.01" {
+					t.Fatalf(
+						"order %d: expected size 0.01, got %v",
+						i+1,
+						child["s"],
+					)
+				}
+			}
+		})
+	}
+}
+
+func TestPlaceWithTpSlRequiresTrigger(t *testing.T) {
+	e, ts := newTestExchange(t, func(path string, body map[string]any) any {
+		return okStatuses("order")
+	})
+
+	_, err := e.PlaceWithTpSl(
+		context.Background(),
+		OrderRequest(
+			"BTC",
+			true,
+			0.01,
+			50000,
+			WithLimitOrder(LimitOrder{Tif: "Gtc"}),
+		),
+		nil,
+		nil,
+	)
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if got := ts.actions(); len(got) != 0 {
+		t.Fatalf("expected nothing posted, got %v", got)
+	}
+}
+
+func TestSetPositionTpSl(t *testing.T) {
+	e, ts := newTestExchange(t, func(path string, body map[string]any) any {
+		if path == "/info" {
+			return map[string]any{
+				"assetPositions": []any{
+					map[string]any{
+						"type":     "oneWay",
+						"position": map[string]any{"coin": "ETH", "szi": "-2.5"},
+					},
+				},
+			}
+		}
+		return okStatuses("order")
+	})
+
+	_, err := e.SetPositionTpSl(
+		context.Background(),
+		"ETH",
+		&TriggerOrder{IsMarket: true, TriggerPx: 2500},
+		&TriggerOrder{IsMarket: true, TriggerPx: 3500},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	last := ts.requests[len(ts.requests)-1]
+	action := last.Body["action"].(map[string]any)
+	if action["grouping"] != "positionTpsl" {
+		t.Fatalf(
+			"expected positionTpsl grouping, got %v",
+			action["grouping"],
+		)
+	}
+
+	orders := action["orders"].([]any)
+	if len(orders) != 2 {
+		t.Fatalf("expected 2 orders, got %d", len(orders))
+	}
+	for i, o := range orders {
+		order := o.(map[string]any)
+		// The position is short, so closing orders buy
+		if order["r"] != true || order["b"] != true || order["s"] != "2.5" {
+			t.Fatalf("order %d: expected reduce only buy of 2.5, got %v", i, o)
+		}
+	}
+}
+
+func TestSetPositionTpSlWithoutPosition(t *testing.T) {
+	e, ts := newTestExchange(t, func(path string, body map[string]any) any {
+		if path == "/info" {
+			return map[string]any{"assetPositions": []any{}}
+		}
+		return okStatuses("order")
+	})
+
+	_, err := e.SetPositionTpSl(
+		context.Background(),
+		"ETH",
+		&TriggerOrder{IsMarket: true, TriggerPx: 2500},
+		nil,
+	)
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if got := ts.actions(); len(got) != 0 {
+		t.Fatalf("expected nothing posted, got %v", got)
+	}
+}
+
+func TestOrderTifValidation(t *testing.T) {
+	tests := []struct {
+		tif   string
+		valid bool
+	}{
+		{TifGtc, true},
+		{TifIoc, true},
+		{TifAlo, true},
+		{TifFrontendMarket, true},
+		{TifLiquidationMarket, true},
+		{"gtc", false},
+		{"FOK", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tif, func(t *testing.T) {
+			order := OrderRequest(
+				"BTC",
+				true,
+				0.01,
+				50000,
+				WithLimitOrder(LimitOrder{Tif: tt.tif}),
+			)
+
+			_, err := order.toOrderWire(0)
+			if tt.valid && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !tt.valid && err == nil {
+				t.Fatalf("expected error for tif %q, got nil", tt.tif)
+			}
+		})
+	}
+}
+
+func TestOrderInvalidTifNotPosted(t *testing.T) {
+	e, ts := newTestExchange(t, func(path string, body map[string]any) any {
+		return okStatuses("order")
+	})
+
+	_, err := e.Order(
+		context.Background(),
+		OrderRequest(
+			"BTC",
+			true,
+			0.01,
+			50000,
+			WithLimitOrder(LimitOrder{Tif: "gtc"}),
+		),
+	)
+	if err == nil || !strings.Contains(err.Error(), `invalid tif "gtc"`) {
+		t.Fatalf("expected invalid tif error, got %v", err)
+	}
+	if got := ts.actions(); len(got) != 0 {
+		t.Fatalf("expected nothing posted, got %v", got)
+	}
+}
+
+func TestNewOrderRequestWithoutOrderType(t *testing.T) {
+	_, err := NewOrderRequest("BTC", true, 0.01, 50000)
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	o, err := NewOrderRequest(
+		"BTC",
+		true,
+		0.01,
+		50000,
+		WithTriggerOrder(TriggerOrder{TriggerPx: 45000, TpSl: "sl"}),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if o.orderType.Trigger == nil {
+		t.Fatal("expected a trigger order")
+	}
+}
+
+func TestNewModifyRequestWithoutId(t *testing.T) {
+	order := OrderRequest(
+		"BTC",
+		true,
+		0.01,
+		50000,
+		WithLimitOrder(LimitOrder{Tif: TifGtc}),
+	)
+
+	_, err := NewModifyRequest(order)
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	m, err := NewModifyRequest(order, WithModifyOrderId(7))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if m.Oid.OrElse(0) != 7 {
+		t.Fatalf("expected oid 7, got %v", m.Oid)
+	}
+}
+
+func TestMarketCloseRoundsSizeDown(t *testing.T) {
+	e, ts := newTestExchange(t, func(path string, body map[string]any) any {
+		if path == "/info" {
+			return map[string]any{
+				"assetPositions": []any{
+					map[string]any{
+						"type": "oneWay",
+						"position": map[string]any{
+							"coin": "ETH",
+							"szi":  "0.123456789",
+						},
+					},
+				},
+			}
+		}
+		return okStatuses(
+			"order",
+			map[string]any{"resting": map[string]any{"oid": 1}},
+		)
+	})
+
+	_, err := e.MarketClose(
+		context.Background(),
+		MarketCloseRequest(
+			"ETH",
+			WithMarketCloseNativeMarket(),
+			WithMarketClosePrice(3000),
+		),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	last := ts.requests[len(ts.requests)-1]
+	order := last.Body["action"].(map[string]any)["orders"].([]any)[0]
+	if sz := order.(map[string]any)["s"]; sz != "0.1234" {
+		t.Fatalf("expected size 0.1234, got %v", sz)
+	}
+}
+
+func TestMarketCloseRejectsDustPosition(t *testing.T) {
+	e, ts := newTestExchange(t, func(path string, body map[string]any) any {
+		if path == "/info" {
+			return map[string]any{
+				"assetPositions": []any{
+					map[string]any{
+						"type": "oneWay",
+						"position": map[string]any{
+							"coin": "ETH",
+							"szi":  "0.00004",
+						},
+					},
+				},
+			}
+		}
+		return okStatuses(
+			"order",
+			map[string]any{"resting": map[string]any{"oid": 1}},
+		)
+	})
+
+	_, err := e.MarketClose(
+		context.Background(),
+		MarketCloseRequest(
+			"ETH",
+			WithMarketCloseNativeMarket(),
+			WithMarketClosePrice(3000),
+		),
+	)
+	if err == nil || !strings.Contains(err.Error(), "rounds down to zero") {
+		t.Fatalf("expected a zero size error, got %v", err)
+	}
+
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	for _, req := range ts.requests {
+		if req.Path == "/exchange" {
+			t.Fatal("expected no order to be posted")
+		}
+	}
+}
+
+func TestAgentKeyUsesAccountAddressForPositions(t *testing.T) {
+	account := common.HexToAddress(
+		"0x00000000000000000000000000000000000000aa",
+	)
+
+	var mu sync.Mutex
+	var users []string
+	e, _ := newTestExchange(t, func(path string, body map[string]any) any {
+		if path == "/info" {
+			mu.Lock()
+			users = append(users, fmt.Sprint(body["user"]))
+			mu.Unlock()
+			return map[string]any{
+				"assetPositions": []any{
+					map[string]any{
+						"type": "oneWay",
+						"position": map[string]any{
+							"coin": "ETH",
+							"szi":  "1",
+						},
+					},
+				},
+			}
+		}
+		return okStatuses(
+			"order",
+			map[string]any{"resting": map[string]any{"oid": 1}},
+		)
+	})
+	e.accountAddress = mo.Some(account)
+
+	ctx := context.Background()
+	if _, err := e.MarketClose(
+		ctx,
+		MarketCloseRequest(
+			"ETH",
+			WithMarketCloseNativeMarket(),
+			WithMarketClosePrice(3000),
+		),
+	); err != nil {
+		t.Fatalf("MarketClose: %v", err)
+	}
+	if _, err := e.SetPositionTpSl(
+		ctx, "ETH", &TriggerOrder{TriggerPx: 5000}, nil,
+	); err != nil {
+		t.Fatalf("SetPositionTpSl: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if len(users) == 0 {
+		t.Fatal("expected a user state lookup")
+	}
+	for _, u := range users {
+		if !strings.EqualFold(u, account.Hex()) {
+			t.Fatalf("user state looked up for %s, want %s", u, account)
+		}
+	}
+}
+
+func TestCancelAndCancelByCloidReturnCancelResponse(t *testing.T) {
+	e, _ := newTestExchange(t, func(path string, body map[string]any) any {
+		return okStatuses("cancel", "success")
+	})
+
+	var byOid CancelResponse
+	byOid, err := e.Cancel(context.Background(), CancelRequest("BTC", 1))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var byCloid CancelResponse
+	byCloid, err = e.CancelByCloid(
+		context.Background(),
+		CancelByCloidRequest(
+			"BTC",
+			types.MustHexToCloid("0x00000000000000000000000000000001"),
+		),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if byOid != byCloid || byCloid.Status != "success" {
+		t.Fatalf(
+			"expected matching success responses, got %v and %v",
+			byOid,
+			byCloid,
+		)
+	}
+}
+
+func TestCancelAll(t *testing.T) {
+	var openOrders []map[string]any
+	e, ts := newTestExchange(t, func(path string, body map[string]any) any {
+		if path == "/info" {
+			return openOrders
+		}
+		cancels := body["action"].(map[string]any)["cancels"].([]any)
+		statuses := make([]any, len(cancels))
+		for i := range statuses {
+			statuses[i] = "success"
+		}
+		return okStatuses("cancel", statuses...)
+	})
+	ctx := context.Background()
+
+	// cancelled returns the asset and oid of each cancel in the last action
+	cancelled := func() string {
+		ts.mu.Lock()
+		defer ts.mu.Unlock()
+		action := ts.requests[len(ts.requests)-1].Body["action"]
+		var parts []string
+		for _, c := range action.(map[string]any)["cancels"].([]any) {
+			cancel := c.(map[string]any)
+			parts = append(
+				parts,
+				fmt.Sprintf("%v/%v", cancel["a"], cancel["o"]),
+			)
+		}
+		return strings.Join(parts, ",")
+	}
+
+	// Nothing open sends no action
+	resp, err := e.CancelAll(ctx, nil)
+	if err != nil {
+		t.Fatalf("CancelAll failed: %v", err)
+	}
+	if len(resp) != 0 || len(ts.actions()) != 0 {
+		t.Fatalf("expected no cancels, got %v and %v", resp, ts.actions())
+	}
+
+	openOrders = []map[string]any{
+		{"coin": "BTC", "oid": 1, "side": "B", "limitPx": "50000", "sz": "1"},
+		{"coin": "ETH", "oid": 2, "side": "A", "limitPx": "3000", "sz": "2"},
+		{"coin": "BTC", "oid": 3, "side": "A", "limitPx": "60000", "sz": "1"},
+	}
+
+	resp, err = e.CancelAll(ctx, nil)
+	if err != nil {
+		t.Fatalf("CancelAll failed: %v", err)
+	}
+	if len(resp) != 3 {
+		t.Fatalf("expected 3 statuses, got %v", resp)
+	}
+	if got := cancelled(); got != "0/1,1/2,0/3" {
+		t.Errorf("expected cancels 0/1,1/2,0/3, got %s", got)
+	}
+
+	coin := "BTC"
+	resp, err = e.CancelAll(ctx, &coin)
+	if err != nil {
+		t.Fatalf("CancelAll failed: %v", err)
+	}
+	if len(resp) != 2 {
+		t.Fatalf("expected 2 statuses, got %v", resp)
+	}
+	if got := cancelled(); got != "0/1,0/3" {
+		t.Errorf("expected cancels 0/1,0/3, got %s", got)
+	}
+
+	actions := ts.actions()
+	if strings.Join(actions, ",") != "cancel,cancel" {
+		t.Fatalf("expected two cancel actions, got %v", actions)
+	}
+}
+
+func TestCancelAllChunking(t *testing.T) {
+	e, ts := newTestExchange(t, func(path string, body map[string]any) any {
+		if path == "/info" {
+			orders := make([]map[string]any, 5)
+			for i := range orders {
+				orders[i] = map[string]any{
+					"coin":    "BTC",
+					"oid":     i + 1,
+					"side":    "B",
+					"limitPx": "50000",
+					"sz":      "1",
+				}
+			}
+			return orders
+		}
+		cancels := body["action"].(map[string]any)["cancels"].([]any)
+		statuses := make([]any, len(cancels))
+		for i := range statuses {
+			statuses[i] = "success"
+		}
+		return okStatuses("cancel", statuses...)
+	})
+	e.maxOrders = 2
+
+	resp, err := e.CancelAll(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("CancelAll failed: %v", err)
+	}
+	if len(resp) != 5 {
+		t.Fatalf("expected 5 statuses, got %v", resp)
+	}
+
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	var sizes []int
+	nonces := map[any]bool{}
+	for _, r := range ts.requests {
+		if r.Path != "/exchange" {
+			continue
+		}
+		action := r.Body["action"].(map[string]any)
+		sizes = append(sizes, len(action["cancels"].([]any)))
+		nonces[r.Body["nonce"]] = true
+	}
+	if fmt.Sprint(sizes) != "[2 2 1]" {
+		t.Fatalf("expected cancel actions of 2, 2 and 1, got %v", sizes)
+	}
+	if len(nonces) != 3 {
+		t.Fatalf("expected a fresh nonce per action, got %v", nonces)
+	}
+}
+
+func TestBulkOrdersStatusErrorIdentifiesRejectedOrder(t *testing.T) {
+	resting := map[string]any{"resting": map[string]any{"oid": 1}}
+	e, _ := newTestExchange(t, func(path string, body map[string]any) any {
+		return okStatuses(
+			"order",
+			resting,
+			resting,
+			resting,
+			map[string]any{"error": "Order has invalid price."},
+		)
+	})
+
+	orders := make([]orderRequest, 4)
+	for i := range orders {
+		orders[i] = OrderRequest(
+			"BTC",
+			true,
+			0.01,
+			50000,
+			WithLimitOrder(LimitOrder{Tif: TifGtc}),
+		)
+	}
+
+	_, err := e.BulkOrders(context.Background(), orders)
+
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) {
+		t.Fatalf("expected StatusError, got %v", err)
+	}
+	if statusErr.Action != "order" {
+		t.Fatalf("expected action order, got %s", statusErr.Action)
+	}
+	if len(statusErr.Failures) != 1 {
+		t.Fatalf("expected 1 failure, got %d", len(statusErr.Failures))
+	}
+
+	failure := statusErr.Failures[0]
+	if failure.Index != 3 {
+		t.Fatalf("expected failure at index 3, got %d", failure.Index)
+	}
+	if failure.Err.Error() != "Order has invalid price." {
+		t.Fatalf("unexpected reason: %v", failure.Err)
+	}
+
+	if len(statusErr.Orders) != 4 || statusErr.Orders[0].Resting == nil {
+		t.Fatalf(
+			"expected the accepted orders to be decoded, got %v",
+			statusErr.Orders,
+		)
+	}
+	if !strings.Contains(string(statusErr.Body), "Order has invalid price.") {
+		t.Fatalf("expected raw body, got %s", statusErr.Body)
+	}
+}
+
+func TestTopLevelErrorIsStatusError(t *testing.T) {
+	e, _ := newTestExchange(t, func(path string, body map[string]any) any {
+		return map[string]any{
+			"status":   "err",
+			"response": "Vault not registered",
+		}
+	})
+
+	_, err := e.Cancel(context.Background(), CancelRequest("BTC", 1))
+
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) {
+		t.Fatalf("expected StatusError, got %v", err)
+	}
+	if statusErr.Message != "Vault not registered" ||
+		statusErr.Action != "cancel" {
+		t.Fatalf("unexpected status error: %+v", statusErr)
+	}
+	if err.Error() != "exchange error (action: cancel): Vault not registered" {
+		t.Fatalf("unexpected message: %v", err)
+	}
+}
+
+func TestWithBuilderWireShape(t *testing.T) {
+	e, ts := newTestExchange(t, func(path string, body map[string]any) any {
+		return okStatuses(
+			"order",
+			map[string]any{"resting": map[string]any{"oid": 1}},
+		)
+	})
+
+	builder := common.HexToAddress("0x8c967E73E7B15087c42A10D344cFf4c96D877f1D")
+	_, err := e.Order(
+		context.Background(),
+		OrderRequest(
+			"BTC",
+			true,
+			0.01,
+			50000,
+			WithLimitOrder(LimitOrder{Tif: TifGtc}),
+		),
+		WithBuilder(builder, 10),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	action := ts.requests[0].Body["action"].(map[string]any)
+	got, _ := json.Marshal(action["builder"])
+	expected := `{"b":"0x8c967e73e7b15087c42a10d344cff4c96d877f1d","f":10}`
+	if string(got) != expected {
+		t.Fatalf("expected builder %s, got %s", expected, got)
+	}
+}
+
+func TestWithBuilderFeeCheck(t *testing.T) {
+	e, ts := newTestExchange(t, func(path string, body map[string]any) any {
+		if path == "/info" && body["type"] == "maxBuilderFee" {
+			return 5
+		}
+		return okStatuses(
+			"order",
+			map[string]any{"resting": map[string]any{"oid": 1}},
+		)
+	})
+
+	builder := common.HexToAddress("0x8c967E73E7B15087c42A10D344cFf4c96D877f1D")
+	order := OrderRequest(
+		"BTC",
+		true,
+		0.01,
+		50000,
+		WithLimitOrder(LimitOrder{Tif: TifGtc}),
+	)
+
+	_, err := e.Order(
+		context.Background(),
+		order,
+		WithBuilder(builder, 10),
+		WithBuilderFeeCheck(),
+	)
+	if err == nil || !strings.Contains(err.Error(), "exceeds approved max 5") {
+		t.Fatalf("expected fee check error, got %v", err)
+	}
+	if got := ts.actions(); len(got) != 0 {
+		t.Fatalf("expected nothing posted, got %v", got)
+	}
+
+	_, err = e.Order(
+		context.Background(),
+		order,
+		WithBuilder(builder, 5),
+		WithBuilderFeeCheck(),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err = e.Order(context.Background(), order, WithBuilder(builder, -1))
+	if err == nil {
+		t.Fatal("expected error for negative builder fee")
+	}
+}
+
+func TestExpiresAfterOnlyOnL1Actions(t *testing.T) {
+	e, ts := newTestExchange(t, func(path string, body map[string]any) any {
+		return map[string]any{
+			"status":   "ok",
+			"response": map[string]any{"type": "default"},
+		}
+	})
+	e.SetExpiresAfter(30 * time.Second)
+
+	destination := common.HexToAddress(
+		"0x0000000000000000000000000000000000000001",
+	)
+	if _, err := e.UsdTransfer(
+		context.Background(),
+		10,
+		destination,
+	); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := e.UsdClassTransfer(context.Background(), 10, true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := e.UpdateLeverage(
+		context.Background(),
+		UpdateLeverageRequest("BTC", 5),
+	); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	for _, r := range ts.requests {
+		action := r.Body["action"].(map[string]any)
+		expiresAfter, ok := r.Body["expiresAfter"]
+		switch action["type"] {
+		case "usdSend", "usdClassTransfer":
+			if ok {
+				t.Fatalf(
+					"%s: expected no expiresAfter, got %v",
+					action["type"],
+					expiresAfter,
+				)
+			}
+		default:
+			if expiresAfter != float64(30000) {
+				t.Fatalf(
+					"%s: expected expiresAfter 30000, got %v",
+					action["type"],
+					expiresAfter,
+				)
+			}
+		}
+	}
+}
+
+// runMultiSig co-signs req with an authorized user key, submits it through
+// MultiSig and returns the inner action, the co-signer signature and the
+// posted request body
+func runMultiSig[T request](
+	t *testing.T,
+	req T,
+) (action, signature, map[string]any) {
+	t.Helper()
+
+	e, ts := newTestExchange(t, func(path string, body map[string]any) any {
+		return map[string]any{"status": "ok", "response": map[string]any{
+			"type": "default",
+		}}
+	})
+
+	ctx := context.Background()
+	multiSigUser := common.HexToAddress(
+		"0x0000000000000000000000000000000000000005",
+	)
+	nonce := int64(1764899871274)
+
+	sig, err := SignMultisigPayload(
+		ctx, e, req, e.privateKey, multiSigUser, nonce,
+	)
+	if err != nil {
+		t.Fatalf("SignMultisigPayload: %v", err)
+	}
+
+	_, err = MultiSig[any](
+		ctx,
+		e,
+		MultiSigRequest(multiSigUser, req, []signature{sig}, nonce),
+		e.privateKey,
+	)
+	if err != nil {
+		t.Fatalf("MultiSig: %v", err)
+	}
+
+	inner, err := req.toAction(ctx, e, nonce)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	return inner, sig, ts.requests[len(ts.requests)-1].Body
+}
+
+func TestMultiSigL1InnerAction(t *testing.T) {
+	e := testExchange(false)
+	req := OrderRequest(
+		"BTC",
+		true,
+		0.01,
+		50000,
+		WithLimitOrder(LimitOrder{Tif: TifGtc}),
+	)
+	inner, sig, body := runMultiSig(t, req)
+
+	a := body["action"].(map[string]any)
+	if a["type"] != "multiSig" {
+		t.Fatalf("expected multiSig action, got %v", a["type"])
+	}
+	payload := a["payload"].(map[string]any)
+	if typ := payload["action"].(map[string]any)["type"]; typ != "order" {
+		t.Fatalf("expected inner order action, got %v", typ)
+	}
+
+	outer := crypto.PubkeyToAddress(e.privateKey.PublicKey)
+	envelope := []any{
+		"0x0000000000000000000000000000000000000005",
+		strings.ToLower(outer.Hex()),
+		inner,
+	}
+	signer := recoverL1Signer(t, envelope, 1764899871274, sig, false)
+	if signer != outer {
+		t.Fatalf("co-signer mismatch: expected %s, got %s", outer, signer)
+	}
+}
+
+func TestMultiSigUserSignedInnerAction(t *testing.T) {
+	e := testExchange(false)
+	req := UsdTransferRequest(
+		1,
+		common.HexToAddress("0x0000000000000000000000000000000000000001"),
+	)
+	inner, sig, body := runMultiSig(t, req)
+
+	payload := body["action"].(map[string]any)["payload"].(map[string]any)
+	if typ := payload["action"].(map[string]any)["type"]; typ != "usdSend" {
+		t.Fatalf("expected inner usdSend action, got %v", typ)
+	}
+
+	outer := crypto.PubkeyToAddress(e.privateKey.PublicKey)
+	message := inner.getMap()
+	message["payloadMultiSigUser"] =
+		"0x0000000000000000000000000000000000000005"
+	message["outerSigner"] = strings.ToLower(outer.Hex())
+
+	hash, _, err := apitypes.TypedDataAndHash(userSignedPayload(
+		inner.getPrimaryType(),
+		addMultiSigTypes(inner.getPayloadTypes()),
+		message,
+		big.NewInt(constants.SIGNATURE_CHAIN_ID),
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rawSig := make([]byte, 65)
+	copy(rawSig[:32], sig.R[:])
+	copy(rawSig[32:64], sig.S[:])
+	rawSig[64] = sig.V - 27
+
+	pubKey, err := crypto.SigToPub(hash, rawSig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if signer := crypto.PubkeyToAddress(*pubKey); signer != outer {
+		t.Fatalf("co-signer mismatch: expected %s, got %s", outer, signer)
+	}
+}
+
+func TestSignForMultiSigTwoSigners(t *testing.T) {
+	e, _ := newTestExchange(t, func(path string, body map[string]any) any {
+		return nil
+	})
+	ctx := context.Background()
+
+	keyA, err := crypto.HexToECDSA(
+		"ac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80",
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyB, err := crypto.HexToECDSA(
+		"59c6995e998f97a5a0044966f0945389dc9e86dae88c7a8412f4603b6b78690d",
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	multiSigUser := common.HexToAddress(
+		"0x0000000000000000000000000000000000000005",
+	)
+	nonce := int64(1764899871274)
+	req := CancelRequest("BTC", 1)
+
+	sigA, err := SignForMultiSig(ctx, e, req, multiSigUser, nonce, keyA)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sigB, err := SignForMultiSig(ctx, e, req, multiSigUser, nonce, keyB)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	inner, err := req.toAction(ctx, e, nonce)
+	if err != nil {
+		t.Fatal(err)
+	}
+	envelope := []any{
+		strings.ToLower(multiSigUser.Hex()),
+		strings.ToLower(crypto.PubkeyToAddress(e.privateKey.PublicKey).Hex()),
+		inner,
+	}
+
+	for _, c := range []struct {
+		sig signature
+		key *ecdsa.PrivateKey
+	}{{sigA, keyA}, {sigB, keyB}} {
+		signer := recoverL1Signer(t, envelope, uint64(nonce), c.sig, false)
+		if want := crypto.PubkeyToAddress(c.key.PublicKey); signer != want {
+			t.Fatalf("signer mismatch: expected %s, got %s", want, signer)
+		}
+	}
+}
+
+func TestConvertFromMultiSigUser(t *testing.T) {
+	_, _, body := runMultiSig(t, ConvertFromMultiSigUserRequest())
+
+	payload := body["action"].(map[string]any)["payload"].(map[string]any)
+	a := payload["action"].(map[string]any)
+	if a["type"] != "convertToMultiSigUser" {
+		t.Fatalf("expected convertToMultiSigUser, got %v", a["type"])
+	}
+	if a["signers"] != "null" {
+		t.Fatalf("expected signers null, got %v", a["signers"])
+	}
+	if a["nonce"] != body["nonce"] {
+		t.Fatalf(
+			"action nonce %v does not match request nonce %v",
+			a["nonce"],
+			body["nonce"],
+		)
+	}
+}
+
+func TestNoop(t *testing.T) {
+	e, ts := newTestExchange(t, func(path string, body map[string]any) any {
+		return map[string]any{
+			"status":   "ok",
+			"response": map[string]any{"type": "default"},
+		}
+	})
+
+	if _, err := e.Noop(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	body := ts.requests[0].Body
+	if typ := body["action"].(map[string]any)["type"]; typ != "noop" {
+		t.Fatalf("expected noop action, got %v", typ)
+	}
+
+	var sig signature
+	raw, _ := json.Marshal(body["signature"])
+	if err := json.Unmarshal(raw, &sig); err != nil {
+		t.Fatal(err)
+	}
+
+	nonce := uint64(body["nonce"].(float64))
+	signer := recoverL1Signer(t, noopAction{Type: "noop"}, nonce, sig, false)
+	if want := crypto.PubkeyToAddress(e.privateKey.PublicKey); signer != want {
+		t.Fatalf("signer mismatch: expected %s, got %s", want, signer)
+	}
+}
+
+func TestCSignerActions(t *testing.T) {
+	e, ts := newTestExchange(t, func(path string, body map[string]any) any {
+		return map[string]any{
+			"status":   "ok",
+			"response": map[string]any{"type": "default"},
+		}
+	})
+
+	ctx := context.Background()
+	if _, err := e.CSignerJailSelf(ctx); err != nil {
+		t.Fatalf("CSignerJailSelf: %v", err)
+	}
+	if _, err := e.CSignerUnjailSelf(ctx); err != nil {
+		t.Fatalf("CSignerUnjailSelf: %v", err)
+	}
+
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	for i, variant := range []string{"jailSelf", "unjailSelf"} {
+		body := ts.requests[i].Body
+		a := body["action"].(map[string]any)
+		if a["type"] != "CSignerAction" {
+			t.Fatalf("expected CSignerAction, got %v", a["type"])
+		}
+		v, ok := a[variant]
+		if !ok || v != nil || len(a) != 2 {
+			t.Fatalf("expected only %s: null, got %v", variant, a)
+		}
+
+		var sig signature
+		raw, _ := json.Marshal(body["signature"])
+		if err := json.Unmarshal(raw, &sig); err != nil {
+			t.Fatal(err)
+		}
+		signer := recoverL1Signer(
+			t,
+			unitVariantAction{Type: "CSignerAction", Variant: variant},
+			uint64(body["nonce"].(float64)),
+			sig,
+			false,
+		)
+		want := crypto.PubkeyToAddress(e.privateKey.PublicKey)
+		if signer != want {
+			t.Fatalf("signer mismatch: expected %s, got %s", want, signer)
+		}
+	}
+}
+
+func TestCValidatorRegisterWireShape(t *testing.T) {
+	e, ts := newTestExchange(t, func(path string, body map[string]any) any {
+		return map[string]any{
+			"status":   "ok",
+			"response": map[string]any{"type": "default"},
+		}
+	})
+
+	_, err := e.CValidatorRegister(
+		context.Background(),
+		CValidatorProfile{
+			NodeIP:              "1.2.3.4",
+			Name:                "validator",
+			Description:         "desc",
+			DelegationsDisabled: true,
+			CommissionBps:       5,
+			Signer: common.HexToAddress(
+				"0x00000000000000000000000000000000000000AB",
+			),
+		},
+		false,
+		100,
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	got, err := json.Marshal(ts.requests[0].Body["action"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"register":{"initial_wei":100,"profile":{` +
+		`"commission_bps":5,"delegations_disabled":true,` +
+		`"description":"desc","name":"validator",` +
+		`"node_ip":{"Ip":"1.2.3.4"},` +
+		`"signer":"0x00000000000000000000000000000000000000ab"},` +
+		`"unjailed":false},"type":"CValidatorAction"}`
+	if string(got) != want {
+		t.Fatalf("expected %s, got %s", want, got)
+	}
+}
+
+func TestCValidatorChangeProfileOptionalFields(t *testing.T) {
+	e := testExchange(false)
+	name := "renamed"
+	nodeIP := "5.6.7.8"
+
+	action, err := CValidatorChangeProfileRequest(
+		true,
+		CValidatorProfileChanges{Name: &name, NodeIP: &nodeIP},
+	).toAction(context.Background(), e)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := json.Marshal(action)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"type":"CValidatorAction","changeProfile":{` +
+		`"node_ip":{"Ip":"5.6.7.8"},"name":"renamed",` +
+		`"description":null,"unjailed":true,` +
+		`"disable_delegations":null,"commission_bps":null,` +
+		`"signer":null}}`
+	if string(got) != want {
+		t.Fatalf("expected %s, got %s", want, got)
+	}
+
+	action, err = CValidatorChangeProfileRequest(
+		false,
+		CValidatorProfileChanges{},
+	).toAction(context.Background(), e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err = json.Marshal(action)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(got), `"node_ip":null`) {
+		t.Fatalf("expected null node_ip, got %s", got)
+	}
+}
+
+func TestCValidatorUnregister(t *testing.T) {
+	e := testExchange(false)
+
+	action, err := CValidatorUnregisterRequest().toAction(
+		context.Background(),
+		e,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := json.Marshal(action)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"type":"CValidatorAction","unregister":null}`
+	if string(got) != want {
+		t.Fatalf("expected %s, got %s", want, got)
+	}
+}
+
+func TestDexAbstraction(t *testing.T) {
+	e, ts := newTestExchange(t, func(path string, body map[string]any) any {
+		return map[string]any{
+			"status":   "ok",
+			"response": map[string]any{"type": "default"},
+		}
+	})
+
+	ctx := context.Background()
+	user := common.HexToAddress("0x00000000000000000000000000000000000000AB")
+	if _, err := e.AgentEnableDexAbstraction(ctx); err != nil {
+		t.Fatalf("AgentEnableDexAbstraction: %v", err)
+	}
+	if _, err := e.UserDexAbstraction(ctx, user, true); err != nil {
+		t.Fatalf("UserDexAbstraction: %v", err)
+	}
+
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	agent := ts.requests[0].Body["action"].(map[string]any)
+	if agent["type"] != "agentEnableDexAbstraction" {
+		t.Fatalf("expected agentEnableDexAbstraction, got %v", agent["type"])
+	}
+
+	body := ts.requests[1].Body
+	raw, err := json.Marshal(body["action"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var a userDexAbstractionAction
+	if err := json.Unmarshal(raw, &a); err != nil {
+		t.Fatal(err)
+	}
+	if a.Type != "userDexAbstraction" || !a.Enabled ||
+		a.User != strings.ToLower(user.Hex()) {
+		t.Fatalf("unexpected action: %+v", a)
+	}
+	const primaryType = "HyperliquidTransaction:UserDexAbstraction"
+	if got := a.getPrimaryType(); got != primaryType {
+		t.Fatalf("expected primary type %s, got %s", primaryType, got)
+	}
+
+	var sig signature
+	raw, _ = json.Marshal(body["signature"])
+	if err := json.Unmarshal(raw, &sig); err != nil {
+		t.Fatal(err)
+	}
+	signer := recoverUserSignedSigner(
+		t,
+		a,
+		sig,
+		constants.SIGNATURE_CHAIN_ID,
+	)
+	if want := crypto.PubkeyToAddress(e.privateKey.PublicKey); signer != want {
+		t.Fatalf("signer mismatch: expected %s, got %s", want, signer)
+	}
+}
+
+func TestSpotSlippagePrice(t *testing.T) {
+	srv := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(map[string]string{
+				"BTC":       "50000",
+				"PURR/USDC": "0.2",
+				"@1":        "25.123456",
+			})
+		}),
+	)
+	t.Cleanup(srv.Close)
+
+	e, err := New(Config{
+		BaseURL:    srv.URL,
+		SkipWS:     true,
+		PrivateKey: testPrivateKey(),
+		Meta: &info.Meta{
+			Universe: []info.AssetInfo{{Name: "BTC", SzDecimals: 5}},
+		},
+		SpotMeta: &info.SpotMeta{
+			Universe: []info.SpotAssetInfo{
+				{Name: "PURR/USDC", Tokens: [2]int64{1, 0}, Index: 0},
+				{Name: "@1", Tokens: [2]int64{2, 0}, Index: 1},
+			},
+			Tokens: []info.SpotTokenInfo{
+				{Name: "USDC", SzDecimals: 8, Index: 0},
+				{Name: "PURR", SzDecimals: 0, Index: 1},
+				{Name: "HYPE", SzDecimals: 2, Index: 2},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to create exchange: %v", err)
+	}
+
+	tests := []struct {
+		coin string
+		want float64
+	}{
+		// 25.123456 * 1.05 = 26.3796288, 5 sig figs and 8-2 decimals
+		{"HYPE/USDC", 26.38},
+		{"@1", 26.38},
+		// 0.2 * 1.05 = 0.21, 8-0 decimals
+		{"PURR/USDC", 0.21},
+		// 50000 * 1.05 = 52500, 6-5 decimals
+		{"BTC", 52500},
+	}
+	for _, tt := range tests {
+		px, err := e.getSlippagePrice(
+			context.Background(),
+			tt.coin,
+			true,
+			0.05,
+			mo.None[float64](),
+		)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.coin, err)
+		}
+		if px != tt.want {
+			t.Fatalf("%s: expected %v, got %v", tt.coin, tt.want, px)
+		}
+	}
+}
+
+func TestSlippagePriceUsesCachedMids(t *testing.T) {
+	var midRequests atomic.Int64
+	e, _ := newTestExchange(t, func(path string, body map[string]any) any {
+		midRequests.Add(1)
+		return map[string]string{"BTC": "50000"}
+	})
+
+	ctx := context.Background()
+
+	// A fresh mid, as delivered by StreamMids, is used without a REST call
+	e.storeMids("", map[string]float64{"BTC": 40000}, time.Minute)
+	px, err := e.getSlippagePrice(ctx, "BTC", true, 0.05, mo.None[float64]())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if px != 42000 {
+		t.Fatalf("expected price from cached mid 42000, got %v", px)
+	}
+	if n := midRequests.Load(); n != 0 {
+		t.Fatalf("expected no mid requests, got %d", n)
+	}
+
+	// Once stale, the mid is fetched again
+	e.storeMids("", map[string]float64{"BTC": 40000}, -time.Second)
+	px, err = e.getSlippagePrice(ctx, "BTC", true, 0.05, mo.None[float64]())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if px != 52500 {
+		t.Fatalf("expected price from fetched mid 52500, got %v", px)
+	}
+	if n := midRequests.Load(); n != 1 {
+		t.Fatalf("expected 1 mid request, got %d", n)
+	}
+}
+
+func TestMidCacheTTL(t *testing.T) {
+	var midRequests atomic.Int64
+	e, _ := newTestExchange(t, func(path string, body map[string]any) any {
+		midRequests.Add(1)
+		return map[string]string{"BTC": "50000"}
+	})
+	e.midCacheTTL = time.Minute
+
+	for range 3 {
+		if _, err := e.getSlippagePrice(
+			context.Background(),
+			"BTC",
+			false,
+			0.05,
+			mo.None[float64](),
+		); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if n := midRequests.Load(); n != 1 {
+		t.Fatalf("expected 1 mid request, got %d", n)
+	}
+}
+
+func TestBulkOrdersChunking(t *testing.T) {
+	var oid atomic.Int64
+	e, ts := newTestExchange(t, func(path string, body map[string]any) any {
+		orders := body["action"].(map[string]any)["orders"].([]any)
+		statuses := make([]any, len(orders))
+		for i := range orders {
+			statuses[i] = map[string]any{
+				"resting": map[string]any{"oid": oid.Add(1)},
+			}
+		}
+		return okStatuses("order", statuses...)
+	})
+	e.maxOrders = 2
+
+	requests := make([]orderRequest, 5)
+	for i := range requests {
+		requests[i] = OrderRequest(
+			"BTC",
+			true,
+			0.01,
+			float64(50000+i),
+			WithLimitOrder(LimitOrder{Tif: TifGtc}),
+		)
+	}
+
+	resp, err := e.BulkOrders(context.Background(), requests)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(resp) != len(requests) {
+		t.Fatalf("expected %d statuses, got %d", len(requests), len(resp))
+	}
+	for i, status := range resp {
+		if status.Resting == nil || status.Resting.Oid != int64(i+1) {
+			t.Fatalf("status %d out of order: %+v", i, status)
+		}
+	}
+
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	if len(ts.requests) != 3 {
+		t.Fatalf("expected 3 posts, got %d", len(ts.requests))
+	}
+	nonces := map[any]bool{}
+	for i, want := range []int{2, 2, 1} {
+		body := ts.requests[i].Body
+		orders := body["action"].(map[string]any)["orders"].([]any)
+		if len(orders) != want {
+			t.Fatalf(
+				"post %d: expected %d orders, got %d",
+				i,
+				want,
+				len(orders),
+			)
+		}
+		nonces[body["nonce"]] = true
+	}
+	if len(nonces) != 3 {
+		t.Fatalf("expected a fresh nonce per post, got %v", nonces)
+	}
+
+	// The last order of the first post is the second request
+	first := ts.requests[0].Body["action"].(map[string]any)["orders"].([]any)
+	if px := first[1].(map[string]any)["p"]; px != "50001" {
+		t.Fatalf("expected second order at 50001, got %v", px)
+	}
+}
+
+func TestBulkOrdersChunkingReportsGlobalIndex(t *testing.T) {
+	var posts atomic.Int64
+	e, _ := newTestExchange(t, func(path string, body map[string]any) any {
+		if posts.Add(1) == 2 {
+			return okStatuses(
+				"order",
+				map[string]any{"resting": map[string]any{"oid": 3}},
+				map[string]any{"error": "Order has invalid price."},
+			)
+		}
+		return okStatuses(
+			"order",
+			map[string]any{"resting": map[string]any{"oid": 1}},
+			map[string]any{"resting": map[string]any{"oid": 2}},
+		)
+	})
+	e.maxOrders = 2
+
+	requests := make([]orderRequest, 4)
+	for i := range requests {
+		requests[i] = OrderRequest(
+			"BTC",
+			true,
+			0.01,
+			50000,
+			WithLimitOrder(LimitOrder{Tif: TifGtc}),
+		)
+	}
+
+	resp, err := e.BulkOrders(context.Background(), requests)
+
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) {
+		t.Fatalf("expected StatusError, got %v", err)
+	}
+	if len(statusErr.Failures) != 1 || statusErr.Failures[0].Index != 3 {
+		t.Fatalf("expected failure at index 3, got %+v", statusErr.Failures)
+	}
+	if len(resp) != 4 || resp[2].Resting == nil || resp[2].Resting.Oid != 3 {
+		t.Fatalf("unexpected statuses: %+v", resp)
+	}
+}
+
+func TestOrderIdempotentLooksUpCloidAfterTimeout(t *testing.T) {
+	var exchangePosts atomic.Int32
+	var lookup map[string]any
+	srv := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var body map[string]any
+			_ = json.NewDecoder(r.Body).Decode(&body)
+
+			if r.URL.Path == "/exchange" {
+				// Accept the order but answer after the client gave up
+				exchangePosts.Add(1)
+				time.Sleep(200 * time.Millisecond)
+				return
+			}
+
+			lookup = body
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(restingOrderStatus(42, false))
+		}),
+	)
+	t.Cleanup(srv.Close)
+
+	e, err := New(Config{
+		BaseURL:    srv.URL,
+		Timeout:    50 * time.Millisecond,
+		SkipWS:     true,
+		PrivateKey: testPrivateKey(),
+		Meta: &info.Meta{
+			Universe: []info.AssetInfo{{Name: "BTC", SzDecimals: 5}},
+		},
+		SpotMeta: &info.SpotMeta{},
+	})
+	if err != nil {
+		t.Fatalf("failed to create exchange: %v", err)
+	}
+
+	cloid := types.MustHexToCloid("0x00000000000000000000000000000001")
+	resp, err := e.OrderIdempotent(
+		context.Background(),
+		OrderRequest(
+			"BTC",
+			true,
+			0.01,
+			50000,
+			WithLimitOrder(LimitOrder{Tif: TifGtc}),
+			WithCloid(cloid),
+		),
+	)
+	if err != nil {
+		t.Fatalf("OrderIdempotent failed: %v", err)
+	}
+
+	if resp.Resting == nil || resp.Resting.Oid != 42 {
+		t.Fatalf("expected resting order 42, got %+v", resp)
+	}
+	if resp.Resting.Status != "open" {
+		t.Errorf("expected status open, got %q", resp.Resting.Status)
+	}
+	if n := exchangePosts.Load(); n != 1 {
+		t.Errorf("expected the order to be posted once, got %d", n)
+	}
+	if lookup["type"] != "orderStatus" || lookup["oid"] != cloid.Hex() {
+		t.Errorf("unexpected lookup: %v", lookup)
+	}
+}
+
+func TestOrderIdempotentResubmitsUnknownCloid(t *testing.T) {
+	cloid := types.MustHexToCloid("0x00000000000000000000000000000002")
+	var exchangePosts atomic.Int32
+	e, _ := newTestExchange(t, func(path string, body map[string]any) any {
+		if path == "/info" {
+			return map[string]any{"status": "unknownOid"}
+		}
+		if exchangePosts.Add(1) == 1 {
+			// Not a valid response, so the outcome is unknown
+			return "bad gateway"
+		}
+		return okStatuses(
+			"order",
+			map[string]any{"resting": map[string]any{"oid": 7}},
+		)
+	})
+
+	resp, err := e.OrderIdempotent(
+		context.Background(),
+		OrderRequest(
+			"BTC",
+			true,
+			0.01,
+			50000,
+			WithLimitOrder(LimitOrder{Tif: TifGtc}),
+			WithCloid(cloid),
+		),
+	)
+	if err != nil {
+		t.Fatalf("OrderIdempotent failed: %v", err)
+	}
+	if resp.Resting == nil || resp.Resting.Oid != 7 {
+		t.Fatalf("expected resubmitted order 7, got %+v", resp)
+	}
+	if n := exchangePosts.Load(); n != 2 {
+		t.Errorf("expected 2 order posts, got %d", n)
+	}
+}
+
+func TestOrderIdempotentReturnsLocalErrors(t *testing.T) {
+	e, ts := newTestExchange(t, func(path string, body map[string]any) any {
+		return okStatuses(
+			"order",
+			map[string]any{"resting": map[string]any{"oid": 1}},
+		)
+	})
+	cloid := types.MustHexToCloid("0x00000000000000000000000000000003")
+
+	for _, request := range []orderRequest{
+		OrderRequest(
+			"NOPE",
+			true,
+			0.01,
+			50000,
+			WithLimitOrder(LimitOrder{Tif: TifGtc}),
+			WithCloid(cloid),
+		),
+		OrderRequest(
+			"BTC",
+			true,
+			0.01,
+			50000,
+			WithLimitOrder(LimitOrder{Tif: "Fok"}),
+			WithCloid(cloid),
+		),
+	} {
+		_, err := e.OrderIdempotent(context.Background(), request)
+		if err == nil {
+			t.Fatal("expected an error")
+		}
+	}
+
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	if len(ts.requests) != 0 {
+		t.Fatalf(
+			"expected no lookup or resubmit, got %d requests",
+			len(ts.requests),
+		)
+	}
+}
+
+func TestOrderIdempotentRequiresCloid(t *testing.T) {
+	e := testExchange(false)
+
+	_, err := e.OrderIdempotent(
+		context.Background(),
+		OrderRequest(
+			"BTC",
+			true,
+			0.01,
+			50000,
+			WithLimitOrder(LimitOrder{Tif: TifGtc}),
+		),
+	)
+	if err == nil {
+		t.Fatal("expected an error for an order without a cloid")
+	}
+}
+
+func TestValidateOrder(t *testing.T) {
+	e, _ := newTestExchange(t, func(path string, body map[string]any) any {
+		return nil
+	})
+
+	limit := WithLimitOrder(LimitOrder{Tif: TifGtc})
+	tests := []struct {
+		name    string
+		request orderRequest
+		want    error
+	}{
+		{
+			"valid",
+			OrderRequest("BTC", true, 0.01, 5000.5, limit),
+			nil,
+		},
+		{
+			"integer price beyond 5 significant figures",
+			OrderRequest("BTC", true, 0.01, 123456, limit),
+			nil,
+		},
+		{
+			"too many significant figures",
+			OrderRequest("BTC", true, 0.01, 12345.6, limit),
+			ErrInvalidPrice,
+		},
+		{
+			"too many decimals",
+			OrderRequest("ETH", true, 0.01, 3000.25, limit),
+			ErrInvalidPrice,
+		},
+		{
+			"trigger price",
+			OrderRequest(
+				"BTC",
+				false,
+				0.01,
+				48000,
+				WithTriggerOrder(TriggerOrder{
+					IsMarket:  true,
+					TriggerPx: 48000.25,
+					TpSl:      "sl",
+				}),
+			),
+			ErrInvalidPrice,
+		},
+		{
+			"sub-precision size",
+			OrderRequest("BTC", true, 0.000001, 50000, limit),
+			ErrInvalidSize,
+		},
+		{
+			"size with too many decimals",
+			OrderRequest("ETH", true, 0.12345, 3000, limit),
+			ErrInvalidSize,
+		},
+	}
+
+	for _, tt := range tests {
+		err := e.ValidateOrder(tt.request)
+		if tt.want == nil && err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.name, err)
+		}
+		if tt.want != nil && !errors.Is(err, tt.want) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, err)
+		}
+	}
+}
+
+func TestRoundOrder(t *testing.T) {
+	e, _ := newTestExchange(t, func(path string, body map[string]any) any {
+		return nil
+	})
+
+	rounded, err := e.RoundOrder(
+		OrderRequest(
+			"BTC",
+			true,
+			0.0123456,
+			12345.67,
+			WithTriggerOrder(TriggerOrder{TriggerPx: 12000.04, TpSl: "tp"}),
+		),
+	)
+	if err != nil {
+		t.Fatalf("RoundOrder failed: %v", err)
+	}
+	if rounded.limitPx != 12346 {
+		t.Errorf("expected price 12346, got %v", rounded.limitPx)
+	}
+	if rounded.orderType.Trigger.TriggerPx != 12000 {
+		t.Errorf(
+			"expected trigger price 12000, got %v",
+			rounded.orderType.Trigger.TriggerPx,
+		)
+	}
+	if rounded.sz != 0.01235 {
+		t.Errorf("expected size 0.01235, got %v", rounded.sz)
+	}
+
+	_, err = e.RoundOrder(
+		OrderRequest(
+			"BTC",
+			true,
+			0.000001,
+			50000,
+			WithLimitOrder(LimitOrder{Tif: TifGtc}),
+		),
+	)
+	if !errors.Is(err, ErrInvalidSize) {
+		t.Errorf("expected ErrInvalidSize for a zero size, got %v", err)
+	}
+}
+
+func TestOrderWithVault(t *testing.T) {
+	e, ts := newTestExchange(t, func(path string, body map[string]any) any {
+		return okStatuses(
+			"order",
+			map[string]any{"resting": map[string]any{"oid": 1}},
+		)
+	})
+	vault := common.HexToAddress("0x1719884eb866cb12b2287399b15f7db5e7d775ea")
+
+	request := OrderRequest(
+		"BTC",
+		true,
+		0.01,
+		50000,
+		WithLimitOrder(LimitOrder{Tif: TifGtc}),
+	)
+	_, err := e.Order(context.Background(), request, WithVault(vault))
+	if err != nil {
+		t.Fatalf("Order failed: %v", err)
+	}
+
+	ts.mu.Lock()
+	body := ts.requests[0].Body
+	ts.mu.Unlock()
+
+	if got, _ := body["vaultAddress"].(string); !strings.EqualFold(
+		got,
+		vault.Hex(),
+	) {
+		t.Errorf("expected vaultAddress %s, got %v", vault.Hex(), got)
+	}
+
+	// The signature must commit to the per-call vault
+	raw, _ := json.Marshal(body["signature"])
+	var sig signature
+	if err := json.Unmarshal(raw, &sig); err != nil {
+		t.Fatalf("failed to decode signature: %v", err)
+	}
+
+	wire, err := request.toOrderWire(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	action := ordersToAction(
+		[]orderWire{wire},
+		mo.None[BuilderInfo](),
+		mo.None[OrderGrouping](),
+	)
+	expected, err := signL1Action(
+		action,
+		uint64(body["nonce"].(float64)),
+		e.privateKey,
+		mo.Some(vault),
+		mo.None[time.Duration](),
+		false,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sig != expected {
+		t.Error("signature does not cover the per-call vault address")
+	}
+
+	// Later calls without the option are unaffected
+	_, err = e.Order(context.Background(), request)
+	if err != nil {
+		t.Fatalf("Order failed: %v", err)
+	}
+
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	if v := ts.requests[1].Body["vaultAddress"]; v != nil {
+		t.Errorf("expected no vaultAddress, got %v", v)
+	}
+}
+
+// logEntry is a single event recorded by captureLogger
+type logEntry struct {
+	level   string
+	msg     string
+	keyvals []any
+}
+
+// captureLogger records every event it receives
+type captureLogger struct {
+	mu      sync.Mutex
+	entries []logEntry
+}
+
+func (l *captureLogger) log(level, msg string, keyvals []any) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries = append(l.entries, logEntry{level, msg, keyvals})
+}
+
+func (l *captureLogger) Debug(msg string, kv ...any) { l.log("debug", msg, kv) }
+func (l *captureLogger) Info(msg string, kv ...any)  { l.log("info", msg, kv) }
+func (l *captureLogger) Warn(msg string, kv ...any)  { l.log("warn", msg, kv) }
+func (l *captureLogger) Error(msg string, kv ...any) { l.log("error", msg, kv) }
+
+func (l *captureLogger) byLevel(level string) []logEntry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var entries []logEntry
+	for _, entry := range l.entries {
+		if entry.level == level {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+func TestPostFailureLoggedOnce(t *testing.T) {
+	e, _ := newTestExchange(t, func(path string, body map[string]any) any {
+		return "bad gateway"
+	})
+	logger := &captureLogger{}
+	e.logger = logger
+
+	_, err := e.Order(
+		context.Background(),
+		OrderRequest(
+			"BTC",
+			true,
+			0.01,
+			50000,
+			WithLimitOrder(LimitOrder{Tif: TifGtc}),
+		),
+	)
+	if err == nil {
+		t.Fatal("expected the order to fail")
+	}
+
+	failures := logger.byLevel("error")
+	if len(failures) != 1 {
+		t.Fatalf("expected 1 error event, got %d: %+v", len(failures), failures)
+	}
+	if failures[0].msg != "exchange post failed" ||
+		failures[0].keyvals[0] != "type" || failures[0].keyvals[1] != "order" {
+		t.Errorf("unexpected error event: %+v", failures[0])
+	}
+
+	var nonceSelected bool
+	for _, entry := range logger.byLevel("debug") {
+		nonceSelected = nonceSelected || entry.msg == "selected nonce"
+	}
+	if !nonceSelected {
+		t.Error("expected the nonce selection to be logged")
+	}
+}
+
+func TestPreviewMatchesLivePayload(t *testing.T) {
+	e, ts := newTestExchange(t, func(path string, body map[string]any) any {
+		return map[string]any{
+			"status":   "ok",
+			"response": map[string]any{"type": "default"},
+		}
+	})
+	ctx := context.Background()
+	req := UsdTransferRequest(
+		12.5,
+		common.HexToAddress("0x0000000000000000000000000000000000000001"),
+	)
+
+	if _, err := e.UsdTransfer(ctx, 12.5, req.destination); err != nil {
+		t.Fatalf("UsdTransfer failed: %v", err)
+	}
+
+	ts.mu.Lock()
+	posted := ts.requests[0].Body
+	ts.mu.Unlock()
+
+	nonce := int64(posted["nonce"].(float64))
+	previewed, err := preview(ctx, e, req, nonce)
+	if err != nil {
+		t.Fatalf("preview failed: %v", err)
+	}
+
+	// Round trip through JSON so both sides hold the same value types
+	raw, _ := json.Marshal(previewed.Payload)
+	var payload map[string]any
+	if err := json.Unmarshal(raw, &payload); err != nil {
+		t.Fatal(err)
+	}
+	want, _ := json.Marshal(posted)
+	got, _ := json.Marshal(payload)
+	if string(got) != string(want) {
+		t.Errorf("preview payload mismatch:\n got %s\nwant %s", got, want)
+	}
+
+	sig, _ := json.Marshal(previewed.Signature)
+	postedSig, _ := json.Marshal(posted["signature"])
+	if string(sig) != string(postedSig) {
+		t.Errorf("signature mismatch: got %s, want %s", sig, postedSig)
+	}
+
+	// Preview takes its own nonce and never posts
+	p, err := Preview(ctx, e, req)
+	if err != nil {
+		t.Fatalf("Preview failed: %v", err)
+	}
+	if p.Nonce <= nonce || p.Payload["nonce"] != p.Nonce {
+		t.Errorf("expected a fresh nonce above %d, got %d", nonce, p.Nonce)
+	}
+	if typ := p.Action.getType(); typ != "usdSend" {
+		t.Errorf("expected usdSend action, got %s", typ)
+	}
+
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	if len(ts.requests) != 1 {
+		t.Errorf("expected 1 posted request, got %d", len(ts.requests))
+	}
+}
+
+// recordingRestClient is a rest.ClientInterface that records every post and
+// answers with a fixed response
+type recordingRestClient struct {
+	response string
+	paths    []string
+	bodies   []string
+}
+
+func (r *recordingRestClient) BaseUrl() string     { return "mock" }
+func (r *recordingRestClient) IsMainnet() bool     { return false }
+func (r *recordingRestClient) NetworkName() string { return "Testnet" }
+
+func (r *recordingRestClient) Post(
+	ctx context.Context,
+	path string,
+	body any,
+	result any,
+) error {
+	raw, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	r.paths = append(r.paths, path)
+	r.bodies = append(r.bodies, string(raw))
+	return json.Unmarshal([]byte(r.response), result)
+}
+
+func TestSubmitSignedPayload(t *testing.T) {
+	e, ts := newTestExchange(t, func(path string, body map[string]any) any {
+		return nil
+	})
+	ctx := context.Background()
+
+	// Sign offline and carry the payload over as JSON
+	signed, err := Preview(ctx, e, UsdTransferRequest(
+		3,
+		common.HexToAddress("0x0000000000000000000000000000000000000001"),
+	))
+	if err != nil {
+		t.Fatalf("Preview failed: %v", err)
+	}
+	raw, err := json.Marshal(signed.Payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var payload map[string]any
+	if err := json.Unmarshal(raw, &payload); err != nil {
+		t.Fatal(err)
+	}
+
+	client := &recordingRestClient{
+		response: `{"status":"ok","response":{"type":"default"}}`,
+	}
+	resp, err := SubmitSignedPayload[UpdateResponse](ctx, client, payload)
+	if err != nil {
+		t.Fatalf("SubmitSignedPayload failed: %v", err)
+	}
+	if resp.Type != "default" {
+		t.Errorf("expected default response, got %q", resp.Type)
+	}
+
+	if len(client.paths) != 1 || client.paths[0] != "/exchange" {
+		t.Fatalf("expected one post to /exchange, got %v", client.paths)
+	}
+	want, _ := json.Marshal(payload)
+	if client.bodies[0] != string(want) {
+		t.Errorf(
+			"posted body mismatch:\n got %s\nwant %s",
+			client.bodies[0],
+			want,
+		)
+	}
+
+	client.response = `{"status":"err","response":"Insufficient balance"}`
+	_, err = SubmitSignedPayload[UpdateResponse](ctx, client, payload)
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) {
+		t.Fatalf("expected StatusError, got %v", err)
+	}
+	if statusErr.Action != "usdSend" {
+		t.Errorf("expected usdSend action, got %q", statusErr.Action)
+	}
+
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	if len(ts.requests) != 0 {
+		t.Errorf("expected no posts by the exchange, got %d", len(ts.requests))
+	}
+}
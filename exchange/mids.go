@@ -0,0 +1,96 @@
+package exchange
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/banky/go-hyperliquid/ws"
+)
+
+// defaultStreamMidTTL bounds the age of a streamed mid when no MidCacheTTL is
+// configured
+const defaultStreamMidTTL = 5 * time.Second
+
+type cachedMids struct {
+	mids    map[string]float64
+	expires time.Time
+}
+
+// allMids returns the mid prices for dex, from the cache when a fresh entry
+// exists and from the info API otherwise
+func (e *Exchange) allMids(
+	ctx context.Context,
+	dex string,
+) (map[string]float64, error) {
+	e.midsMu.Lock()
+	cached, ok := e.mids[dex]
+	e.midsMu.Unlock()
+	if ok && time.Now().Before(cached.expires) {
+		return cached.mids, nil
+	}
+
+	mids, err := e.info.AllMids(ctx, dex)
+	if err != nil {
+		return nil, err
+	}
+
+	if e.midCacheTTL > 0 {
+		e.storeMids(dex, mids, e.midCacheTTL)
+	}
+
+	return mids, nil
+}
+
+// storeMids caches mids for dex for ttl
+func (e *Exchange) storeMids(
+	dex string,
+	mids map[string]float64,
+	ttl time.Duration,
+) {
+	e.midsMu.Lock()
+	defer e.midsMu.Unlock()
+
+	e.mids[dex] = cachedMids{mids: mids, expires: time.Now().Add(ttl)}
+}
+
+// StreamMids keeps the mid prices of the default dex up to date from the
+// allMids websocket feed until ctx is cancelled, so market orders are priced
+// without a REST round trip. Each update is used for MidCacheTTL, or five
+// seconds if it is unset, after which orders fall back to REST until the next
+// update arrives. The info client must have its websocket enabled.
+func (e *Exchange) StreamMids(ctx context.Context) error {
+	if e.info == nil {
+		return fmt.Errorf("info client is required to stream mids")
+	}
+
+	ttl := e.midCacheTTL
+	if ttl <= 0 {
+		ttl = defaultStreamMidTTL
+	}
+
+	ch := make(chan ws.AllMidsMessage, 1)
+	sub, err := e.info.SubscribeAllMids(ctx, ch)
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to mids: %w", err)
+	}
+
+	go func() {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg := <-ch:
+				mids, err := msg.Floats()
+				if err != nil {
+					e.logger.Warn("failed to parse streamed mids", "err", err)
+					continue
+				}
+				e.storeMids("", mids, ttl)
+			}
+		}
+	}()
+
+	return nil
+}
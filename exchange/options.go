@@ -1,6 +1,7 @@
 package exchange
 
 import (
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/samber/mo"
 )
 
@@ -13,8 +14,10 @@ import (
 type orderOption func(*orderConfig)
 
 type orderConfig struct {
-	builder  mo.Option[BuilderInfo]
-	grouping mo.Option[OrderGrouping]
+	builder         mo.Option[BuilderInfo]
+	grouping        mo.Option[OrderGrouping]
+	checkBuilderFee bool
+	vault           mo.Option[common.Address]
 }
 
 // WithBuilderInfo sets the builder info for the order
@@ -24,6 +27,21 @@ func WithBuilderInfo(builder BuilderInfo) orderOption {
 	}
 }
 
+// WithBuilder routes the order through a builder, charging fee in tenths of
+// a basis point. For example 10 means 1 basis point.
+func WithBuilder(builder common.Address, fee int64) orderOption {
+	return WithBuilderInfo(BuilderInfo{PublicAddress: builder, FeeAmount: fee})
+}
+
+// WithBuilderFeeCheck looks up the max fee the user has approved for the
+// builder and rejects the order before signing if the builder fee exceeds
+// it. This costs an extra info request per call.
+func WithBuilderFeeCheck() orderOption {
+	return func(cfg *orderConfig) {
+		cfg.checkBuilderFee = true
+	}
+}
+
 func withBuilderInfo(builder mo.Option[BuilderInfo]) orderOption {
 	return func(cfg *orderConfig) {
 		cfg.builder = builder
@@ -36,6 +54,19 @@ func WithGrouping(grouping OrderGrouping) orderOption {
 	}
 }
 
+// WithVault trades on behalf of vault for this call only, overriding
+// Config.VaultAddress. The vault is signed over and sent in the payload, and
+// positions, order lookups and builder fee checks use the vault's account.
+func WithVault(vault common.Address) orderOption {
+	return withVault(mo.Some(vault))
+}
+
+func withVault(vault mo.Option[common.Address]) orderOption {
+	return func(cfg *orderConfig) {
+		cfg.vault = vault
+	}
+}
+
 /*//////////////////////////////////////////////////////////////
                           MODIFY ORDER
 //////////////////////////////////////////////////////////////*/
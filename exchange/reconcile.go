@@ -0,0 +1,82 @@
+package exchange
+
+import (
+	"math"
+
+	"github.com/banky/go-hyperliquid/info"
+)
+
+// reconcileEpsilon absorbs float rounding when comparing prices and sizes
+const reconcileEpsilon = 1e-9
+
+// DesiredQuote is a resting order that a quoting strategy wants to have on the
+// book
+type DesiredQuote struct {
+	// Coin is the coin as reported by the exchange (e.g. "BTC" or "@107")
+	Coin  string
+	IsBuy bool
+	Px    float64
+	Sz    float64
+	// PxTolerance is how far a resting order's price may be from Px and
+	// still be kept
+	PxTolerance float64
+	// SzTolerance is how far a resting order's remaining size may be from Sz
+	// and still be kept
+	SzTolerance float64
+}
+
+// matches reports whether a resting order satisfies the quote
+func (q DesiredQuote) matches(order info.FrontendOpenOrder) bool {
+	if order.Coin != q.Coin {
+		return false
+	}
+
+	isBuy := order.Side == "B"
+	if isBuy != q.IsBuy {
+		return false
+	}
+
+	pxDiff := math.Abs(order.LimitPx.Raw() - q.Px)
+	szDiff := math.Abs(order.Sz.Raw() - q.Sz)
+
+	return pxDiff <= q.PxTolerance+reconcileEpsilon &&
+		szDiff <= q.SzTolerance+reconcileEpsilon
+}
+
+// ReconcileQuotes diffs the resting orders against the desired quotes and
+// returns the smallest set of changes that converges them. Resting orders
+// that already satisfy a desired quote are left alone so they keep their
+// queue priority. Every other resting order is returned in toCancel, and
+// every desired quote without a matching resting order is returned in
+// toPlace.
+func ReconcileQuotes(
+	current []info.FrontendOpenOrder,
+	desired []DesiredQuote,
+) (toCancel []int64, toPlace []DesiredQuote) {
+	matched := make([]bool, len(desired))
+
+	for _, order := range current {
+		kept := false
+		for i, quote := range desired {
+			if matched[i] || !quote.matches(order) {
+				continue
+			}
+
+			matched[i] = true
+			kept = true
+			break
+		}
+
+		if !kept {
+			toCancel = append(toCancel, order.Oid)
+		}
+	}
+
+	for i, quote := range desired {
+		if !matched[i] {
+			toPlace = append(toPlace, quote)
+		}
+	}
+
+	return toCancel, toPlace
+}
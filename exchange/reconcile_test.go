@@ -0,0 +1,88 @@
+package exchange
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/banky/go-hyperliquid/info"
+)
+
+func TestReconcileQuotesKeep(t *testing.T) {
+	current := []info.FrontendOpenOrder{
+		{Coin: "BTC", Side: "B", LimitPx: 49990, Sz: 0.1, Oid: 1},
+		{Coin: "BTC", Side: "A", LimitPx: 50010, Sz: 0.1, Oid: 2},
+	}
+	desired := []DesiredQuote{
+		{Coin: "BTC", IsBuy: true, Px: 49991, Sz: 0.1, PxTolerance: 2},
+		{Coin: "BTC", IsBuy: false, Px: 50010, Sz: 0.1},
+	}
+
+	toCancel, toPlace := ReconcileQuotes(current, desired)
+
+	if len(toCancel) != 0 {
+		t.Fatalf("expected nothing to cancel, got %v", toCancel)
+	}
+	if len(toPlace) != 0 {
+		t.Fatalf("expected nothing to place, got %v", toPlace)
+	}
+}
+
+func TestReconcileQuotesAdd(t *testing.T) {
+	current := []info.FrontendOpenOrder{
+		{Coin: "BTC", Side: "B", LimitPx: 49990, Sz: 0.1, Oid: 1},
+	}
+	desired := []DesiredQuote{
+		{Coin: "BTC", IsBuy: true, Px: 49990, Sz: 0.1},
+		{Coin: "BTC", IsBuy: false, Px: 50010, Sz: 0.1},
+	}
+
+	toCancel, toPlace := ReconcileQuotes(current, desired)
+
+	if len(toCancel) != 0 {
+		t.Fatalf("expected nothing to cancel, got %v", toCancel)
+	}
+	if len(toPlace) != 1 || toPlace[0] != desired[1] {
+		t.Fatalf("expected to place the ask, got %v", toPlace)
+	}
+}
+
+func TestReconcileQuotesRemove(t *testing.T) {
+	current := []info.FrontendOpenOrder{
+		{Coin: "BTC", Side: "B", LimitPx: 49990, Sz: 0.1, Oid: 1},
+		{Coin: "BTC", Side: "B", LimitPx: 49980, Sz: 0.1, Oid: 2},
+		{Coin: "BTC", Side: "A", LimitPx: 50010, Sz: 0.1, Oid: 3},
+	}
+	desired := []DesiredQuote{
+		{Coin: "BTC", IsBuy: true, Px: 49990, Sz: 0.1},
+	}
+
+	toCancel, toPlace := ReconcileQuotes(current, desired)
+
+	if !slices.Equal(toCancel, []int64{2, 3}) {
+		t.Fatalf("expected to cancel [2 3], got %v", toCancel)
+	}
+	if len(toPlace) != 0 {
+		t.Fatalf("expected nothing to place, got %v", toPlace)
+	}
+}
+
+func TestReconcileQuotesReprice(t *testing.T) {
+	current := []info.FrontendOpenOrder{
+		{Coin: "BTC", Side: "B", LimitPx: 49990, Sz: 0.1, Oid: 1},
+		{Coin: "BTC", Side: "B", LimitPx: 49990, Sz: 0.1, Oid: 2},
+	}
+	desired := []DesiredQuote{
+		{Coin: "BTC", IsBuy: true, Px: 49990, Sz: 0.1},
+		{Coin: "BTC", IsBuy: true, Px: 49950, Sz: 0.1, PxTolerance: 10},
+	}
+
+	toCancel, toPlace := ReconcileQuotes(current, desired)
+
+	// Only one resting order can satisfy each desired quote
+	if !slices.Equal(toCancel, []int64{2}) {
+		t.Fatalf("expected to cancel [2], got %v", toCancel)
+	}
+	if len(toPlace) != 1 || toPlace[0] != desired[1] {
+		t.Fatalf("expected to place the repriced bid, got %v", toPlace)
+	}
+}
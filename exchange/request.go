@@ -5,19 +5,18 @@ import (
 	"crypto/ecdsa"
 	"encoding/json"
 	"fmt"
-	"math"
 	"math/big"
 	"slices"
 	"strings"
 	"time"
 
-	"github.com/banky/go-hyperliquid/info"
 	"github.com/banky/go-hyperliquid/internal/utils"
 	"github.com/banky/go-hyperliquid/types"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/signer/core/apitypes"
 	"github.com/samber/mo"
+	"github.com/vmihailenco/msgpack/v5"
 )
 
 // ============================================================================
@@ -63,6 +62,23 @@ type LimitOrder struct {
 	Tif string `json:"tif"`
 }
 
+// Time in force values accepted for limit orders
+const (
+	TifGtc               = "Gtc"
+	TifIoc               = "Ioc"
+	TifAlo               = "Alo"
+	TifFrontendMarket    = "FrontendMarket"
+	TifLiquidationMarket = "LiquidationMarket"
+)
+
+var validTifs = []string{
+	TifGtc,
+	TifIoc,
+	TifAlo,
+	TifFrontendMarket,
+	TifLiquidationMarket,
+}
+
 type TriggerOrder struct {
 	IsMarket  bool
 	TriggerPx float64
@@ -93,6 +109,14 @@ func (t OrderType) toOrderTypeWire() (orderTypeWire, error) {
 	wire := orderTypeWire{}
 
 	if t.Limit != nil {
+		if !slices.Contains(validTifs, t.Limit.Tif) {
+			return orderTypeWire{}, fmt.Errorf(
+				"invalid tif %q: must be one of %s",
+				t.Limit.Tif,
+				strings.Join(validTifs, ", "),
+			)
+		}
+
 		wire.Limit = &LimitOrder{
 			Tif: t.Limit.Tif,
 		}
@@ -141,6 +165,11 @@ type orderRequestConfig struct {
 	triggerOrder mo.Option[TriggerOrder]
 }
 
+// OrderRequest creates a new order request. Exactly one of WithLimitOrder or
+// WithTriggerOrder must be passed.
+//
+// OrderRequest panics if no order type is set. Use NewOrderRequest when the
+// options come from user input.
 func OrderRequest(
 	coin string,
 	isBuy bool,
@@ -148,6 +177,22 @@ func OrderRequest(
 	limitPx float64,
 	opts ...orderRequestOption,
 ) orderRequest {
+	o, err := NewOrderRequest(coin, isBuy, sz, limitPx, opts...)
+	if err != nil {
+		panic(fmt.Sprintf("Failed to create OrderRequest. %v", err))
+	}
+	return o
+}
+
+// NewOrderRequest is like OrderRequest but returns an error instead of
+// panicking when no order type is set
+func NewOrderRequest(
+	coin string,
+	isBuy bool,
+	sz float64,
+	limitPx float64,
+	opts ...orderRequestOption,
+) (orderRequest, error) {
 	cfg := orderRequestConfig{}
 	for _, opt := range opts {
 		opt(&cfg)
@@ -159,7 +204,7 @@ func OrderRequest(
 	} else if t, ok := cfg.triggerOrder.Get(); ok {
 		orderType.Trigger = &t
 	} else {
-		panic("Failed to create OrderRequest. OrderType must be set")
+		return orderRequest{}, fmt.Errorf("OrderType must be set")
 	}
 
 	return orderRequest{
@@ -170,7 +215,7 @@ func OrderRequest(
 		orderType:  orderType,
 		reduceOnly: cfg.reduceOnly,
 		cloid:      cfg.cloid,
-	}
+	}, nil
 }
 
 // WithReduceOnly sets the reduce-only flag
@@ -225,7 +270,7 @@ func (o orderRequest) toAction(
 	}
 
 	// Get asset ID for this order's coin
-	assetId, ok := e.info.GetAsset(o.coin)
+	assetId, ok := e.getAsset(o.coin)
 	if !ok {
 		return nil, fmt.Errorf("unknown coin: %s", o.coin)
 	}
@@ -357,36 +402,58 @@ type modifyRequest struct {
 	Oid   mo.Option[int64]
 	Cloid mo.Option[types.Cloid]
 	Order orderRequest
+
+	// cancelReplace sends the modify as a cancel followed by a new order
+	cancelReplace bool
 }
 
 type modifyRequestOption func(*modifyRequestConfig)
 
 type modifyRequestConfig struct {
-	oid   mo.Option[int64]
-	cloid mo.Option[types.Cloid]
+	oid           mo.Option[int64]
+	cloid         mo.Option[types.Cloid]
+	cancelReplace bool
 }
 
-// ModifyRequest creates a new modify order request
+// ModifyRequest creates a new modify order request. The order to modify is
+// identified with WithModifyOrderId or WithModifyCloid.
+//
+// ModifyRequest panics if neither is passed. Use NewModifyRequest when the
+// options come from user input.
 func ModifyRequest(
 	order orderRequest,
 	opts ...modifyRequestOption,
 ) modifyRequest {
+	m, err := NewModifyRequest(order, opts...)
+	if err != nil {
+		panic(fmt.Sprintf("failed to create modify request. %v", err))
+	}
+	return m
+}
+
+// NewModifyRequest is like ModifyRequest but returns an error instead of
+// panicking when neither an order ID nor a CLOID is provided
+func NewModifyRequest(
+	order orderRequest,
+	opts ...modifyRequestOption,
+) (modifyRequest, error) {
 	cfg := modifyRequestConfig{}
 	for _, opt := range opts {
 		opt(&cfg)
 	}
 
 	if cfg.oid.IsNone() && cfg.cloid.IsNone() {
-		panic(
-			"failed to create modify request. either order ID or CLOID must be provided",
+		return modifyRequest{}, fmt.Errorf(
+			"either order ID or CLOID must be provided",
 		)
 	}
 
 	return modifyRequest{
-		Oid:   cfg.oid,
-		Cloid: cfg.cloid,
-		Order: order,
-	}
+		Oid:           cfg.oid,
+		Cloid:         cfg.cloid,
+		Order:         order,
+		cancelReplace: cfg.cancelReplace,
+	}, nil
 }
 
 func WithModifyOrderId(id int64) modifyRequestOption {
@@ -401,6 +468,16 @@ func WithModifyCloid(c types.Cloid) modifyRequestOption {
 	}
 }
 
+// WithModifyCancelReplace makes BulkModifyOrders cancel the order and place
+// the new one instead of modifying it in place, without first looking the
+// order up to check whether its type changes. The order is gone if placing
+// the new one fails.
+func WithModifyCancelReplace() modifyRequestOption {
+	return func(mrc *modifyRequestConfig) {
+		mrc.cancelReplace = true
+	}
+}
+
 // toAction converts a modifyRequest to a batchModifyAction
 func (m modifyRequest) toAction(
 	ctx context.Context,
@@ -408,7 +485,7 @@ func (m modifyRequest) toAction(
 	opts ...any,
 ) (action, error) {
 	// Get asset ID for this modify's coin
-	assetId, ok := e.info.GetAsset(m.Order.coin)
+	assetId, ok := e.getAsset(m.Order.coin)
 	if !ok {
 		return nil, fmt.Errorf("unknown coin: %s", m.Order.coin)
 	}
@@ -511,7 +588,7 @@ func (c cancelRequest) toAction(
 	opts ...any,
 ) (action, error) {
 	// Get asset ID for this cancel's coin
-	assetId, ok := e.info.GetAsset(c.Coin)
+	assetId, ok := e.getAsset(c.Coin)
 	if !ok {
 		return nil, fmt.Errorf("unknown coin: %s", c.Coin)
 	}
@@ -607,7 +684,7 @@ func (c cancelByCloidRequest) toAction(
 	opts ...any,
 ) (action, error) {
 	// Get asset ID for this cancel's coin
-	assetId, ok := e.info.GetAsset(c.Coin)
+	assetId, ok := e.getAsset(c.Coin)
 	if !ok {
 		return nil, fmt.Errorf("unknown coin: %s", c.Coin)
 	}
@@ -683,20 +760,24 @@ func cancelsByCloidToAction(cancels []cancelByCloidWire) cancelByCloidAction {
 // ============================================================================
 
 type marketOpenRequest struct {
-	coin     string
-	isBuy    bool
-	sz       float64
-	px       mo.Option[float64]
-	slippage mo.Option[float64]
-	cloid    mo.Option[types.Cloid]
+	coin       string
+	isBuy      bool
+	sz         float64
+	px         mo.Option[float64]
+	slippage   mo.Option[float64]
+	cloid      mo.Option[types.Cloid]
+	tif        mo.Option[string]
+	reduceOnly bool
 }
 
 type marketOpenRequestOption func(*marketOpenRequestConfig)
 
 type marketOpenRequestConfig struct {
-	px       mo.Option[float64]
-	slippage mo.Option[float64]
-	cloid    mo.Option[types.Cloid]
+	px         mo.Option[float64]
+	slippage   mo.Option[float64]
+	cloid      mo.Option[types.Cloid]
+	tif        mo.Option[string]
+	reduceOnly bool
 }
 
 // MarketOpenRequest creates a new market order request
@@ -712,12 +793,14 @@ func MarketOpenRequest(
 	}
 
 	return marketOpenRequest{
-		coin:     coin,
-		isBuy:    isBuy,
-		sz:       sz,
-		px:       cfg.px,
-		slippage: cfg.slippage,
-		cloid:    cfg.cloid,
+		coin:       coin,
+		isBuy:      isBuy,
+		sz:         sz,
+		px:         cfg.px,
+		slippage:   cfg.slippage,
+		cloid:      cfg.cloid,
+		tif:        cfg.tif,
+		reduceOnly: cfg.reduceOnly,
 	}
 }
 
@@ -742,6 +825,59 @@ func WithMarketCloid(c types.Cloid) marketOpenRequestOption {
 	}
 }
 
+// WithNativeMarket sends the market order with the FrontendMarket tif so that
+// the matching engine handles slippage. No mid price is fetched and the
+// slippage option is ignored. The limit price must be set with
+// WithMarketPrice, otherwise the order fails before it is signed.
+func WithNativeMarket() marketOpenRequestOption {
+	return WithMarketTif(TifFrontendMarket)
+}
+
+// WithMarketReduceOnly marks the market order as reduce only, so it can only
+// shrink an existing position
+func WithMarketReduceOnly(reduceOnly bool) marketOpenRequestOption {
+	return func(cfg *marketOpenRequestConfig) {
+		cfg.reduceOnly = reduceOnly
+	}
+}
+
+// WithMarketTif selects how a market order is priced. TifIoc, the default,
+// sends an aggressive limit price derived from the mid and the slippage
+// setting. TifFrontendMarket is the same as WithNativeMarket. Any other tif
+// makes the order fail before it is signed.
+func WithMarketTif(tif string) marketOpenRequestOption {
+	return func(cfg *marketOpenRequestConfig) {
+		cfg.tif = mo.Some(tif)
+	}
+}
+
+// nativeMarket reports whether the market order uses the FrontendMarket tif.
+// It returns an error for tifs that are not valid for a market order.
+func (m marketOpenRequest) nativeMarket() (bool, error) {
+	tif := m.tif.OrElse(TifIoc)
+	if !slices.Contains(validTifs, tif) {
+		return false, fmt.Errorf(
+			"invalid tif %q: must be one of %s",
+			tif,
+			strings.Join(validTifs, ", "),
+		)
+	}
+
+	switch tif {
+	case TifIoc:
+		return false, nil
+	case TifFrontendMarket:
+		return true, nil
+	default:
+		return false, fmt.Errorf(
+			"invalid market order tif %q: must be %s or %s",
+			tif,
+			TifIoc,
+			TifFrontendMarket,
+		)
+	}
+}
+
 // toAction converts a marketOpenRequest to an orderAction
 // Note: This optionally accepts builder in opts
 func (m marketOpenRequest) toAction(
@@ -758,26 +894,31 @@ func (m marketOpenRequest) toAction(
 		}
 	}
 
-	// Get slippage price
-	px, err := e.getSlippagePrice(
+	nativeMarket, err := m.nativeMarket()
+	if err != nil {
+		return nil, err
+	}
+
+	px, tif, err := e.marketOrderPrice(
 		ctx,
 		m.coin,
 		m.isBuy,
-		m.slippage.OrElse(DEFAULT_SLIPPAGE),
+		nativeMarket,
+		m.slippage,
 		m.px,
 	)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get slippage price: %w", err)
+		return nil, err
 	}
 
-	// Create an order request with IoC tif and reduceOnly=false
+	// Create an order request with a market tif
 	orderReq := OrderRequest(
 		m.coin,
 		m.isBuy,
 		m.sz,
 		px,
-		WithLimitOrder(LimitOrder{Tif: "Ioc"}),
-		WithReduceOnly(false),
+		WithLimitOrder(LimitOrder{Tif: tif}),
+		WithReduceOnly(m.reduceOnly),
 		withCloid(m.cloid),
 	)
 
@@ -790,20 +931,22 @@ func (m marketOpenRequest) toAction(
 // ============================================================================
 
 type marketCloseRequest struct {
-	coin     string
-	sz       mo.Option[float64]
-	px       mo.Option[float64]
-	slippage mo.Option[float64]
-	cloid    mo.Option[types.Cloid]
+	coin         string
+	sz           mo.Option[float64]
+	px           mo.Option[float64]
+	slippage     mo.Option[float64]
+	cloid        mo.Option[types.Cloid]
+	nativeMarket bool
 }
 
 type marketCloseRequestOption func(*marketCloseRequestConfig)
 
 type marketCloseRequestConfig struct {
-	sz       mo.Option[float64]
-	px       mo.Option[float64]
-	slippage mo.Option[float64]
-	cloid    mo.Option[types.Cloid]
+	sz           mo.Option[float64]
+	px           mo.Option[float64]
+	slippage     mo.Option[float64]
+	cloid        mo.Option[types.Cloid]
+	nativeMarket bool
 }
 
 // MarketCloseRequest creates a new market close request
@@ -817,11 +960,12 @@ func MarketCloseRequest(
 	}
 
 	return marketCloseRequest{
-		coin:     coin,
-		sz:       cfg.sz,
-		px:       cfg.px,
-		slippage: cfg.slippage,
-		cloid:    cfg.cloid,
+		coin:         coin,
+		sz:           cfg.sz,
+		px:           cfg.px,
+		slippage:     cfg.slippage,
+		cloid:        cfg.cloid,
+		nativeMarket: cfg.nativeMarket,
 	}
 }
 
@@ -854,6 +998,16 @@ func WithMarketCloseCloid(c types.Cloid) marketCloseRequestOption {
 	}
 }
 
+// WithMarketCloseNativeMarket sends the market close with the FrontendMarket
+// tif so that the matching engine handles slippage. No mid price is fetched
+// and the slippage option is ignored. The limit price must be set with
+// WithMarketClosePrice, otherwise the order fails before it is signed.
+func WithMarketCloseNativeMarket() marketCloseRequestOption {
+	return func(cfg *marketCloseRequestConfig) {
+		cfg.nativeMarket = true
+	}
+}
+
 // toAction converts a marketCloseRequest to an orderAction
 // Note: This optionally accepts builder in opts
 func (m marketCloseRequest) toAction(
@@ -870,70 +1024,32 @@ func (m marketCloseRequest) toAction(
 		}
 	}
 
-	// Get user state to find the position
-	address := crypto.PubkeyToAddress(e.privateKey.PublicKey)
-	if a, ok := e.accountAddress.Get(); ok {
-		address = a
-	}
-	if v, ok := e.vaultAddress.Get(); ok {
-		address = v
-	}
-
-	dex := utils.GetDex(m.coin)
-	userState, err := e.info.UserState(ctx, address, dex)
+	closeSz, isBuy, err := e.closeSize(ctx, e.userAddress(), m.coin, m.sz)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get user state: %w", err)
-	}
-
-	// Find the position for this coin
-	var position *info.Position
-	var positionSize float64
-	if userState.AssetPositions != nil {
-		for _, assetPos := range userState.AssetPositions {
-			if assetPos.Position.Coin == m.coin {
-				position = &assetPos.Position
-				positionSize = float64(assetPos.Position.Szi)
-				break
-			}
-		}
-	}
-
-	if position == nil {
-		return nil, fmt.Errorf("no position found for coin: %s", m.coin)
-	}
-
-	// Determine size to close
-	var closeSz float64
-	if sz, ok := m.sz.Get(); ok {
-		closeSz = sz
-	} else {
-		// Close entire position
-		closeSz = math.Abs(positionSize)
+		return nil, err
 	}
 
-	// Determine buy/sell direction (opposite of current position)
-	isBuy := positionSize < 0
-
-	// Get slippage price
-	px, err := e.getSlippagePrice(
+	px, tif, err := e.marketOrderPrice(
 		ctx,
 		m.coin,
 		isBuy,
-		m.slippage.OrElse(DEFAULT_SLIPPAGE),
+		m.nativeMarket,
+		m.slippage,
 		m.px,
 	)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get slippage price: %w", err)
+		return nil, err
 	}
 
-	// Create an order request with IoC tif and reduceOnly=false
+	// Create an order request with a market tif and reduceOnly=true so the close can never
+	// flip the position
 	orderReq := OrderRequest(
 		m.coin,
 		isBuy,
 		closeSz,
 		px,
-		WithLimitOrder(LimitOrder{Tif: "Ioc"}),
-		WithReduceOnly(false),
+		WithLimitOrder(LimitOrder{Tif: tif}),
+		WithReduceOnly(true),
 		withCloid(m.cloid),
 	)
 
@@ -941,6 +1057,185 @@ func (m marketCloseRequest) toAction(
 	return orderReq.toAction(ctx, e, builder)
 }
 
+// ============================================================================
+// TWAP Order Request
+// ============================================================================
+
+type twapOrderRequest struct {
+	coin       string
+	isBuy      bool
+	sz         float64
+	reduceOnly bool
+	minutes    int64
+	randomize  bool
+}
+
+// TwapOrderRequest creates a new request to run a TWAP order over the given
+// number of minutes
+func TwapOrderRequest(
+	coin string,
+	isBuy bool,
+	sz float64,
+	reduceOnly bool,
+	minutes int64,
+	randomize bool,
+) twapOrderRequest {
+	return twapOrderRequest{
+		coin:       coin,
+		isBuy:      isBuy,
+		sz:         sz,
+		reduceOnly: reduceOnly,
+		minutes:    minutes,
+		randomize:  randomize,
+	}
+}
+
+// toAction converts a twapOrderRequest to a twapOrderAction
+func (t twapOrderRequest) toAction(
+	ctx context.Context,
+	e *Exchange,
+	opts ...any,
+) (action, error) {
+	assetId, ok := e.getAsset(t.coin)
+	if !ok {
+		return nil, fmt.Errorf("unknown coin: %s", t.coin)
+	}
+
+	sizeStr, err := utils.FloatToWire(t.sz)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert size: %w", err)
+	}
+
+	return twapOrderAction{
+		Type: "twapOrder",
+		Twap: twapWire{
+			A: assetId,
+			B: t.isBuy,
+			S: sizeStr,
+			R: t.reduceOnly,
+			M: t.minutes,
+			T: t.randomize,
+		},
+	}, nil
+}
+
+type twapWire struct {
+	A int64  `json:"a"`
+	B bool   `json:"b"`
+	S string `json:"s"`
+	R bool   `json:"r"`
+	M int64  `json:"m"`
+	T bool   `json:"t"`
+}
+
+type twapOrderAction struct {
+	Type string   `json:"type"`
+	Twap twapWire `json:"twap"`
+}
+
+func (t twapOrderAction) getType() string {
+	return t.Type
+}
+
+func (t twapOrderAction) sign(
+	privateKey *ecdsa.PrivateKey,
+	nonce int64,
+	e *Exchange,
+) (signature, error) {
+	return signL1Action(
+		t,
+		uint64(nonce),
+		privateKey,
+		e.vaultAddress,
+		e.expiresAfter,
+		e.rest.IsMainnet(),
+	)
+}
+
+func (t twapOrderAction) getMap() map[string]any {
+	return nil // L1 action
+}
+
+func (t twapOrderAction) getPayloadTypes() []apitypes.Type {
+	return nil // L1 action
+}
+
+func (t twapOrderAction) getPrimaryType() string {
+	return "" // L1 action
+}
+
+// ============================================================================
+// TWAP Cancel Request
+// ============================================================================
+
+type twapCancelRequest struct {
+	coin   string
+	twapId int64
+}
+
+// TwapCancelRequest creates a new request to cancel a running TWAP order
+func TwapCancelRequest(coin string, twapId int64) twapCancelRequest {
+	return twapCancelRequest{
+		coin:   coin,
+		twapId: twapId,
+	}
+}
+
+// toAction converts a twapCancelRequest to a twapCancelAction
+func (t twapCancelRequest) toAction(
+	ctx context.Context,
+	e *Exchange,
+	opts ...any,
+) (action, error) {
+	assetId, ok := e.getAsset(t.coin)
+	if !ok {
+		return nil, fmt.Errorf("unknown coin: %s", t.coin)
+	}
+
+	return twapCancelAction{
+		Type: "twapCancel",
+		A:    assetId,
+		T:    t.twapId,
+	}, nil
+}
+
+type twapCancelAction struct {
+	Type string `json:"type"`
+	A    int64  `json:"a"`
+	T    int64  `json:"t"`
+}
+
+func (t twapCancelAction) getType() string {
+	return t.Type
+}
+
+func (t twapCancelAction) sign(
+	privateKey *ecdsa.PrivateKey,
+	nonce int64,
+	e *Exchange,
+) (signature, error) {
+	return signL1Action(
+		t,
+		uint64(nonce),
+		privateKey,
+		e.vaultAddress,
+		e.expiresAfter,
+		e.rest.IsMainnet(),
+	)
+}
+
+func (t twapCancelAction) getMap() map[string]any {
+	return nil // L1 action
+}
+
+func (t twapCancelAction) getPayloadTypes() []apitypes.Type {
+	return nil // L1 action
+}
+
+func (t twapCancelAction) getPrimaryType() string {
+	return "" // L1 action
+}
+
 // ============================================================================
 // Update Leverage Request
 // ============================================================================
@@ -989,7 +1284,7 @@ func (u updateLeverageRequest) toAction(
 	opts ...any,
 ) (action, error) {
 	// Get asset ID for the leverage update
-	assetId, ok := e.info.GetAsset(u.coin)
+	assetId, ok := e.getAsset(u.coin)
 	if !ok {
 		return nil, fmt.Errorf("unknown coin: %s", u.coin)
 	}
@@ -1353,78 +1648,194 @@ func createSubAccountToAction(n string) createSubAccountAction {
 }
 
 // ============================================================================
-// USD Class Transfer Request
+// Claim Rewards Request
 // ============================================================================
 
-type usdClassTransferRequest struct {
-	amount float64
-	toPerp bool
-}
+type claimRewardsRequest struct{}
 
-// UsdClassTransferRequest creates a new USD class transfer request
-func UsdClassTransferRequest(
-	amount float64,
-	toPerp bool,
-) usdClassTransferRequest {
-	return usdClassTransferRequest{
-		amount: amount,
-		toPerp: toPerp,
-	}
+// ClaimRewardsRequest creates a new claim rewards request
+func ClaimRewardsRequest() claimRewardsRequest {
+	return claimRewardsRequest{}
 }
 
-// toAction converts a usdClassTransferRequest to a usdClassTransferAction
-// Note: This requires timestamp (int64) in opts
-func (u usdClassTransferRequest) toAction(
+// toAction converts a claimRewardsRequest to a claimRewardsAction
+func (c claimRewardsRequest) toAction(
 	ctx context.Context,
 	e *Exchange,
 	opts ...any,
 ) (action, error) {
-	// Extract timestamp from opts
-	var timestamp int64
-	for _, opt := range opts {
-		if ts, ok := opt.(int64); ok {
-			timestamp = ts
-			break
-		}
-	}
-
-	if timestamp == 0 {
-		return nil, fmt.Errorf(
-			"timestamp is required in opts for usdClassTransferRequest",
-		)
-	}
-
-	// Convert amount to wire format
-	strAmount, err := utils.FloatToWire(u.amount)
-	if err != nil {
-		return nil, fmt.Errorf(
-			"failed to convert amount to wire format: %w",
-			err,
-		)
-	}
-
-	// Add vault address if present
-	if v, ok := e.vaultAddress.Get(); ok {
-		strAmount += fmt.Sprintf(" subaccount:%s", v.String())
-	}
+	return claimRewardsToAction(), nil
+}
 
-	return usdClassTransferAction{
-		Type:             "usdClassTransfer",
-		Amount:           strAmount,
-		ToPerp:           u.toPerp,
-		Nonce:            timestamp,
-		SignatureChainId: getSignatureChainId(),
-		HyperliquidChain: e.rest.NetworkName(),
-	}, nil
+type claimRewardsAction struct {
+	Type string `json:"type"`
 }
 
-type usdClassTransferAction struct {
-	Type             string `json:"type"`
-	Amount           string `json:"amount"`
-	ToPerp           bool   `json:"toPerp"`
-	Nonce            int64  `json:"nonce"`
-	SignatureChainId string `json:"signatureChainId"`
-	HyperliquidChain string `json:"hyperliquidChain"`
+func (c claimRewardsAction) getType() string {
+	return c.Type
+}
+
+func (c claimRewardsAction) sign(
+	privateKey *ecdsa.PrivateKey,
+	nonce int64,
+	e *Exchange,
+) (signature, error) {
+	return signL1Action(
+		c,
+		uint64(nonce),
+		privateKey,
+		e.vaultAddress,
+		e.expiresAfter,
+		e.rest.IsMainnet(),
+	)
+}
+
+func (c claimRewardsAction) getMap() map[string]any {
+	return nil // L1 action
+}
+
+func (c claimRewardsAction) getPayloadTypes() []apitypes.Type {
+	return nil // L1 action
+}
+
+func (c claimRewardsAction) getPrimaryType() string {
+	return "" // L1 action
+}
+
+func claimRewardsToAction() claimRewardsAction {
+	return claimRewardsAction{
+		Type: "claimRewards",
+	}
+}
+
+// ============================================================================
+// Noop Request
+// ============================================================================
+
+type noopRequest struct{}
+
+// NoopRequest creates a new no-op request
+func NoopRequest() noopRequest {
+	return noopRequest{}
+}
+
+// toAction converts a noopRequest to a noopAction
+func (n noopRequest) toAction(
+	ctx context.Context,
+	e *Exchange,
+	opts ...any,
+) (action, error) {
+	return noopAction{Type: "noop"}, nil
+}
+
+type noopAction struct {
+	Type string `json:"type"`
+}
+
+func (n noopAction) getType() string {
+	return n.Type
+}
+
+func (n noopAction) sign(
+	privateKey *ecdsa.PrivateKey,
+	nonce int64,
+	e *Exchange,
+) (signature, error) {
+	return signL1Action(
+		n,
+		uint64(nonce),
+		privateKey,
+		e.vaultAddress,
+		e.expiresAfter,
+		e.rest.IsMainnet(),
+	)
+}
+
+func (n noopAction) getMap() map[string]any {
+	return nil // L1 action
+}
+
+func (n noopAction) getPayloadTypes() []apitypes.Type {
+	return nil // L1 action
+}
+
+func (n noopAction) getPrimaryType() string {
+	return "" // L1 action
+}
+
+// ============================================================================
+// USD Class Transfer Request
+// ============================================================================
+
+type usdClassTransferRequest struct {
+	amount float64
+	toPerp bool
+}
+
+// UsdClassTransferRequest creates a new USD class transfer request
+func UsdClassTransferRequest(
+	amount float64,
+	toPerp bool,
+) usdClassTransferRequest {
+	return usdClassTransferRequest{
+		amount: amount,
+		toPerp: toPerp,
+	}
+}
+
+// toAction converts a usdClassTransferRequest to a usdClassTransferAction
+// Note: This requires timestamp (int64) in opts
+func (u usdClassTransferRequest) toAction(
+	ctx context.Context,
+	e *Exchange,
+	opts ...any,
+) (action, error) {
+	// Extract timestamp from opts
+	var timestamp int64
+	for _, opt := range opts {
+		if ts, ok := opt.(int64); ok {
+			timestamp = ts
+			break
+		}
+	}
+
+	if timestamp == 0 {
+		return nil, fmt.Errorf(
+			"timestamp is required in opts for usdClassTransferRequest",
+		)
+	}
+
+	// Convert amount to wire format
+	strAmount, err := utils.FloatToWire(u.amount)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to convert amount to wire format: %w",
+			err,
+		)
+	}
+
+	// Add vault address if present
+	if v, ok := e.vaultAddress.Get(); ok {
+		strAmount += fmt.Sprintf(" subaccount:%s", v.String())
+	}
+
+	return usdClassTransferAction{
+		Type:             "usdClassTransfer",
+		Amount:           strAmount,
+		ToPerp:           u.toPerp,
+		Nonce:            timestamp,
+		SignatureChainId: e.getSignatureChainId(),
+		HyperliquidChain: e.rest.NetworkName(),
+	}, nil
+}
+
+type usdClassTransferAction struct {
+	Type             string `json:"type"`
+	Amount           string `json:"amount"`
+	ToPerp           bool   `json:"toPerp"`
+	Nonce            int64  `json:"nonce"`
+	SignatureChainId string `json:"signatureChainId"`
+	HyperliquidChain string `json:"hyperliquidChain"`
 }
 
 func (u usdClassTransferAction) getType() string {
@@ -1517,7 +1928,7 @@ func (u usdTransferRequest) toAction(
 		Amount:           strAmount,
 		Destination:      strings.ToLower(u.destination.Hex()),
 		Time:             timestamp,
-		SignatureChainId: getSignatureChainId(),
+		SignatureChainId: e.getSignatureChainId(),
 		HyperliquidChain: e.rest.NetworkName(),
 	}, nil
 }
@@ -1621,7 +2032,7 @@ func (s sendAssetRequest) toAction(
 		Amount:           amountStr,
 		FromSubAccount:   fromSubAccount,
 		Nonce:            0, // Will be set by Exchange
-		SignatureChainId: getSignatureChainId(),
+		SignatureChainId: e.getSignatureChainId(),
 		HyperliquidChain: e.rest.NetworkName(),
 	}, nil
 }
@@ -1981,7 +2392,7 @@ func (s spotTransferRequest) toAction(
 		Token:            s.token,
 		Amount:           strAmount,
 		Time:             timestamp,
-		SignatureChainId: getSignatureChainId(),
+		SignatureChainId: e.getSignatureChainId(),
 		HyperliquidChain: e.rest.NetworkName(),
 	}, nil
 }
@@ -2038,14 +2449,15 @@ func (s spotTransferAction) getPrimaryType() string {
 
 type tokenDelegateRequest struct {
 	validator    common.Address
-	wei          int64
+	wei          *big.Int
 	isUndelegate bool
 }
 
-// TokenDelegateRequest creates a new token delegate request
+// TokenDelegateRequest creates a new token delegate request. The protocol
+// signs wei as a uint64, so it must be non-negative and fit in 64 bits.
 func TokenDelegateRequest(
 	validator common.Address,
-	wei int64,
+	wei *big.Int,
 	isUndelegate bool,
 ) tokenDelegateRequest {
 	return tokenDelegateRequest{
@@ -2077,13 +2489,20 @@ func (t tokenDelegateRequest) toAction(
 		)
 	}
 
+	if t.wei == nil || t.wei.Sign() < 0 || !t.wei.IsUint64() {
+		return nil, fmt.Errorf(
+			"wei must be a non-negative value that fits in uint64, got %v",
+			t.wei,
+		)
+	}
+
 	return tokenDelegateAction{
 		Type:             "tokenDelegate",
 		Validator:        strings.ToLower(t.validator.Hex()),
-		Wei:              t.wei,
+		Wei:              t.wei.Uint64(),
 		IsUndelegate:     t.isUndelegate,
 		Nonce:            timestamp,
-		SignatureChainId: getSignatureChainId(),
+		SignatureChainId: e.getSignatureChainId(),
 		HyperliquidChain: e.rest.NetworkName(),
 	}, nil
 }
@@ -2091,7 +2510,7 @@ func (t tokenDelegateRequest) toAction(
 type tokenDelegateAction struct {
 	Type             string `json:"type"`
 	Validator        string `json:"validator"`
-	Wei              int64  `json:"wei"`
+	Wei              uint64 `json:"wei"`
 	IsUndelegate     bool   `json:"isUndelegate"`
 	Nonce            int64  `json:"nonce"`
 	SignatureChainId string `json:"signatureChainId"`
@@ -2114,7 +2533,7 @@ func (t tokenDelegateAction) getMap() map[string]any {
 	return map[string]any{
 		"hyperliquidChain": t.HyperliquidChain,
 		"validator":        t.Validator,
-		"wei":              big.NewInt(t.Wei),
+		"wei":              new(big.Int).SetUint64(t.Wei),
 		"isUndelegate":     t.IsUndelegate,
 		"nonce":            big.NewInt(t.Nonce),
 	}
@@ -2188,7 +2607,7 @@ func (w withdrawFromBridgeRequest) toAction(
 		Destination:      strings.ToLower(w.destination.Hex()),
 		Amount:           strAmount,
 		Time:             timestamp,
-		SignatureChainId: getSignatureChainId(),
+		SignatureChainId: e.getSignatureChainId(),
 		HyperliquidChain: e.rest.NetworkName(),
 	}, nil
 }
@@ -2314,7 +2733,7 @@ func (a approveAgentRequest) toAction(
 		AgentAddress:     strings.ToLower(agentAddress.Hex()),
 		AgentName:        agentName,
 		Nonce:            timestamp,
-		SignatureChainId: getSignatureChainId(),
+		SignatureChainId: e.getSignatureChainId(),
 		HyperliquidChain: e.rest.NetworkName(),
 	}, nil
 }
@@ -2408,7 +2827,7 @@ func (a approveBuilderFeeRequest) toAction(
 		MaxFeeRate:       a.maxFeeRate,
 		Builder:          strings.ToLower(a.builder.Hex()),
 		Nonce:            timestamp,
-		SignatureChainId: getSignatureChainId(),
+		SignatureChainId: e.getSignatureChainId(),
 		HyperliquidChain: e.rest.NetworkName(),
 	}, nil
 }
@@ -2467,6 +2886,8 @@ func (a approveBuilderFeeAction) getPrimaryType() string {
 type convertToMultiSigUserRequest struct {
 	authorizedUsers []common.Address
 	threshold       int64
+	// toNormalUser converts a multi-sig account back to a normal user
+	toNormalUser bool
 }
 
 func ConvertToMultiSigUserRequest(
@@ -2479,6 +2900,14 @@ func ConvertToMultiSigUserRequest(
 	}
 }
 
+// ConvertFromMultiSigUserRequest creates a request that converts a multi-sig
+// account back to a normal user. It is the convertToMultiSigUser action with
+// signers set to "null". Only the account's authorized users can sign it, so
+// it must be submitted with MultiSig.
+func ConvertFromMultiSigUserRequest() convertToMultiSigUserRequest {
+	return convertToMultiSigUserRequest{toNormalUser: true}
+}
+
 // toAction converts a convertToMultiSigUserRequest to a
 // convertToMultiSigUserAction
 // Note: This requires timestamp (int64) in opts
@@ -2502,6 +2931,16 @@ func (c convertToMultiSigUserRequest) toAction(
 		)
 	}
 
+	if c.toNormalUser {
+		return convertToMultiSigUserAction{
+			Type:             "convertToMultiSigUser",
+			Signers:          "null",
+			Nonce:            timestamp,
+			SignatureChainId: e.getSignatureChainId(),
+			HyperliquidChain: e.rest.NetworkName(),
+		}, nil
+	}
+
 	// Sort authorized users
 	sortedUsers := make([]common.Address, len(c.authorizedUsers))
 	copy(sortedUsers, c.authorizedUsers)
@@ -2532,7 +2971,7 @@ func (c convertToMultiSigUserRequest) toAction(
 		Type:             "convertToMultiSigUser",
 		Signers:          string(signersJSON),
 		Nonce:            timestamp,
-		SignatureChainId: getSignatureChainId(),
+		SignatureChainId: e.getSignatureChainId(),
 		HyperliquidChain: e.rest.NetworkName(),
 	}, nil
 }
@@ -2581,6 +3020,1097 @@ func (a convertToMultiSigUserAction) getPrimaryType() string {
 	return "HyperliquidTransaction:ConvertToMultiSigUser"
 }
 
+// ============================================================================
+// Spot Deploy Request
+// ============================================================================
+
+// UserWeiPair assigns a genesis balance in wei to a user
+type UserWeiPair struct {
+	User common.Address
+	Wei  *big.Int
+}
+
+// TokenWeiPair assigns a genesis balance in wei to the holders of an existing
+// token
+type TokenWeiPair struct {
+	Token int64
+	Wei   *big.Int
+}
+
+type spotDeployRegisterTokenRequest struct {
+	tokenName   string
+	szDecimals  int64
+	weiDecimals int64
+	maxGas      int64
+	fullName    string
+}
+
+// SpotDeployRegisterTokenRequest creates a new request to register a spot
+// token
+func SpotDeployRegisterTokenRequest(
+	tokenName string,
+	szDecimals int64,
+	weiDecimals int64,
+	maxGas int64,
+	fullName string,
+) spotDeployRegisterTokenRequest {
+	return spotDeployRegisterTokenRequest{
+		tokenName:   tokenName,
+		szDecimals:  szDecimals,
+		weiDecimals: weiDecimals,
+		maxGas:      maxGas,
+		fullName:    fullName,
+	}
+}
+
+// toAction converts a spotDeployRegisterTokenRequest to a spotDeployAction
+func (s spotDeployRegisterTokenRequest) toAction(
+	ctx context.Context,
+	e *Exchange,
+	opts ...any,
+) (action, error) {
+	return spotDeployAction{
+		Type: "spotDeploy",
+		RegisterToken2: &spotDeployRegisterToken2Wire{
+			Spec: spotDeployTokenSpecWire{
+				Name:        s.tokenName,
+				SzDecimals:  s.szDecimals,
+				WeiDecimals: s.weiDecimals,
+			},
+			MaxGas:   s.maxGas,
+			FullName: s.fullName,
+		},
+	}, nil
+}
+
+type spotDeployUserGenesisRequest struct {
+	token               int64
+	userAndWei          []UserWeiPair
+	existingTokenAndWei []TokenWeiPair
+}
+
+// SpotDeployUserGenesisRequest creates a new request to assign genesis
+// balances for a token
+func SpotDeployUserGenesisRequest(
+	token int64,
+	userAndWei []UserWeiPair,
+	existingTokenAndWei []TokenWeiPair,
+) spotDeployUserGenesisRequest {
+	return spotDeployUserGenesisRequest{
+		token:               token,
+		userAndWei:          userAndWei,
+		existingTokenAndWei: existingTokenAndWei,
+	}
+}
+
+// toAction converts a spotDeployUserGenesisRequest to a spotDeployAction
+func (s spotDeployUserGenesisRequest) toAction(
+	ctx context.Context,
+	e *Exchange,
+	opts ...any,
+) (action, error) {
+	userAndWei := make([][]string, len(s.userAndWei))
+	for i, pair := range s.userAndWei {
+		if pair.Wei == nil {
+			return nil, fmt.Errorf("wei is required for user %d", i)
+		}
+		userAndWei[i] = []string{
+			strings.ToLower(pair.User.Hex()),
+			pair.Wei.String(),
+		}
+	}
+
+	existingTokenAndWei := make([][]any, len(s.existingTokenAndWei))
+	for i, pair := range s.existingTokenAndWei {
+		if pair.Wei == nil {
+			return nil, fmt.Errorf("wei is required for existing token %d", i)
+		}
+		existingTokenAndWei[i] = []any{pair.Token, pair.Wei.String()}
+	}
+
+	return spotDeployAction{
+		Type: "spotDeploy",
+		UserGenesis: &spotDeployUserGenesisWire{
+			Token:               s.token,
+			UserAndWei:          userAndWei,
+			ExistingTokenAndWei: existingTokenAndWei,
+		},
+	}, nil
+}
+
+type spotDeployGenesisRequest struct {
+	token            int64
+	maxSupply        string
+	noHyperliquidity bool
+}
+
+// SpotDeployGenesisRequest creates a new request to set the max supply of a
+// token and finish its genesis
+func SpotDeployGenesisRequest(
+	token int64,
+	maxSupply string,
+	noHyperliquidity bool,
+) spotDeployGenesisRequest {
+	return spotDeployGenesisRequest{
+		token:            token,
+		maxSupply:        maxSupply,
+		noHyperliquidity: noHyperliquidity,
+	}
+}
+
+// toAction converts a spotDeployGenesisRequest to a spotDeployAction
+func (s spotDeployGenesisRequest) toAction(
+	ctx context.Context,
+	e *Exchange,
+	opts ...any,
+) (action, error) {
+	return spotDeployAction{
+		Type: "spotDeploy",
+		Genesis: &spotDeployGenesisWire{
+			Token:            s.token,
+			MaxSupply:        s.maxSupply,
+			NoHyperliquidity: s.noHyperliquidity,
+		},
+	}, nil
+}
+
+type spotDeployRegisterSpotRequest struct {
+	baseToken  int64
+	quoteToken int64
+}
+
+// SpotDeployRegisterSpotRequest creates a new request to register a spot
+// trading pair
+func SpotDeployRegisterSpotRequest(
+	baseToken int64,
+	quoteToken int64,
+) spotDeployRegisterSpotRequest {
+	return spotDeployRegisterSpotRequest{
+		baseToken:  baseToken,
+		quoteToken: quoteToken,
+	}
+}
+
+// toAction converts a spotDeployRegisterSpotRequest to a spotDeployAction
+func (s spotDeployRegisterSpotRequest) toAction(
+	ctx context.Context,
+	e *Exchange,
+	opts ...any,
+) (action, error) {
+	return spotDeployAction{
+		Type: "spotDeploy",
+		RegisterSpot: &spotDeployRegisterSpotWire{
+			Tokens: [2]int64{s.baseToken, s.quoteToken},
+		},
+	}, nil
+}
+
+type spotDeployRegisterHyperliquidityRequest struct {
+	spot          int64
+	startPx       float64
+	orderSz       float64
+	nOrders       int64
+	nSeededLevels mo.Option[int64]
+}
+
+// SpotDeployRegisterHyperliquidityRequest creates a new request to register
+// the hyperliquidity market maker for a spot pair. nSeededLevels is optional.
+func SpotDeployRegisterHyperliquidityRequest(
+	spot int64,
+	startPx float64,
+	orderSz float64,
+	nOrders int64,
+	nSeededLevels *int64,
+) spotDeployRegisterHyperliquidityRequest {
+	var levels mo.Option[int64]
+	if nSeededLevels != nil {
+		levels = mo.Some(*nSeededLevels)
+	}
+
+	return spotDeployRegisterHyperliquidityRequest{
+		spot:          spot,
+		startPx:       startPx,
+		orderSz:       orderSz,
+		nOrders:       nOrders,
+		nSeededLevels: levels,
+	}
+}
+
+// toAction converts a spotDeployRegisterHyperliquidityRequest to a
+// spotDeployAction
+func (s spotDeployRegisterHyperliquidityRequest) toAction(
+	ctx context.Context,
+	e *Exchange,
+	opts ...any,
+) (action, error) {
+	startPx, err := utils.FloatToWire(s.startPx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert start price: %w", err)
+	}
+
+	orderSz, err := utils.FloatToWire(s.orderSz)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert order size: %w", err)
+	}
+
+	var nSeededLevels *int64
+	if n, ok := s.nSeededLevels.Get(); ok {
+		nSeededLevels = &n
+	}
+
+	return spotDeployAction{
+		Type: "spotDeploy",
+		RegisterHyperliquidity: &spotDeployRegisterHyperliquidityWire{
+			Spot:          s.spot,
+			StartPx:       startPx,
+			OrderSz:       orderSz,
+			NOrders:       s.nOrders,
+			NSeededLevels: nSeededLevels,
+		},
+	}, nil
+}
+
+type spotDeployTokenSpecWire struct {
+	Name        string `json:"name"`
+	SzDecimals  int64  `json:"szDecimals"`
+	WeiDecimals int64  `json:"weiDecimals"`
+}
+
+type spotDeployRegisterToken2Wire struct {
+	Spec     spotDeployTokenSpecWire `json:"spec"`
+	MaxGas   int64                   `json:"maxGas"`
+	FullName string                  `json:"fullName"`
+}
+
+type spotDeployUserGenesisWire struct {
+	Token               int64      `json:"token"`
+	UserAndWei          [][]string `json:"userAndWei"`
+	ExistingTokenAndWei [][]any    `json:"existingTokenAndWei"`
+}
+
+type spotDeployGenesisWire struct {
+	Token            int64  `json:"token"`
+	MaxSupply        string `json:"maxSupply"`
+	NoHyperliquidity bool   `json:"noHyperliquidity,omitempty"`
+}
+
+type spotDeployRegisterSpotWire struct {
+	Tokens [2]int64 `json:"tokens"`
+}
+
+type spotDeployRegisterHyperliquidityWire struct {
+	Spot          int64  `json:"spot"`
+	StartPx       string `json:"startPx"`
+	OrderSz       string `json:"orderSz"`
+	NOrders       int64  `json:"nOrders"`
+	NSeededLevels *int64 `json:"nSeededLevels,omitempty"`
+}
+
+// spotDeployAction carries exactly one spot deploy variant
+type spotDeployAction struct {
+	Type                   string                                `json:"type"`
+	RegisterToken2         *spotDeployRegisterToken2Wire         `json:"registerToken2,omitempty"`
+	UserGenesis            *spotDeployUserGenesisWire            `json:"userGenesis,omitempty"`
+	Genesis                *spotDeployGenesisWire                `json:"genesis,omitempty"`
+	RegisterSpot           *spotDeployRegisterSpotWire           `json:"registerSpot,omitempty"`
+	RegisterHyperliquidity *spotDeployRegisterHyperliquidityWire `json:"registerHyperliquidity,omitempty"`
+}
+
+func (s spotDeployAction) getType() string {
+	return s.Type
+}
+
+func (s spotDeployAction) sign(
+	privateKey *ecdsa.PrivateKey,
+	nonce int64,
+	e *Exchange,
+) (signature, error) {
+	return signL1Action(
+		s,
+		uint64(nonce),
+		privateKey,
+		e.vaultAddress,
+		e.expiresAfter,
+		e.rest.IsMainnet(),
+	)
+}
+
+func (s spotDeployAction) getMap() map[string]any {
+	return nil // L1 action
+}
+
+func (s spotDeployAction) getPayloadTypes() []apitypes.Type {
+	return nil // L1 action
+}
+
+func (s spotDeployAction) getPrimaryType() string {
+	return "" // L1 action
+}
+
+// ============================================================================
+// Perp Deploy Request
+// ============================================================================
+
+// PerpDeploySchemaInput represents schema input for perp deployment
+type PerpDeploySchemaInput struct {
+	FullName        string
+	CollateralToken string
+	OracleUpdater   *common.Address
+}
+
+type perpDeployRegisterAssetRequest struct {
+	dex           string
+	maxGas        *int64
+	coin          string
+	szDecimals    int64
+	oraclePx      string
+	marginTableID int64
+	onlyIsolated  bool
+	schema        *PerpDeploySchemaInput
+}
+
+// PerpDeployRegisterAssetRequest creates a new request to register a perp
+// asset on a builder-deployed dex. maxGas and schema are optional.
+func PerpDeployRegisterAssetRequest(
+	dex string,
+	maxGas *int64,
+	coin string,
+	szDecimals int64,
+	oraclePx string,
+	marginTableID int64,
+	onlyIsolated bool,
+	schema *PerpDeploySchemaInput,
+) perpDeployRegisterAssetRequest {
+	return perpDeployRegisterAssetRequest{
+		dex:           dex,
+		maxGas:        maxGas,
+		coin:          coin,
+		szDecimals:    szDecimals,
+		oraclePx:      oraclePx,
+		marginTableID: marginTableID,
+		onlyIsolated:  onlyIsolated,
+		schema:        schema,
+	}
+}
+
+// toAction converts a perpDeployRegisterAssetRequest to a perpDeployAction
+func (p perpDeployRegisterAssetRequest) toAction(
+	ctx context.Context,
+	e *Exchange,
+	opts ...any,
+) (action, error) {
+	var schema *perpDeploySchemaWire
+	if p.schema != nil {
+		schema = &perpDeploySchemaWire{
+			FullName:        p.schema.FullName,
+			CollateralToken: p.schema.CollateralToken,
+		}
+		if p.schema.OracleUpdater != nil {
+			updater := strings.ToLower(p.schema.OracleUpdater.Hex())
+			schema.OracleUpdater = &updater
+		}
+	}
+
+	return perpDeployAction{
+		Type: "perpDeploy",
+		RegisterAsset: &perpDeployRegisterAssetWire{
+			MaxGas: p.maxGas,
+			AssetRequest: perpDeployAssetRequestWire{
+				Coin:          p.coin,
+				SzDecimals:    p.szDecimals,
+				OraclePx:      p.oraclePx,
+				MarginTableID: p.marginTableID,
+				OnlyIsolated:  p.onlyIsolated,
+			},
+			Dex:    p.dex,
+			Schema: schema,
+		},
+	}, nil
+}
+
+type perpDeploySetOracleRequest struct {
+	dex             string
+	oraclePxs       map[string]string
+	allMarkPxs      []map[string]string
+	externalPerpPxs map[string]string
+}
+
+// PerpDeploySetOracleRequest creates a new request to set the oracle, mark
+// and external perp prices of a builder-deployed dex
+func PerpDeploySetOracleRequest(
+	dex string,
+	oraclePxs map[string]string,
+	allMarkPxs []map[string]string,
+	externalPerpPxs map[string]string,
+) perpDeploySetOracleRequest {
+	return perpDeploySetOracleRequest{
+		dex:             dex,
+		oraclePxs:       oraclePxs,
+		allMarkPxs:      allMarkPxs,
+		externalPerpPxs: externalPerpPxs,
+	}
+}
+
+// toAction converts a perpDeploySetOracleRequest to a perpDeployAction. Maps
+// are serialized as key sorted pairs so the signed payload is deterministic.
+func (p perpDeploySetOracleRequest) toAction(
+	ctx context.Context,
+	e *Exchange,
+	opts ...any,
+) (action, error) {
+	markPxs := make([][][]string, len(p.allMarkPxs))
+	for i, pxs := range p.allMarkPxs {
+		markPxs[i] = sortStringMap(pxs)
+	}
+
+	return perpDeployAction{
+		Type: "perpDeploy",
+		SetOracle: &perpDeploySetOracleWire{
+			Dex:             p.dex,
+			OraclePxs:       sortStringMap(p.oraclePxs),
+			MarkPxs:         markPxs,
+			ExternalPerpPxs: sortStringMap(p.externalPerpPxs),
+		},
+	}, nil
+}
+
+type perpDeployAssetRequestWire struct {
+	Coin          string `json:"coin"`
+	SzDecimals    int64  `json:"szDecimals"`
+	OraclePx      string `json:"oraclePx"`
+	MarginTableID int64  `json:"marginTableId"`
+	OnlyIsolated  bool   `json:"onlyIsolated"`
+}
+
+type perpDeploySchemaWire struct {
+	FullName        string  `json:"fullName"`
+	CollateralToken string  `json:"collateralToken"`
+	OracleUpdater   *string `json:"oracleUpdater"`
+}
+
+type perpDeployRegisterAssetWire struct {
+	MaxGas       *int64                     `json:"maxGas"`
+	AssetRequest perpDeployAssetRequestWire `json:"assetRequest"`
+	Dex          string                     `json:"dex"`
+	Schema       *perpDeploySchemaWire      `json:"schema"`
+}
+
+type perpDeploySetOracleWire struct {
+	Dex             string       `json:"dex"`
+	OraclePxs       [][]string   `json:"oraclePxs"`
+	MarkPxs         [][][]string `json:"markPxs"`
+	ExternalPerpPxs [][]string   `json:"externalPerpPxs"`
+}
+
+// perpDeployAction carries exactly one perp deploy variant
+type perpDeployAction struct {
+	Type          string                       `json:"type"`
+	RegisterAsset *perpDeployRegisterAssetWire `json:"registerAsset,omitempty"`
+	SetOracle     *perpDeploySetOracleWire     `json:"setOracle,omitempty"`
+}
+
+func (p perpDeployAction) getType() string {
+	return p.Type
+}
+
+func (p perpDeployAction) sign(
+	privateKey *ecdsa.PrivateKey,
+	nonce int64,
+	e *Exchange,
+) (signature, error) {
+	return signL1Action(
+		p,
+		uint64(nonce),
+		privateKey,
+		e.vaultAddress,
+		e.expiresAfter,
+		e.rest.IsMainnet(),
+	)
+}
+
+func (p perpDeployAction) getMap() map[string]any {
+	return nil // L1 action
+}
+
+func (p perpDeployAction) getPayloadTypes() []apitypes.Type {
+	return nil // L1 action
+}
+
+func (p perpDeployAction) getPrimaryType() string {
+	return "" // L1 action
+}
+
+// ============================================================================
+// Agent Enable Dex Abstraction Request
+// ============================================================================
+
+type agentEnableDexAbstractionRequest struct{}
+
+// AgentEnableDexAbstractionRequest creates a request that enables DEX
+// abstraction for the agent's account
+func AgentEnableDexAbstractionRequest() agentEnableDexAbstractionRequest {
+	return agentEnableDexAbstractionRequest{}
+}
+
+// toAction converts an agentEnableDexAbstractionRequest to an
+// agentEnableDexAbstractionAction
+func (a agentEnableDexAbstractionRequest) toAction(
+	ctx context.Context,
+	e *Exchange,
+	opts ...any,
+) (action, error) {
+	return agentEnableDexAbstractionAction{
+		Type: "agentEnableDexAbstraction",
+	}, nil
+}
+
+type agentEnableDexAbstractionAction struct {
+	Type string `json:"type"`
+}
+
+func (a agentEnableDexAbstractionAction) getType() string {
+	return a.Type
+}
+
+func (a agentEnableDexAbstractionAction) sign(
+	privateKey *ecdsa.PrivateKey,
+	nonce int64,
+	e *Exchange,
+) (signature, error) {
+	return signL1Action(
+		a,
+		uint64(nonce),
+		privateKey,
+		e.vaultAddress,
+		e.expiresAfter,
+		e.rest.IsMainnet(),
+	)
+}
+
+func (a agentEnableDexAbstractionAction) getMap() map[string]any {
+	return nil // L1 action
+}
+
+func (a agentEnableDexAbstractionAction) getPayloadTypes() []apitypes.Type {
+	return nil // L1 action
+}
+
+func (a agentEnableDexAbstractionAction) getPrimaryType() string {
+	return "" // L1 action
+}
+
+// ============================================================================
+// User Dex Abstraction Request
+// ============================================================================
+
+type userDexAbstractionRequest struct {
+	user    common.Address
+	enabled bool
+}
+
+// UserDexAbstractionRequest creates a request that enables or disables DEX
+// abstraction for user
+func UserDexAbstractionRequest(
+	user common.Address,
+	enabled bool,
+) userDexAbstractionRequest {
+	return userDexAbstractionRequest{
+		user:    user,
+		enabled: enabled,
+	}
+}
+
+// toAction converts a userDexAbstractionRequest to a
+// userDexAbstractionAction
+// Note: This requires timestamp (int64) in opts
+func (u userDexAbstractionRequest) toAction(
+	ctx context.Context,
+	e *Exchange,
+	opts ...any,
+) (action, error) {
+	// Extract timestamp from opts
+	var timestamp int64
+	for _, opt := range opts {
+		if ts, ok := opt.(int64); ok {
+			timestamp = ts
+			break
+		}
+	}
+
+	if timestamp == 0 {
+		return nil, fmt.Errorf(
+			"timestamp is required in opts for userDexAbstractionRequest",
+		)
+	}
+
+	return userDexAbstractionAction{
+		Type:             "userDexAbstraction",
+		User:             strings.ToLower(u.user.Hex()),
+		Enabled:          u.enabled,
+		Nonce:            timestamp,
+		SignatureChainId: e.getSignatureChainId(),
+		HyperliquidChain: e.rest.NetworkName(),
+	}, nil
+}
+
+type userDexAbstractionAction struct {
+	Type             string `json:"type"`
+	User             string `json:"user"`
+	Enabled          bool   `json:"enabled"`
+	Nonce            int64  `json:"nonce"`
+	SignatureChainId string `json:"signatureChainId"`
+	HyperliquidChain string `json:"hyperliquidChain"`
+}
+
+func (u userDexAbstractionAction) getType() string {
+	return u.Type
+}
+
+func (u userDexAbstractionAction) sign(
+	privateKey *ecdsa.PrivateKey,
+	nonce int64,
+	e *Exchange,
+) (signature, error) {
+	return signUserDexAbstractionAction(
+		u.getMap(),
+		u.SignatureChainId,
+		privateKey,
+	)
+}
+
+func (u userDexAbstractionAction) getMap() map[string]any {
+	return map[string]any{
+		"hyperliquidChain": u.HyperliquidChain,
+		"user":             u.User,
+		"enabled":          u.Enabled,
+		"nonce":            big.NewInt(u.Nonce),
+	}
+}
+
+func (u userDexAbstractionAction) getPayloadTypes() []apitypes.Type {
+	return []apitypes.Type{
+		{Name: "hyperliquidChain", Type: "string"},
+		{Name: "user", Type: "address"},
+		{Name: "enabled", Type: "bool"},
+		{Name: "nonce", Type: "uint64"},
+	}
+}
+
+func (u userDexAbstractionAction) getPrimaryType() string {
+	return "HyperliquidTransaction:UserDexAbstraction"
+}
+
+// ============================================================================
+// Use Big Blocks Request
+// ============================================================================
+
+type useBigBlocksRequest struct {
+	enable bool
+}
+
+// UseBigBlocksRequest creates a new request to toggle big blocks for the
+// user's HyperEVM transactions
+func UseBigBlocksRequest(enable bool) useBigBlocksRequest {
+	return useBigBlocksRequest{enable: enable}
+}
+
+// toAction converts a useBigBlocksRequest to a useBigBlocksAction
+func (u useBigBlocksRequest) toAction(
+	ctx context.Context,
+	e *Exchange,
+	opts ...any,
+) (action, error) {
+	return useBigBlocksAction{
+		Type:           "evmUserModify",
+		UsingBigBlocks: u.enable,
+	}, nil
+}
+
+type useBigBlocksAction struct {
+	Type           string `json:"type"`
+	UsingBigBlocks bool   `json:"usingBigBlocks"`
+}
+
+func (u useBigBlocksAction) getType() string {
+	return u.Type
+}
+
+func (u useBigBlocksAction) sign(
+	privateKey *ecdsa.PrivateKey,
+	nonce int64,
+	e *Exchange,
+) (signature, error) {
+	return signL1Action(
+		u,
+		uint64(nonce),
+		privateKey,
+		e.vaultAddress,
+		e.expiresAfter,
+		e.rest.IsMainnet(),
+	)
+}
+
+func (u useBigBlocksAction) getMap() map[string]any {
+	return nil // L1 action
+}
+
+func (u useBigBlocksAction) getPayloadTypes() []apitypes.Type {
+	return nil // L1 action
+}
+
+func (u useBigBlocksAction) getPrimaryType() string {
+	return "" // L1 action
+}
+
+// ============================================================================
+// C Signer Request
+// ============================================================================
+
+type cSignerRequest struct {
+	variant string
+}
+
+// CSignerJailSelfRequest creates a request that jails the validator signer
+func CSignerJailSelfRequest() cSignerRequest {
+	return cSignerRequest{variant: "jailSelf"}
+}
+
+// CSignerUnjailSelfRequest creates a request that unjails the validator
+// signer
+func CSignerUnjailSelfRequest() cSignerRequest {
+	return cSignerRequest{variant: "unjailSelf"}
+}
+
+// toAction converts a cSignerRequest to a unitVariantAction
+func (c cSignerRequest) toAction(
+	ctx context.Context,
+	e *Exchange,
+	opts ...any,
+) (action, error) {
+	return unitVariantAction{Type: "CSignerAction", Variant: c.variant}, nil
+}
+
+// ============================================================================
+// C Validator Request
+// ============================================================================
+
+// CValidatorProfile is the profile a validator registers with
+type CValidatorProfile struct {
+	NodeIP              string
+	Name                string
+	Description         string
+	DelegationsDisabled bool
+	CommissionBps       int64
+	Signer              common.Address
+}
+
+// CValidatorProfileChanges lists the profile fields to change. Nil fields are
+// left unchanged.
+type CValidatorProfileChanges struct {
+	NodeIP             *string
+	Name               *string
+	Description        *string
+	DisableDelegations *bool
+	CommissionBps      *int64
+	Signer             *common.Address
+}
+
+type cValidatorRegisterRequest struct {
+	profile    CValidatorProfile
+	unjailed   bool
+	initialWei int64
+}
+
+// CValidatorRegisterRequest creates a request to register a validator
+func CValidatorRegisterRequest(
+	profile CValidatorProfile,
+	unjailed bool,
+	initialWei int64,
+) cValidatorRegisterRequest {
+	return cValidatorRegisterRequest{
+		profile:    profile,
+		unjailed:   unjailed,
+		initialWei: initialWei,
+	}
+}
+
+// toAction converts a cValidatorRegisterRequest to a
+// cValidatorRegisterAction
+func (c cValidatorRegisterRequest) toAction(
+	ctx context.Context,
+	e *Exchange,
+	opts ...any,
+) (action, error) {
+	return cValidatorRegisterAction{
+		Type: "CValidatorAction",
+		Register: cValidatorRegisterWire{
+			Profile: cValidatorProfileWire{
+				NodeIP:              cValidatorNodeIPWire{Ip: c.profile.NodeIP},
+				Name:                c.profile.Name,
+				Description:         c.profile.Description,
+				DelegationsDisabled: c.profile.DelegationsDisabled,
+				CommissionBps:       c.profile.CommissionBps,
+				Signer:              strings.ToLower(c.profile.Signer.Hex()),
+			},
+			Unjailed:   c.unjailed,
+			InitialWei: c.initialWei,
+		},
+	}, nil
+}
+
+type cValidatorChangeProfileRequest struct {
+	unjailed bool
+	changes  CValidatorProfileChanges
+}
+
+// CValidatorChangeProfileRequest creates a request to change a validator's
+// profile
+func CValidatorChangeProfileRequest(
+	unjailed bool,
+	changes CValidatorProfileChanges,
+) cValidatorChangeProfileRequest {
+	return cValidatorChangeProfileRequest{
+		unjailed: unjailed,
+		changes:  changes,
+	}
+}
+
+// toAction converts a cValidatorChangeProfileRequest to a
+// cValidatorChangeProfileAction
+func (c cValidatorChangeProfileRequest) toAction(
+	ctx context.Context,
+	e *Exchange,
+	opts ...any,
+) (action, error) {
+	var nodeIP *cValidatorNodeIPWire
+	if c.changes.NodeIP != nil {
+		nodeIP = &cValidatorNodeIPWire{Ip: *c.changes.NodeIP}
+	}
+
+	var signer *string
+	if c.changes.Signer != nil {
+		s := strings.ToLower(c.changes.Signer.Hex())
+		signer = &s
+	}
+
+	return cValidatorChangeProfileAction{
+		Type: "CValidatorAction",
+		ChangeProfile: cValidatorChangeProfileWire{
+			NodeIP:             nodeIP,
+			Name:               c.changes.Name,
+			Description:        c.changes.Description,
+			Unjailed:           c.unjailed,
+			DisableDelegations: c.changes.DisableDelegations,
+			CommissionBps:      c.changes.CommissionBps,
+			Signer:             signer,
+		},
+	}, nil
+}
+
+type cValidatorUnregisterRequest struct{}
+
+// CValidatorUnregisterRequest creates a request to unregister a validator
+func CValidatorUnregisterRequest() cValidatorUnregisterRequest {
+	return cValidatorUnregisterRequest{}
+}
+
+// toAction converts a cValidatorUnregisterRequest to a unitVariantAction
+func (c cValidatorUnregisterRequest) toAction(
+	ctx context.Context,
+	e *Exchange,
+	opts ...any,
+) (action, error) {
+	return unitVariantAction{
+		Type:    "CValidatorAction",
+		Variant: "unregister",
+	}, nil
+}
+
+// ============================================================================
+// C Validator Action
+// ============================================================================
+
+type cValidatorNodeIPWire struct {
+	Ip string `json:"Ip"`
+}
+
+type cValidatorProfileWire struct {
+	NodeIP              cValidatorNodeIPWire `json:"node_ip"`
+	Name                string               `json:"name"`
+	Description         string               `json:"description"`
+	DelegationsDisabled bool                 `json:"delegations_disabled"`
+	CommissionBps       int64                `json:"commission_bps"`
+	Signer              string               `json:"signer"`
+}
+
+type cValidatorRegisterWire struct {
+	Profile    cValidatorProfileWire `json:"profile"`
+	Unjailed   bool                  `json:"unjailed"`
+	InitialWei int64                 `json:"initial_wei"`
+}
+
+type cValidatorRegisterAction struct {
+	Type     string                 `json:"type"`
+	Register cValidatorRegisterWire `json:"register"`
+}
+
+func (c cValidatorRegisterAction) getType() string {
+	return c.Type
+}
+
+func (c cValidatorRegisterAction) sign(
+	privateKey *ecdsa.PrivateKey,
+	nonce int64,
+	e *Exchange,
+) (signature, error) {
+	return signL1Action(
+		c,
+		uint64(nonce),
+		privateKey,
+		e.vaultAddress,
+		e.expiresAfter,
+		e.rest.IsMainnet(),
+	)
+}
+
+func (c cValidatorRegisterAction) getMap() map[string]any {
+	return nil // L1 action
+}
+
+func (c cValidatorRegisterAction) getPayloadTypes() []apitypes.Type {
+	return nil // L1 action
+}
+
+func (c cValidatorRegisterAction) getPrimaryType() string {
+	return "" // L1 action
+}
+
+// cValidatorChangeProfileWire sends every key, with null for the fields that
+// are left unchanged
+type cValidatorChangeProfileWire struct {
+	NodeIP             *cValidatorNodeIPWire `json:"node_ip"`
+	Name               *string               `json:"name"`
+	Description        *string               `json:"description"`
+	Unjailed           bool                  `json:"unjailed"`
+	DisableDelegations *bool                 `json:"disable_delegations"`
+	CommissionBps      *int64                `json:"commission_bps"`
+	Signer             *string               `json:"signer"`
+}
+
+type cValidatorChangeProfileAction struct {
+	Type          string                      `json:"type"`
+	ChangeProfile cValidatorChangeProfileWire `json:"changeProfile"`
+}
+
+func (c cValidatorChangeProfileAction) getType() string {
+	return c.Type
+}
+
+func (c cValidatorChangeProfileAction) sign(
+	privateKey *ecdsa.PrivateKey,
+	nonce int64,
+	e *Exchange,
+) (signature, error) {
+	return signL1Action(
+		c,
+		uint64(nonce),
+		privateKey,
+		e.vaultAddress,
+		e.expiresAfter,
+		e.rest.IsMainnet(),
+	)
+}
+
+func (c cValidatorChangeProfileAction) getMap() map[string]any {
+	return nil // L1 action
+}
+
+func (c cValidatorChangeProfileAction) getPayloadTypes() []apitypes.Type {
+	return nil // L1 action
+}
+
+func (c cValidatorChangeProfileAction) getPrimaryType() string {
+	return "" // L1 action
+}
+
+// ============================================================================
+// Unit Variant Action
+// ============================================================================
+
+// unitVariantAction is an L1 action of the form {"type": Type, Variant: null},
+// used where the protocol selects an operation by the presence of a key with
+// no value
+type unitVariantAction struct {
+	Type    string
+	Variant string
+}
+
+var (
+	_ json.Marshaler        = unitVariantAction{}
+	_ msgpack.CustomEncoder = unitVariantAction{}
+)
+
+func (u unitVariantAction) MarshalJSON() ([]byte, error) {
+	t, err := json.Marshal(u.Type)
+	if err != nil {
+		return nil, err
+	}
+	v, err := json.Marshal(u.Variant)
+	if err != nil {
+		return nil, err
+	}
+	return fmt.Appendf(nil, `{"type":%s,%s:null}`, t, v), nil
+}
+
+// EncodeMsgpack keeps type before the variant key, matching the order the
+// action is hashed in by the reference SDK
+func (u unitVariantAction) EncodeMsgpack(enc *msgpack.Encoder) error {
+	if err := enc.EncodeMapLen(2); err != nil {
+		return err
+	}
+	if err := enc.EncodeString("type"); err != nil {
+		return err
+	}
+	if err := enc.EncodeString(u.Type); err != nil {
+		return err
+	}
+	if err := enc.EncodeString(u.Variant); err != nil {
+		return err
+	}
+	return enc.EncodeNil()
+}
+
+func (u unitVariantAction) getType() string {
+	return u.Type
+}
+
+func (u unitVariantAction) sign(
+	privateKey *ecdsa.PrivateKey,
+	nonce int64,
+	e *Exchange,
+) (signature, error) {
+	return signL1Action(
+		u,
+		uint64(nonce),
+		privateKey,
+		e.vaultAddress,
+		e.expiresAfter,
+		e.rest.IsMainnet(),
+	)
+}
+
+func (u unitVariantAction) getMap() map[string]any {
+	return nil // L1 action
+}
+
+func (u unitVariantAction) getPayloadTypes() []apitypes.Type {
+	return nil // L1 action
+}
+
+func (u unitVariantAction) getPrimaryType() string {
+	return "" // L1 action
+}
+
 // ============================================================================
 // Multi Sig Request
 // ============================================================================
@@ -2660,7 +4190,7 @@ func (m multiSigRequest[T]) toAction(
 	// Create the multiSigAction
 	return multiSigAction{
 		Type:             "multiSig",
-		SignatureChainId: getSignatureChainId(),
+		SignatureChainId: e.getSignatureChainId(),
 		Signatures:       m.signatures,
 		Payload: multiSigPayload{
 			MultiSigUser: strings.ToLower(m.multiSigUser.Hex()),
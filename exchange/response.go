@@ -111,12 +111,27 @@ type OrderResponse struct {
 type BulkOrdersResponse []OrderResponse
 
 // UnmarshalJSON unmarshals the response into a flat slice of OrderStatus
+//
+// Every status is decoded even if some orders were rejected. In that case a
+// *StatusError listing each rejected order by index is returned.
 func (or *BulkOrdersResponse) UnmarshalJSON(data []byte) error {
-	statuses, err := extractStatuses[OrderResponse](data)
+	statuses, err := extractStatuses[json.RawMessage](data)
 	if err != nil {
 		return err
 	}
-	*or = BulkOrdersResponse(statuses)
+
+	orders := make(BulkOrdersResponse, len(statuses))
+	var failures []StatusFailure
+	for i, status := range statuses {
+		if err := json.Unmarshal(status, &orders[i]); err != nil {
+			failures = append(failures, StatusFailure{Index: i, Err: err})
+		}
+	}
+	*or = orders
+
+	if len(failures) > 0 {
+		return &StatusError{Failures: failures, Orders: orders}
+	}
 	return nil
 }
 
@@ -136,7 +151,7 @@ func (os *OrderResponse) UnmarshalJSON(data []byte) error {
 	// If there's an error in the response, bubble it up
 	// TODO: Stop bubbling it makes things harder for bulk
 	if obj.Error != nil {
-		return fmt.Errorf("%s", *obj.Error)
+		return orderStatusError(*obj.Error)
 	}
 
 	if obj.Resting != nil || obj.Filled != nil {
@@ -184,6 +199,33 @@ type OrderStatusFilled struct {
 	Oid     int64  `json:"oid"`
 }
 
+/*//////////////////////////////////////////////////////////////
+                             MODIFY
+//////////////////////////////////////////////////////////////*/
+
+// ModifyPath describes how a modify request was submitted
+type ModifyPath string
+
+const (
+	// ModifyPathModify means the order was modified in place with batchModify
+	ModifyPathModify ModifyPath = "modify"
+	// ModifyPathCancelReplace means the modify changed the order type or was
+	// made with WithModifyCancelReplace, so the order was cancelled and a new
+	// order was placed
+	ModifyPathCancelReplace ModifyPath = "cancelReplace"
+)
+
+// ModifyResponse is the status of a single modify along with the path that
+// was taken to apply it
+type ModifyResponse struct {
+	OrderResponse
+	Path ModifyPath
+}
+
+// BulkModifyResponse holds one ModifyResponse per modify request, in the same
+// order as the requests
+type BulkModifyResponse []ModifyResponse
+
 /*//////////////////////////////////////////////////////////////
                              CANCEL
 //////////////////////////////////////////////////////////////*/
@@ -231,6 +273,65 @@ func (c *CancelResponse) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+/*//////////////////////////////////////////////////////////////
+                              TWAP
+//////////////////////////////////////////////////////////////*/
+
+// TwapOrderResponse holds the id of a running TWAP order
+type TwapOrderResponse struct {
+	TwapId int64 `json:"twapId"`
+}
+
+// UnmarshalJSON unwraps the twap id from the response data and bubbles up
+// errors
+func (tr *TwapOrderResponse) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Data struct {
+			Status struct {
+				Running *struct {
+					TwapId int64 `json:"twapId"`
+				} `json:"running,omitempty"`
+				Error *string `json:"error,omitempty"`
+			} `json:"status"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	status := raw.Data.Status
+	if status.Error != nil {
+		return fmt.Errorf("%s", *status.Error)
+	}
+	if status.Running == nil {
+		return fmt.Errorf("Expected running twap status, but got none")
+	}
+
+	tr.TwapId = status.Running.TwapId
+	return nil
+}
+
+// TwapCancelResponse holds the status of a TWAP cancel
+type TwapCancelResponse struct {
+	CancelResponse
+}
+
+// UnmarshalJSON unwraps the cancel status from the response data and bubbles
+// up errors
+func (tr *TwapCancelResponse) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Data struct {
+			Status CancelResponse `json:"status"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	tr.CancelResponse = raw.Data.Status
+	return nil
+}
+
 /*//////////////////////////////////////////////////////////////
                             UPDATES
 //////////////////////////////////////////////////////////////*/
@@ -2,6 +2,7 @@ package exchange
 
 import (
 	"encoding/json"
+	"errors"
 	"strings"
 	"testing"
 )
@@ -39,6 +40,21 @@ const (
    }
 }`
 
+	okInsufficientMarginJSON = `
+{
+   "status":"ok",
+   "response":{
+      "type":"order",
+      "data":{
+         "statuses":[
+            {
+               "error":"Insufficient margin to place order. asset=0"
+            }
+         ]
+      }
+   }
+}`
+
 	errTopLevelJSON = `
 {
    "status": "err",
@@ -140,6 +156,30 @@ func TestUnmarshalResponse_OK_ErrorStatus(t *testing.T) {
 	}
 }
 
+func TestUnmarshalResponse_OK_InsufficientMargin(t *testing.T) {
+	var resp response[BulkOrdersResponse]
+
+	err := json.Unmarshal([]byte(okInsufficientMarginJSON), &resp)
+	if err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+	if !errors.Is(err, ErrInsufficientMargin) {
+		t.Fatalf("expected ErrInsufficientMargin, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "asset=0") {
+		t.Fatal("Error doesn't contain original message")
+	}
+}
+
+func TestUnmarshalResponse_OK_ErrorStatusNotInsufficientMargin(t *testing.T) {
+	var resp response[BulkOrdersResponse]
+
+	err := json.Unmarshal([]byte(okErrorStatusJSON), &resp)
+	if errors.Is(err, ErrInsufficientMargin) {
+		t.Fatalf("did not expect ErrInsufficientMargin, got %v", err)
+	}
+}
+
 func TestUnmarshalResponse_Err_TopLevel(t *testing.T) {
 	var resp response[OrderResponse]
 
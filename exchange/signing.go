@@ -177,20 +177,33 @@ func signMultiSigAction(
 			{Name: "nonce", Type: "uint64"},
 		},
 		"HyperliquidTransaction:SendMultiSig",
+		action.SignatureChainId,
 		privateKey,
 	)
 }
 
+// signUserSignedAction signs action as EIP-712 typed data. The domain chain
+// id is the action's signatureChainId, so the two always agree.
 func signUserSignedAction(
 	action map[string]any,
 	payloadTypes []apitypes.Type,
 	primaryType string,
+	signatureChainId string,
 	privateKey *ecdsa.PrivateKey,
 ) (signature, error) {
+	chainId, ok := math.ParseBig256(signatureChainId)
+	if !ok {
+		return signature{}, fmt.Errorf(
+			"invalid signature chain id %q",
+			signatureChainId,
+		)
+	}
+
 	typedData := userSignedPayload(
 		primaryType,
 		payloadTypes,
 		action,
+		chainId,
 	)
 
 	hash, _, err := apitypes.TypedDataAndHash(typedData)
@@ -211,6 +224,7 @@ func signMultiSigUserSignedActionPayload[T action](
 	primaryType string,
 	multiSigUser common.Address,
 	outerSigner common.Address,
+	signatureChainId string,
 ) (signature, error) {
 	actionMap := a.getMap()
 
@@ -240,6 +254,7 @@ func signMultiSigUserSignedActionPayload[T action](
 		actionMap,
 		enrichedTypes,
 		primaryType,
+		signatureChainId,
 		privateKey,
 	)
 }
@@ -264,6 +279,7 @@ func signUsdTransferAction(
 			{Name: "time", Type: "uint64"},
 		},
 		"HyperliquidTransaction:UsdSend",
+		action.SignatureChainId,
 		privateKey,
 	)
 }
@@ -290,6 +306,7 @@ func signSpotTransferAction(
 			{Name: "time", Type: "uint64"},
 		},
 		"HyperliquidTransaction:SpotSend",
+		action.SignatureChainId,
 		privateKey,
 	)
 }
@@ -314,6 +331,7 @@ func signWithdrawFromBridgeAction(
 			{Name: "time", Type: "uint64"},
 		},
 		"HyperliquidTransaction:Withdraw",
+		action.SignatureChainId,
 		privateKey,
 	)
 }
@@ -338,6 +356,7 @@ func signUsdClassTransferAction(
 			{Name: "nonce", Type: "uint64"},
 		},
 		"HyperliquidTransaction:UsdClassTransfer",
+		action.SignatureChainId,
 		privateKey,
 	)
 }
@@ -370,12 +389,14 @@ func signSendAssetAction(
 			{Name: "nonce", Type: "uint64"},
 		},
 		"HyperliquidTransaction:SendAsset",
+		action.SignatureChainId,
 		privateKey,
 	)
 }
 
 func signUserDexAbstractionAction(
 	action map[string]any,
+	signatureChainId string,
 	privateKey *ecdsa.PrivateKey,
 ) (signature, error) {
 	return signUserSignedAction(
@@ -387,6 +408,7 @@ func signUserDexAbstractionAction(
 			{Name: "nonce", Type: "uint64"},
 		},
 		"HyperliquidTransaction:UserDexAbstraction",
+		signatureChainId,
 		privateKey,
 	)
 }
@@ -409,6 +431,7 @@ func signConvertToMultiSigUserAction(
 			{Name: "nonce", Type: "uint64"},
 		},
 		"HyperliquidTransaction:ConvertToMultiSigUser",
+		action.SignatureChainId,
 		privateKey,
 	)
 }
@@ -420,7 +443,7 @@ func signTokenDelegateAction(
 	actionMap := map[string]any{
 		"hyperliquidChain": action.HyperliquidChain,
 		"validator":        action.Validator,
-		"wei":              big.NewInt(action.Wei),
+		"wei":              new(big.Int).SetUint64(action.Wei),
 		"isUndelegate":     action.IsUndelegate,
 		"nonce":            big.NewInt(action.Nonce),
 	}
@@ -435,6 +458,7 @@ func signTokenDelegateAction(
 			{Name: "nonce", Type: "uint64"},
 		},
 		"HyperliquidTransaction:TokenDelegate",
+		action.SignatureChainId,
 		privateKey,
 	)
 }
@@ -459,6 +483,7 @@ func signAgentAction(
 			{Name: "nonce", Type: "uint64"},
 		},
 		"HyperliquidTransaction:ApproveAgent",
+		action.SignatureChainId,
 		privateKey,
 	)
 }
@@ -483,6 +508,7 @@ func signApproveBuilderFeeAction(
 			{Name: "nonce", Type: "uint64"},
 		},
 		"HyperliquidTransaction:ApproveBuilderFee",
+		action.SignatureChainId,
 		privateKey,
 	)
 }
@@ -611,6 +637,7 @@ func userSignedPayload(
 	primaryType string,
 	payloadTypes []apitypes.Type,
 	action apitypes.TypedDataMessage,
+	chainId *big.Int,
 ) apitypes.TypedData {
 	types := apitypes.Types{
 		"EIP712Domain": {
@@ -629,7 +656,7 @@ func userSignedPayload(
 		Domain: apitypes.TypedDataDomain{
 			Name:              "HyperliquidSignTransaction",
 			Version:           "1",
-			ChainId:           math.NewHexOrDecimal256(421614),
+			ChainId:           (*math.HexOrDecimal256)(chainId),
 			VerifyingContract: "0x0000000000000000000000000000000000000000",
 		},
 		Message: action,
@@ -1,17 +1,26 @@
 package exchange
 
 import (
+	"bytes"
+	"context"
 	"crypto/ecdsa"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/banky/go-hyperliquid/constants"
+	"github.com/banky/go-hyperliquid/logging"
 	"github.com/banky/go-hyperliquid/rest"
 	"github.com/banky/go-hyperliquid/types"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
 	"github.com/samber/mo"
+	"github.com/vmihailenco/msgpack/v5"
 )
 
 // Helper to create a test private key
@@ -45,6 +54,7 @@ func testExchange(isMainnet bool) *Exchange {
 		vaultAddress:   mo.None[common.Address](),
 		expiresAfter:   mo.None[time.Duration](),
 		rest:           restClient,
+		logger:         logging.Nop(),
 	}
 }
 
@@ -115,7 +125,7 @@ func TestL1SigningOrderWithCloidMatches(t *testing.T) {
 		100,
 		WithLimitOrder(LimitOrder{Tif: "Gtc"}),
 		WithReduceOnly(false),
-		WithCloid(types.HexToCloid("0x00000000000000000000000000000001")),
+		WithCloid(types.MustHexToCloid("0x00000000000000000000000000000001")),
 	)
 
 	wire, err := order.toOrderWire(1)
@@ -239,7 +249,7 @@ func TestSignUsdTransferAction(t *testing.T) {
 		Destination:      "0x5e9ee1089755c3435139848e47e6635505d5a13a",
 		Time:             1687816341423,
 		HyperliquidChain: "Testnet",
-		SignatureChainId: getSignatureChainId(),
+		SignatureChainId: "0x66eee",
 	}
 
 	sig, err := signUsdTransferAction(action, privateKey)
@@ -276,6 +286,62 @@ func TestSignUsdTransferAction(t *testing.T) {
 	}
 }
 
+func TestSignUsdTransferActionPerNetwork(t *testing.T) {
+	destination := common.HexToAddress(
+		"0x5e9ee1089755c3435139848e47e6635505d5a13a",
+	)
+	nonce := int64(1687816341423)
+
+	custom := testExchange(false)
+	custom.chainID = 42161
+
+	tests := []struct {
+		name     string
+		e        *Exchange
+		chain    string
+		chainId  int64
+		chainHex string
+	}{
+		{"mainnet", testExchange(true), "Mainnet", 421614, "0x66eee"},
+		{"testnet", testExchange(false), "Testnet", 421614, "0x66eee"},
+		{"custom", custom, "Testnet", 42161, "0xa4b1"},
+	}
+
+	seen := make(map[signature]string)
+	for _, tt := range tests {
+		a, err := UsdTransferRequest(1, destination).
+			toAction(context.Background(), tt.e, nonce)
+		if err != nil {
+			t.Fatal(err)
+		}
+		transfer := a.(usdTransferAction)
+		if transfer.HyperliquidChain != tt.chain ||
+			transfer.SignatureChainId != tt.chainHex {
+			t.Fatalf("%s: unexpected action %+v", tt.name, transfer)
+		}
+
+		sig, err := a.sign(tt.e.privateKey, nonce, tt.e)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if other, ok := seen[sig]; ok {
+			t.Errorf("%s and %s produced the same signature", other, tt.name)
+		}
+		seen[sig] = tt.name
+
+		// The domain chain id must match the signatureChainId that is sent
+		expected := crypto.PubkeyToAddress(tt.e.privateKey.PublicKey)
+		signer := recoverUserSignedSigner(t, a, sig, tt.chainId)
+		if signer != expected {
+			t.Errorf("%s: signer mismatch: got %s", tt.name, signer.Hex())
+		}
+		signer = recoverUserSignedSigner(t, a, sig, tt.chainId+1)
+		if signer == expected {
+			t.Errorf("%s: signature verified under another chain", tt.name)
+		}
+	}
+}
+
 func TestSubAccountTransferAction(t *testing.T) {
 	privateKey, err := crypto.HexToECDSA(
 		"0123456789012345678901234567890123456789012345678901234567890123",
@@ -355,6 +421,7 @@ func TestSignMultisigUserSignedPayload(t *testing.T) {
 		action.getPrimaryType(),
 		common.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266"),
 		crypto.PubkeyToAddress(privateKey.PublicKey),
+		"0x66eee",
 	)
 	if err != nil {
 		t.Fatal(err)
@@ -389,6 +456,65 @@ func TestSignMultisigUserSignedPayload(t *testing.T) {
 	}
 }
 
+func TestSignConvertFromMultiSigUserPayload(t *testing.T) {
+	privateKey, err := crypto.HexToECDSA(
+		"0123456789012345678901234567890123456789012345678901234567890123",
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	action, err := ConvertFromMultiSigUserRequest().toAction(
+		context.Background(),
+		testExchange(false),
+		int64(1764899871274),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sig, err := signMultiSigUserSignedActionPayload(
+		action,
+		privateKey,
+		action.getPayloadTypes(),
+		action.getPrimaryType(),
+		common.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266"),
+		crypto.PubkeyToAddress(privateKey.PublicKey),
+		"0x66eee",
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedR := common.HexToHash(
+		"0x54e5a28a9ab662a97e6a1021b91b73e897913f7dd31182dbe9e83fc9710e913a",
+	)
+	expectedS := common.HexToHash(
+		"0x3645e763e7ee3055df3b2349d9d18b224612d117d54f7f857ea7750162715815",
+	)
+	expectedV := byte(28)
+
+	if sig.R != expectedR {
+		t.Fatalf(
+			"R mismatch: expected %s, got %s",
+			expectedR.Hex(),
+			sig.R.Hex(),
+		)
+	}
+
+	if sig.S != expectedS {
+		t.Fatalf(
+			"S mismatch: expected %s, got %s",
+			expectedS.Hex(),
+			sig.S.Hex(),
+		)
+	}
+
+	if sig.V != expectedV {
+		t.Fatalf("V mismatch: expected %d, got %d", expectedV, sig.V)
+	}
+}
+
 func TestSignMultisigAction(t *testing.T) {
 	privateKey, err := crypto.HexToECDSA(
 		"ac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80",
@@ -428,6 +554,7 @@ func TestSignMultisigAction(t *testing.T) {
 		action.getPrimaryType(),
 		multisigUser,
 		crypto.PubkeyToAddress(privateKey.PublicKey),
+		"0x66eee",
 	)
 	if err != nil {
 		t.Fatal(err)
@@ -487,6 +614,371 @@ func TestSignMultisigAction(t *testing.T) {
 	}
 }
 
+// recoverL1Signer rebuilds the EIP-712 hash for an L1 action and recovers the
+// address that produced the signature
+func recoverL1Signer[T any](
+	t *testing.T,
+	action T,
+	nonce uint64,
+	sig signature,
+	isMainnet bool,
+) common.Address {
+	t.Helper()
+
+	actionHash, err := hashAction(
+		action,
+		mo.None[common.Address](),
+		nonce,
+		mo.None[time.Duration](),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	typedData := l1Payload(constructPhantomAgent(actionHash, isMainnet))
+	hash, _, err := apitypes.TypedDataAndHash(typedData)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rawSig := make([]byte, 65)
+	copy(rawSig[:32], sig.R[:])
+	copy(rawSig[32:64], sig.S[:])
+	rawSig[64] = sig.V - 27
+
+	pubKey, err := crypto.SigToPub(hash, rawSig)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return crypto.PubkeyToAddress(*pubKey)
+}
+
+func TestSignClaimRewardsAction(t *testing.T) {
+	e := testExchange(false)
+
+	action, err := ClaimRewardsRequest().toAction(context.Background(), e)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if action.getType() != "claimRewards" {
+		t.Fatalf("expected type claimRewards, got %s", action.getType())
+	}
+
+	nonce := int64(1764899871274)
+	sig, err := action.sign(e.privateKey, nonce, e)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signer := recoverL1Signer(t, action, uint64(nonce), sig, false)
+	expected := crypto.PubkeyToAddress(e.privateKey.PublicKey)
+	if signer != expected {
+		t.Fatalf(
+			"signer mismatch: expected %s, got %s",
+			expected.Hex(),
+			signer.Hex(),
+		)
+	}
+}
+
+func TestTokenDelegateLargeWei(t *testing.T) {
+	e := testExchange(false)
+	validator := common.HexToAddress(
+		"0x946bf3135c7d15e4462b510f74b6e304aabb5b21",
+	)
+
+	// Larger than math.MaxInt64 but still a valid uint64
+	wei, _ := new(big.Int).SetString("18000000000000000000", 10)
+
+	action, err := TokenDelegateRequest(validator, wei, false).
+		toAction(context.Background(), e, int64(1764899871274))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := json.Marshal(action)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"wei":18000000000000000000`) {
+		t.Fatalf("expected exact wei in wire action, got %s", data)
+	}
+
+	if _, err := action.sign(e.privateKey, 1764899871274, e); err != nil {
+		t.Fatalf("failed to sign large wei: %v", err)
+	}
+
+	tooLarge := new(big.Int).Lsh(big.NewInt(1), 64)
+	_, err = TokenDelegateRequest(validator, tooLarge, false).
+		toAction(context.Background(), e, int64(1764899871274))
+	if err == nil {
+		t.Fatal("expected error for wei larger than uint64")
+	}
+}
+
+// recoverUserSignedSigner rebuilds the EIP-712 hash for a user-signed action
+// under the domain chainId and recovers the address that produced the
+// signature
+func recoverUserSignedSigner(
+	t *testing.T,
+	a action,
+	sig signature,
+	chainId int64,
+) common.Address {
+	t.Helper()
+
+	typedData := userSignedPayload(
+		a.getPrimaryType(),
+		a.getPayloadTypes(),
+		a.getMap(),
+		big.NewInt(chainId),
+	)
+	hash, _, err := apitypes.TypedDataAndHash(typedData)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rawSig := make([]byte, 65)
+	copy(rawSig[:32], sig.R[:])
+	copy(rawSig[32:64], sig.S[:])
+	rawSig[64] = sig.V - 27
+
+	pubKey, err := crypto.SigToPub(hash, rawSig)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return crypto.PubkeyToAddress(*pubKey)
+}
+
+func TestBuildSignedWithdraw(t *testing.T) {
+	e := testExchange(false)
+	destination := common.HexToAddress(
+		"0x5e9ee1089755c3435139848e47e6635505d5a13a",
+	)
+	nonce := int64(1764899871274)
+
+	payload, err := e.BuildSignedWithdraw(10.5, destination, nonce)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if payload["nonce"] != nonce {
+		t.Fatalf("expected nonce %d, got %v", nonce, payload["nonce"])
+	}
+
+	a, ok := payload["action"].(withdrawFromBridgeAction)
+	if !ok {
+		t.Fatalf("unexpected action type %T", payload["action"])
+	}
+	if a.Type != "withdraw3" || a.Amount != "10.5" || a.Time != nonce {
+		t.Fatalf("unexpected action %+v", a)
+	}
+
+	sig, ok := payload["signature"].(signature)
+	if !ok {
+		t.Fatalf("unexpected signature type %T", payload["signature"])
+	}
+
+	signer := recoverUserSignedSigner(
+		t,
+		a,
+		sig,
+		constants.SIGNATURE_CHAIN_ID,
+	)
+	expected := crypto.PubkeyToAddress(e.privateKey.PublicKey)
+	if signer != expected {
+		t.Fatalf(
+			"signer mismatch: expected %s, got %s",
+			expected.Hex(),
+			signer.Hex(),
+		)
+	}
+
+	// The payload must survive being serialized for transport
+	if _, err := json.Marshal(payload); err != nil {
+		t.Fatalf("failed to marshal payload: %v", err)
+	}
+}
+
+func TestSpotDeployRegisterTokenWireShape(t *testing.T) {
+	e := testExchange(false)
+
+	action, err := SpotDeployRegisterTokenRequest(
+		"TEST",
+		2,
+		8,
+		1000000,
+		"Test Token",
+	).toAction(context.Background(), e)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := json.Marshal(action)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{"type":"spotDeploy","registerToken2":{"spec":{"name":"TEST","szDecimals":2,"weiDecimals":8},"maxGas":1000000,"fullName":"Test Token"}}`
+	if string(got) != expected {
+		t.Fatalf("wire shape mismatch:\nexpected %s\ngot      %s", expected, got)
+	}
+
+	nonce := int64(1764899871274)
+	sig, err := action.sign(e.privateKey, nonce, e)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signer := recoverL1Signer(t, action, uint64(nonce), sig, false)
+	if signer != crypto.PubkeyToAddress(e.privateKey.PublicKey) {
+		t.Fatalf("unexpected signer %s", signer.Hex())
+	}
+}
+
+func TestSpotDeployGenesisFlowWireShape(t *testing.T) {
+	e := testExchange(false)
+	ctx := context.Background()
+	user := common.HexToAddress("0x946Bf3135c7D15E4462b510f74B6e304AABb5B21")
+	levels := int64(3)
+
+	tests := []struct {
+		name     string
+		req      request
+		expected string
+	}{
+		{
+			name: "userGenesis",
+			req: SpotDeployUserGenesisRequest(
+				1,
+				[]UserWeiPair{{User: user, Wei: big.NewInt(100)}},
+				[]TokenWeiPair{{Token: 0, Wei: big.NewInt(5)}},
+			),
+			expected: `{"type":"spotDeploy","userGenesis":{"token":1,"userAndWei":[["0x946bf3135c7d15e4462b510f74b6e304aabb5b21","100"]],"existingTokenAndWei":[[0,"5"]]}}`,
+		},
+		{
+			name:     "genesis",
+			req:      SpotDeployGenesisRequest(1, "1000000", false),
+			expected: `{"type":"spotDeploy","genesis":{"token":1,"maxSupply":"1000000"}}`,
+		},
+		{
+			name:     "registerSpot",
+			req:      SpotDeployRegisterSpotRequest(1, 0),
+			expected: `{"type":"spotDeploy","registerSpot":{"tokens":[1,0]}}`,
+		},
+		{
+			name: "registerHyperliquidity",
+			req: SpotDeployRegisterHyperliquidityRequest(
+				2,
+				1.5,
+				10,
+				4,
+				&levels,
+			),
+			expected: `{"type":"spotDeploy","registerHyperliquidity":{"spot":2,"startPx":"1.5","orderSz":"10","nOrders":4,"nSeededLevels":3}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			action, err := tt.req.toAction(ctx, e)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := json.Marshal(action)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if string(got) != tt.expected {
+				t.Fatalf(
+					"wire shape mismatch:\nexpected %s\ngot      %s",
+					tt.expected,
+					got,
+				)
+			}
+		})
+	}
+}
+
+func TestPerpDeploySetOracleSortedStable(t *testing.T) {
+	e := testExchange(false)
+	oraclePxs := map[string]string{
+		"dex:ZETA":  "3.1",
+		"dex:ALPHA": "1.5",
+		"dex:MID":   "2.25",
+	}
+	markPxs := []map[string]string{
+		{"dex:MID": "2.2", "dex:ALPHA": "1.4"},
+		{"dex:ZETA": "3.0"},
+	}
+	externalPxs := map[string]string{"dex:ZETA": "3.2", "dex:ALPHA": "1.6"}
+
+	req := PerpDeploySetOracleRequest("dex", oraclePxs, markPxs, externalPxs)
+
+	expected := `{"type":"perpDeploy","setOracle":{"dex":"dex","oraclePxs":[["dex:ALPHA","1.5"],["dex:MID","2.25"],["dex:ZETA","3.1"]],"markPxs":[[["dex:ALPHA","1.4"],["dex:MID","2.2"]],[["dex:ZETA","3.0"]]],"externalPerpPxs":[["dex:ALPHA","1.6"],["dex:ZETA","3.2"]]}}`
+
+	var firstHash common.Hash
+	for i := 0; i < 20; i++ {
+		action, err := req.toAction(context.Background(), e)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := json.Marshal(action)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != expected {
+			t.Fatalf("wire shape mismatch:\nexpected %s\ngot      %s", expected, got)
+		}
+
+		hash, err := hashAction(
+			action,
+			mo.None[common.Address](),
+			1764899871274,
+			mo.None[time.Duration](),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if i == 0 {
+			firstHash = hash
+		} else if hash != firstHash {
+			t.Fatalf("action hash changed between calls")
+		}
+	}
+}
+
+func TestUseBigBlocksWireShape(t *testing.T) {
+	e := testExchange(false)
+
+	for _, enable := range []bool{true, false} {
+		action, err := UseBigBlocksRequest(enable).
+			toAction(context.Background(), e)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := json.Marshal(action)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expected := fmt.Sprintf(
+			`{"type":"evmUserModify","usingBigBlocks":%t}`,
+			enable,
+		)
+		if string(got) != expected {
+			t.Fatalf("expected %s, got %s", expected, got)
+		}
+	}
+}
+
 // func TestL1ActionSigningProducesValidSignature(t *testing.T) {
 // 	ex := testExchange(true)
 // 	numStr, _ := floatToWire(1000)
@@ -1203,3 +1695,20 @@ func TestSignMultisigAction(t *testing.T) {
 // 		t.Errorf("mainnet V mismatch (with time): got %d, want 28", sig.V)
 // 	}
 // }
+
+func TestUnitVariantActionMsgpack(t *testing.T) {
+	var buf bytes.Buffer
+	enc := msgpack.NewEncoder(&buf)
+	enc.SetCustomStructTag("json")
+	if err := enc.Encode(
+		unitVariantAction{Type: "CSignerAction", Variant: "jailSelf"},
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	// {"type": "CSignerAction", "jailSelf": None} packed by msgpack-python
+	expected := "82a474797065ad435369676e6572416374696f6ea86a61696c53656c66c0"
+	if got := hex.EncodeToString(buf.Bytes()); got != expected {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+}
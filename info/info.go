@@ -2,11 +2,16 @@ package info
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"slices"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/banky/go-hyperliquid/internal/utils"
+	"github.com/banky/go-hyperliquid/logging"
 	"github.com/banky/go-hyperliquid/rest"
 	"github.com/banky/go-hyperliquid/ws"
 	"github.com/ethereum/go-ethereum/common"
@@ -22,8 +27,32 @@ type Info struct {
 	coinToAsset       map[string]int64
 	nameToCoin        map[string]string
 	assetToSzDecimals map[int64]int64
+	// dexCoinToAsset maps each perp dex to its coins, named without the dex
+	// prefix, so that coins with the same name on different dexes resolve to
+	// their own asset
+	dexCoinToAsset map[string]map[string]int64
+	// spotPairToAsset maps the base and quote token names of each spot pair
+	// to its asset
+	spotPairToAsset map[[2]string]int64
+
+	// metaTTL is how long fetched Meta and SpotMeta are reused. Zero disables
+	// caching.
+	metaTTL       time.Duration
+	perpDexs      []string
+	metaCache     map[string]cachedValue[Meta]
+	spotMetaCache *cachedValue[SpotMeta]
 }
 
+// cachedValue is a metadata response and the time it was fetched
+type cachedValue[T any] struct {
+	value     T
+	fetchedAt time.Time
+}
+
+// DefaultMetaTTL is how long Meta and SpotMeta responses are cached unless
+// Config.MetaTTL says otherwise
+const DefaultMetaTTL = time.Hour
+
 // Config for initializing the Info client
 type Config struct {
 	BaseURL  string
@@ -31,22 +60,58 @@ type Config struct {
 	SkipWS   bool
 	Meta     *Meta     // Optional: if nil, will be fetched from API
 	SpotMeta *SpotMeta // Optional: if nil, will be fetched from API
-	PerpDexs []string  // Optional: if empty, defaults to [""] (main DEX)
+	// PerpDexs lists the perp dexes to load coins for, in any order. If
+	// empty, only the main dex ("") is loaded. Builder-deployed dexes are
+	// looked up with the perpDexs query, which fixes their asset ids.
+	PerpDexs []string
+	// Retry is the retry policy for info queries. If unset, requests are not
+	// retried. rest.DefaultRetryPolicy is a sensible choice.
+	Retry rest.RetryPolicy
+	// MetaTTL is how long Meta and SpotMeta responses are cached. If unset,
+	// DefaultMetaTTL is used. A negative value disables caching.
+	MetaTTL time.Duration
+	// Logger receives websocket connection events. If unset, events are
+	// discarded.
+	Logger logging.Logger
+	// Observer is notified of the duration and outcome of every /info query.
+	// It is also passed to the websocket client. If unset, queries are not
+	// observed.
+	Observer logging.Observer
+	// HTTPClient is the client used for REST queries. If unset, a default
+	// client is used.
+	HTTPClient *http.Client
 }
 
 // New creates a new Info client
 func New(cfg Config) (*Info, error) {
 	// Create REST client
 	client := rest.New(rest.Config{
-		BaseUrl: cfg.BaseURL,
-		Timeout: cfg.Timeout,
+		BaseUrl:    cfg.BaseURL,
+		Timeout:    cfg.Timeout,
+		Retry:      cfg.Retry,
+		Observer:   cfg.Observer,
+		HTTPClient: cfg.HTTPClient,
 	})
 
 	// Create WebSocket manager if not skipped
-	var wsManager *ws.Client
+	// Left as a nil interface when skipped, so that the i.ws == nil checks
+	// hold
+	var wsManager ws.ClientInterface
 	if !cfg.SkipWS {
-		wsManager = ws.New(cfg.BaseURL)
-		wsManager.Start(context.Background())
+		c := ws.New(
+			cfg.BaseURL,
+			ws.WithLogger(cfg.Logger),
+			ws.WithObserver(cfg.Observer),
+		)
+		c.Start(context.Background())
+		wsManager = c
+	}
+
+	metaTTL := cfg.MetaTTL
+	if metaTTL == 0 {
+		metaTTL = DefaultMetaTTL
+	} else if metaTTL < 0 {
+		metaTTL = 0
 	}
 
 	info := &Info{
@@ -55,6 +120,7 @@ func New(cfg Config) (*Info, error) {
 		coinToAsset:       make(map[string]int64),
 		nameToCoin:        make(map[string]string),
 		assetToSzDecimals: make(map[int64]int64),
+		metaTTL:           metaTTL,
 	}
 
 	// Initialize metadata and coin/asset mappings
@@ -88,6 +154,15 @@ func (i *Info) initializeMetadata(ctx context.Context, cfg Config) error {
 		perpDexs = []string{""}
 	}
 
+	i.mu.Lock()
+	i.perpDexs = perpDexs
+	i.mu.Unlock()
+
+	offsets, err := i.perpDexOffsets(ctx, perpDexs)
+	if err != nil {
+		return err
+	}
+
 	// Process each perp DEX
 	for _, dex := range perpDexs {
 		var meta *Meta
@@ -106,23 +181,52 @@ func (i *Info) initializeMetadata(ctx context.Context, cfg Config) error {
 				}
 				meta = &fetched
 			}
-			i.setPerpMeta(*meta, 0)
+			i.setPerpMeta(dex, *meta, 0)
 		} else {
-			// Fetch meta for other DEXs (offset calculation would be handled
-			// separately)
 			fetched, err := i.Meta(ctx, dex)
 			if err != nil {
 				return fmt.Errorf("failed to fetch meta for dex %q: %w", dex, err)
 			}
-			// TODO: Calculate correct offset for builder-deployed perp dexs
-			// (110000 + i*10000)
-			i.setPerpMeta(fetched, 0)
+			i.setPerpMeta(dex, fetched, offsets[dex])
 		}
 	}
 
 	return nil
 }
 
+// perpDexOffsets returns the first asset id of each builder-deployed dex in
+// perpDexs. The dex at index n of the perpDexs query, after the main dex at
+// index 0, takes asset ids from 100000 + n*10000. The query is skipped if
+// only the main dex is loaded.
+func (i *Info) perpDexOffsets(
+	ctx context.Context,
+	perpDexs []string,
+) (map[string]int64, error) {
+	offsets := make(map[string]int64)
+	if !slices.ContainsFunc(perpDexs, func(dex string) bool {
+		return dex != ""
+	}) {
+		return offsets, nil
+	}
+
+	listed, err := i.PerpDexs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch perp dexs: %w", err)
+	}
+	for n, dex := range listed {
+		if n > 0 {
+			offsets[dex.Name] = 100000 + int64(n)*10000
+		}
+	}
+
+	for _, dex := range perpDexs {
+		if _, ok := offsets[dex]; dex != "" && !ok {
+			return nil, fmt.Errorf("unknown perp dex %q", dex)
+		}
+	}
+	return offsets, nil
+}
+
 // initializeSpotMetadata processes spot metadata to build coin/asset mappings
 func (i *Info) initializeSpotMetadata(spotMeta *SpotMeta) {
 	if spotMeta == nil {
@@ -132,6 +236,10 @@ func (i *Info) initializeSpotMetadata(spotMeta *SpotMeta) {
 	i.mu.Lock()
 	defer i.mu.Unlock()
 
+	if i.spotPairToAsset == nil {
+		i.spotPairToAsset = make(map[[2]string]int64)
+	}
+
 	// Process spot assets (start at 10000)
 	for _, spot := range spotMeta.Universe {
 		asset := spot.Index + 10000
@@ -157,6 +265,10 @@ func (i *Info) initializeSpotMetadata(spotMeta *SpotMeta) {
 				if _, exists := i.nameToCoin[friendlyName]; !exists {
 					i.nameToCoin[friendlyName] = spot.Name
 				}
+				pair := [2]string{baseInfo.Name, quoteInfo.Name}
+				if _, exists := i.spotPairToAsset[pair]; !exists {
+					i.spotPairToAsset[pair] = asset
+				}
 				i.assetToSzDecimals[asset] = baseInfo.SzDecimals
 			}
 		}
@@ -164,15 +276,22 @@ func (i *Info) initializeSpotMetadata(spotMeta *SpotMeta) {
 }
 
 // setPerpMeta processes perpetual metadata for a specific DEX and asset offset
-func (i *Info) setPerpMeta(meta Meta, offset int64) {
+func (i *Info) setPerpMeta(dex string, meta Meta, offset int64) {
 	i.mu.Lock()
 	defer i.mu.Unlock()
 
+	if i.dexCoinToAsset == nil {
+		i.dexCoinToAsset = make(map[string]map[string]int64)
+	}
+	dexAssets := make(map[string]int64, len(meta.Universe))
+	i.dexCoinToAsset[dex] = dexAssets
+
 	for idx, asset := range meta.Universe {
 		assetID := int64(idx) + offset
 		i.coinToAsset[asset.Name] = assetID
 		i.nameToCoin[asset.Name] = asset.Name
 		i.assetToSzDecimals[assetID] = asset.SzDecimals
+		dexAssets[strings.TrimPrefix(asset.Name, dex+":")] = assetID
 	}
 }
 
@@ -183,6 +302,16 @@ func (i *Info) Close() {
 	}
 }
 
+// IsMainnet reports whether the info client is connected to mainnet
+func (i *Info) IsMainnet() bool {
+	return i.rest.IsMainnet()
+}
+
+// NetworkName returns the name of the network the info client is connected to
+func (i *Info) NetworkName() string {
+	return i.rest.NetworkName()
+}
+
 // ===== Market Data Queries =====
 
 // AllMids retrieves mid-prices for all coins, with fallback to last trade price
@@ -201,8 +330,11 @@ func (i *Info) AllMids(
 		},
 		&result,
 	)
+	if err != nil {
+		return nil, err
+	}
 
-	mappedResult := make(map[string]float64)
+	mappedResult := make(map[string]float64, len(result))
 	for coin, mid := range result {
 		s, err := utils.StringToFloat(mid)
 		if err != nil {
@@ -212,7 +344,7 @@ func (i *Info) AllMids(
 		mappedResult[coin] = s
 	}
 
-	return mappedResult, err
+	return mappedResult, nil
 }
 
 // L2Snapshot retrieves up to 20 levels of the order book for a coin.
@@ -243,7 +375,16 @@ func (i *Info) L2Snapshot(
 }
 
 // Meta retrieves exchange metadata for perpetuals.
+//
+// Responses are cached for the client's meta TTL; use RefreshMeta to reload.
 func (i *Info) Meta(ctx context.Context, dex string) (Meta, error) {
+	i.mu.RLock()
+	cached, ok := i.metaCache[dex]
+	i.mu.RUnlock()
+	if ok && i.isFresh(cached.fetchedAt) {
+		return cached.value, nil
+	}
+
 	var result Meta
 	err := i.rest.Post(
 		ctx,
@@ -254,12 +395,33 @@ func (i *Info) Meta(ctx context.Context, dex string) (Meta, error) {
 		},
 		&result,
 	)
+	if err != nil {
+		return result, err
+	}
 
-	return result, err
+	if i.metaTTL > 0 {
+		i.mu.Lock()
+		if i.metaCache == nil {
+			i.metaCache = make(map[string]cachedValue[Meta])
+		}
+		i.metaCache[dex] = cachedValue[Meta]{result, time.Now()}
+		i.mu.Unlock()
+	}
+
+	return result, nil
 }
 
 // SpotMeta retrieves exchange metadata for spot trading.
+//
+// Responses are cached for the client's meta TTL; use RefreshMeta to reload.
 func (i *Info) SpotMeta(ctx context.Context) (SpotMeta, error) {
+	i.mu.RLock()
+	cached := i.spotMetaCache
+	i.mu.RUnlock()
+	if cached != nil && i.isFresh(cached.fetchedAt) {
+		return cached.value, nil
+	}
+
 	var result SpotMeta
 	err := i.rest.Post(
 		ctx,
@@ -269,29 +431,206 @@ func (i *Info) SpotMeta(ctx context.Context) (SpotMeta, error) {
 		},
 		&result,
 	)
+	if err != nil {
+		return result, err
+	}
+
+	if i.metaTTL > 0 {
+		i.mu.Lock()
+		i.spotMetaCache = &cachedValue[SpotMeta]{result, time.Now()}
+		i.mu.Unlock()
+	}
+
+	return result, nil
+}
+
+// PerpDexs retrieves the perp dexes in the order that fixes their asset ids.
+// The first entry is the main dex and has an empty name.
+func (i *Info) PerpDexs(ctx context.Context) ([]PerpDex, error) {
+	var result []PerpDex
+	err := i.rest.Post(
+		ctx,
+		"/info",
+		map[string]any{"type": "perpDexs"},
+		&result,
+	)
+
+	return result, err
+}
+
+// RefreshMeta drops the cached Meta and SpotMeta, fetches them again and
+// updates the coin and asset mappings, for example after a new listing.
+func (i *Info) RefreshMeta(ctx context.Context) error {
+	i.mu.Lock()
+	i.metaCache = nil
+	i.spotMetaCache = nil
+	i.mu.Unlock()
+
+	return i.LoadCoinMappingFromMeta(ctx)
+}
+
+// LoadCoinMappingFromMeta fetches Meta for each perp dex and SpotMeta, and
+// adds every coin to the coin and asset mappings. Spot pairs resolve from
+// both their "@{index}" wire name and their "BASE/QUOTE" name. Cached
+// metadata within the meta TTL is reused; use RefreshMeta to bypass it.
+//
+// New does this already unless metadata is passed in Config, so this is
+// mostly useful for an Info built around static metadata.
+func (i *Info) LoadCoinMappingFromMeta(ctx context.Context) error {
+	i.mu.Lock()
+	if i.coinToAsset == nil {
+		i.coinToAsset = make(map[string]int64)
+		i.nameToCoin = make(map[string]string)
+		i.assetToSzDecimals = make(map[int64]int64)
+	}
+	perpDexs := i.perpDexs
+	i.mu.Unlock()
+
+	return i.initializeMetadata(ctx, Config{PerpDexs: perpDexs})
+}
+
+// isFresh reports whether metadata fetched at fetchedAt is within the TTL
+func (i *Info) isFresh(fetchedAt time.Time) bool {
+	return i.metaTTL > 0 && time.Since(fetchedAt) < i.metaTTL
+}
+
+// MetaAndAssetCtxs retrieves exchange metadata for perpetuals along with the
+// market context of each asset. Contexts are in the same order as the
+// universe.
+func (i *Info) MetaAndAssetCtxs(
+	ctx context.Context,
+) (Meta, []PerpAssetCtx, error) {
+	var result []json.RawMessage
+	err := i.rest.Post(
+		ctx,
+		"/info",
+		map[string]any{
+			"type": "metaAndAssetCtxs",
+		},
+		&result,
+	)
+	if err != nil {
+		return Meta{}, nil, err
+	}
+
+	if len(result) != 2 {
+		return Meta{}, nil, fmt.Errorf(
+			"expected meta and asset contexts, got %d elements",
+			len(result),
+		)
+	}
+
+	var meta Meta
+	if err := json.Unmarshal(result[0], &meta); err != nil {
+		return Meta{}, nil, fmt.Errorf("failed to decode meta: %w", err)
+	}
+
+	var assetCtxs []PerpAssetCtx
+	if err := json.Unmarshal(result[1], &assetCtxs); err != nil {
+		return Meta{}, nil, fmt.Errorf(
+			"failed to decode asset contexts: %w",
+			err,
+		)
+	}
+
+	return meta, assetCtxs, nil
+}
+
+// SpotMetaAndAssetCtxs retrieves exchange metadata for spot trading along with
+// the market context of each spot pair. Contexts are in the same order as the
+// universe.
+func (i *Info) SpotMetaAndAssetCtxs(
+	ctx context.Context,
+) (SpotMeta, []SpotAssetCtx, error) {
+	var result []json.RawMessage
+	err := i.rest.Post(
+		ctx,
+		"/info",
+		map[string]any{
+			"type": "spotMetaAndAssetCtxs",
+		},
+		&result,
+	)
+	if err != nil {
+		return SpotMeta{}, nil, err
+	}
+
+	if len(result) != 2 {
+		return SpotMeta{}, nil, fmt.Errorf(
+			"expected spot meta and asset contexts, got %d elements",
+			len(result),
+		)
+	}
+
+	var spotMeta SpotMeta
+	if err := json.Unmarshal(result[0], &spotMeta); err != nil {
+		return SpotMeta{}, nil, fmt.Errorf(
+			"failed to decode spot meta: %w",
+			err,
+		)
+	}
+
+	var assetCtxs []SpotAssetCtx
+	if err := json.Unmarshal(result[1], &assetCtxs); err != nil {
+		return SpotMeta{}, nil, fmt.Errorf(
+			"failed to decode asset contexts: %w",
+			err,
+		)
+	}
+
+	return spotMeta, assetCtxs, nil
+}
+
+// PredictedFundings retrieves the predicted funding rates of every perp on
+// Hyperliquid and other venues.
+func (i *Info) PredictedFundings(
+	ctx context.Context,
+) ([]PredictedFunding, error) {
+	var result []PredictedFunding
+	err := i.rest.Post(
+		ctx,
+		"/info",
+		map[string]any{
+			"type": "predictedFundings",
+		},
+		&result,
+	)
 
 	return result, err
 }
 
 // AssetToSzDecimals retrieves the number of decimal places for a given asset.
 func (i *Info) AssetToSzDecimals(asset int64) (int64, bool) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
 	szDecimals, ok := i.assetToSzDecimals[asset]
 	return szDecimals, ok
 }
 
 // CoinToAsset retrieves the asset ID for a given coin.
 func (i *Info) CoinToAsset(coin string) (int64, bool) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
 	assetID, ok := i.coinToAsset[coin]
 	return assetID, ok
 }
 
 // NameToCoin retrieves the coin name for a given asset name.
 func (i *Info) NameToCoin(name string) (string, bool) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
 	coin, ok := i.nameToCoin[name]
 	return coin, ok
 }
 
+// NameToAsset retrieves the asset ID for a given asset name.
 func (i *Info) NameToAsset(name string) (int64, bool) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
 	asset, ok := i.coinToAsset[i.nameToCoin[name]]
 	return asset, ok
 }
@@ -359,6 +698,201 @@ func (i *Info) OpenOrders(
 	return result, err
 }
 
+// OpenOrdersForCoin retrieves a user's open orders for a single coin. The
+// endpoint returns orders for every coin, so the name is resolved to its coin
+// and the result is filtered client-side.
+func (i *Info) OpenOrdersForCoin(
+	ctx context.Context,
+	user common.Address,
+	name string,
+	dex string,
+) ([]OpenOrder, error) {
+	coin := i.getCoinFromName(name)
+	if coin == "" {
+		return nil, fmt.Errorf("unknown coin name: %s", name)
+	}
+
+	orders, err := i.OpenOrders(ctx, user, dex)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]OpenOrder, 0, len(orders))
+	for _, order := range orders {
+		if order.Coin == coin {
+			result = append(result, order)
+		}
+	}
+
+	return result, nil
+}
+
+// FrontendOpenOrders retrieves a user's active orders along with their
+// trigger details and cloids.
+func (i *Info) FrontendOpenOrders(
+	ctx context.Context,
+	user common.Address,
+	dex string,
+) ([]OrderData, error) {
+	var result []OrderData
+	err := i.rest.Post(
+		ctx,
+		"/info",
+		map[string]any{
+			"type": "frontendOpenOrders",
+			"user": user,
+			"dex":  dex,
+		},
+		&result,
+	)
+
+	return result, err
+}
+
+// SubAccounts retrieves the sub-accounts of a master account.
+func (i *Info) SubAccounts(
+	ctx context.Context,
+	user common.Address,
+) ([]SubAccount, error) {
+	var result []SubAccount
+	err := i.rest.Post(
+		ctx,
+		"/info",
+		map[string]any{
+			"type": "subAccounts",
+			"user": user,
+		},
+		&result,
+	)
+
+	return result, err
+}
+
+// VaultDetails retrieves the details and performance of a vault. If user is
+// set, the follower state of that user is included.
+func (i *Info) VaultDetails(
+	ctx context.Context,
+	vaultAddress common.Address,
+	user *common.Address,
+) (VaultDetails, error) {
+	req := map[string]any{
+		"type":         "vaultDetails",
+		"vaultAddress": vaultAddress,
+	}
+	if user != nil {
+		req["user"] = *user
+	}
+
+	var result VaultDetails
+	err := i.rest.Post(
+		ctx,
+		"/info",
+		req,
+		&result,
+	)
+
+	return result, err
+}
+
+// Portfolio retrieves the account value and PnL history of a user over each
+// portfolio period.
+func (i *Info) Portfolio(
+	ctx context.Context,
+	user common.Address,
+) (Portfolio, error) {
+	var result Portfolio
+	err := i.rest.Post(
+		ctx,
+		"/info",
+		map[string]any{
+			"type": "portfolio",
+			"user": user,
+		},
+		&result,
+	)
+
+	return result, err
+}
+
+// Referral retrieves the referral status and rewards of a user.
+func (i *Info) Referral(
+	ctx context.Context,
+	user common.Address,
+) (ReferralState, error) {
+	var result ReferralState
+	err := i.rest.Post(
+		ctx,
+		"/info",
+		map[string]any{
+			"type": "referral",
+			"user": user,
+		},
+		&result,
+	)
+
+	return result, err
+}
+
+// Delegations retrieves the staking delegations of a user.
+func (i *Info) Delegations(
+	ctx context.Context,
+	user common.Address,
+) ([]Delegation, error) {
+	var result []Delegation
+	err := i.rest.Post(
+		ctx,
+		"/info",
+		map[string]any{
+			"type": "delegations",
+			"user": user,
+		},
+		&result,
+	)
+
+	return result, err
+}
+
+// DelegatorSummary retrieves the staking totals of a user.
+func (i *Info) DelegatorSummary(
+	ctx context.Context,
+	user common.Address,
+) (DelegatorSummary, error) {
+	var result DelegatorSummary
+	err := i.rest.Post(
+		ctx,
+		"/info",
+		map[string]any{
+			"type": "delegatorSummary",
+			"user": user,
+		},
+		&result,
+	)
+
+	return result, err
+}
+
+// MaxBuilderFee retrieves the max builder fee a user has approved for a
+// builder, in tenths of a basis point.
+func (i *Info) MaxBuilderFee(
+	ctx context.Context,
+	user common.Address,
+	builder common.Address,
+) (int64, error) {
+	var result int64
+	err := i.rest.Post(
+		ctx,
+		"/info",
+		map[string]any{
+			"type":    "maxBuilderFee",
+			"user":    user,
+			"builder": builder,
+		},
+		&result,
+	)
+
+	return result, err
+}
+
 // UserFills retrieves a user's fills/executed trades.
 func (i *Info) UserFills(
 	ctx context.Context,
@@ -613,6 +1147,41 @@ func (i *Info) SubscribeActiveAssetCtx(
 	return i.ws.SubscribeActiveAssetCtx(ctx, coin, ch)
 }
 
+// SubscribeActiveSpotAssetCtx subscribes to active asset context for a spot
+// pair
+func (i *Info) SubscribeActiveSpotAssetCtx(
+	ctx context.Context,
+	name string,
+	ch chan<- ws.ActiveSpotAssetCtxMessage,
+) (ws.Subscription, error) {
+	if i.ws == nil {
+		return nil, fmt.Errorf("websocket not initialized")
+	}
+	coin := i.getCoinFromName(name)
+	if coin == "" {
+		return nil, fmt.Errorf("unknown coin name: %s", name)
+	}
+	return i.ws.SubscribeActiveSpotAssetCtx(ctx, coin, ch)
+}
+
+// SubscribeActiveAssetData subscribes to the leverage and trade sizing of a
+// user for a coin
+func (i *Info) SubscribeActiveAssetData(
+	ctx context.Context,
+	name string,
+	user string,
+	ch chan<- ws.ActiveAssetDataMessage,
+) (ws.Subscription, error) {
+	if i.ws == nil {
+		return nil, fmt.Errorf("websocket not initialized")
+	}
+	coin := i.getCoinFromName(name)
+	if coin == "" {
+		return nil, fmt.Errorf("unknown coin name: %s", name)
+	}
+	return i.ws.SubscribeActiveAssetData(ctx, coin, user, ch)
+}
+
 // SubscribeUserEvents subscribes to user events
 func (i *Info) SubscribeUserEvents(
 	ctx context.Context,
@@ -649,6 +1218,139 @@ func (i *Info) SubscribeOrderUpdates(
 	return i.ws.SubscribeOrderUpdates(ctx, user, ch)
 }
 
+// SubscribeUserFundings subscribes to user fundings
+func (i *Info) SubscribeUserFundings(
+	ctx context.Context,
+	user string,
+	ch chan<- ws.UserFundingsMessage,
+) (ws.Subscription, error) {
+	if i.ws == nil {
+		return nil, fmt.Errorf("websocket not initialized")
+	}
+	return i.ws.SubscribeUserFundings(ctx, user, ch)
+}
+
+// SubscribeWebData2 subscribes to the aggregated account state of a user
+func (i *Info) SubscribeWebData2(
+	ctx context.Context,
+	user string,
+	ch chan<- ws.WebData2Message,
+) (ws.Subscription, error) {
+	if i.ws == nil {
+		return nil, fmt.Errorf("websocket not initialized")
+	}
+	return i.ws.SubscribeWebData2(ctx, user, ch)
+}
+
+// SubscribeNotification subscribes to notifications for a user, such as
+// liquidation warnings
+func (i *Info) SubscribeNotification(
+	ctx context.Context,
+	user common.Address,
+	ch chan<- ws.NotificationMessage,
+) (ws.Subscription, error) {
+	if i.ws == nil {
+		return nil, fmt.Errorf("websocket not initialized")
+	}
+	return i.ws.SubscribeNotification(ctx, user, ch)
+}
+
+// ManagedMessage is delivered by a managed subscription. It carries either a
+// feed message in Data, or Reset set to true and no data. A reset marks a
+// websocket reconnect: state built from earlier messages may have missed
+// updates and should be discarded, since the server resends its snapshot
+// after the subscription is replayed.
+type ManagedMessage[T any] struct {
+	Reset bool
+	Data  T
+}
+
+// SubscribeManaged runs subscribe, typically one of the Info Subscribe
+// methods, and forwards its messages to ch, sending a reset message after
+// every websocket reconnect. The subscription itself survives reconnects, so
+// the consumer never needs to subscribe again. Unsubscribe stops both the
+// forwarding and the underlying subscription.
+//
+//	sub, err := info.SubscribeManaged(ctx, i, i.SubscribeAllMids, ch)
+func SubscribeManaged[T any](
+	ctx context.Context,
+	i *Info,
+	subscribe func(context.Context, chan<- T) (ws.Subscription, error),
+	ch chan<- ManagedMessage[T],
+) (ws.Subscription, error) {
+	if i.ws == nil {
+		return nil, fmt.Errorf("websocket not initialized")
+	}
+
+	// Taken before subscribing so no reconnect is missed
+	reconnected := i.ws.Reconnected()
+
+	in := make(chan T, 1)
+	sub, err := subscribe(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &managedSubscription{
+		Subscription: sub,
+		done:         make(chan struct{}),
+	}
+
+	send := func(msg ManagedMessage[T]) bool {
+		select {
+		case ch <- msg:
+			return true
+		case <-m.done:
+			return false
+		}
+	}
+	// reset reports a reconnect, if one happened, and waits for the next
+	reset := func() bool {
+		select {
+		case <-reconnected:
+			reconnected = i.ws.Reconnected()
+			return send(ManagedMessage[T]{Reset: true})
+		default:
+			return true
+		}
+	}
+
+	go func() {
+		for {
+			select {
+			case <-m.done:
+				return
+			case <-reconnected:
+				if !reset() {
+					return
+				}
+			case msg := <-in:
+				// A reconnect before msg arrived is reported first
+				if !reset() || !send(ManagedMessage[T]{Data: msg}) {
+					return
+				}
+			}
+		}
+	}()
+
+	return m, nil
+}
+
+// managedSubscription stops the forwarding goroutine of a managed
+// subscription along with the subscription itself
+type managedSubscription struct {
+	ws.Subscription
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+func (m *managedSubscription) Unsubscribe() {
+	m.stopOnce.Do(func() {
+		close(m.done)
+		m.Subscription.Unsubscribe()
+	})
+}
+
 // ===== Coin/Asset Management =====
 
 // getCoinFromName retrieves the actual coin name from a user-friendly name.
@@ -684,6 +1386,32 @@ func (i *Info) GetAsset(name string) (int64, bool) {
 	return asset, ok
 }
 
+// GetAssetForDex retrieves the asset ID for a coin on the given perp dex. The
+// name may carry the "dex:" prefix or not. For the main dex ("") this is the
+// same as GetAsset, so spot names resolve too.
+func (i *Info) GetAssetForDex(name string, dex string) (int64, bool) {
+	if dex == "" {
+		return i.GetAsset(name)
+	}
+
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	asset, ok := i.dexCoinToAsset[dex][strings.TrimPrefix(name, dex+":")]
+	return asset, ok
+}
+
+// SpotAsset retrieves the asset ID of the spot pair trading base against
+// quote, such as "PURR" and "USDC". The pair is found by its token names in
+// the spot metadata rather than by its coin name.
+func (i *Info) SpotAsset(base string, quote string) (int64, bool) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	asset, ok := i.spotPairToAsset[[2]string{base, quote}]
+	return asset, ok
+}
+
 // ===== Order Query Methods =====
 
 // QueryOrderByOid retrieves order status by order ID.
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"strconv"
 	"testing"
 	"time"
 
@@ -168,6 +169,28 @@ func loadCassettes(
 			client.registerCassette("userFillsByTime", testName)
 		case "test_get_info":
 			client.registerCassette("meta", testName)
+		case "test_get_spot_meta":
+			client.registerCassette("spotMeta", testName)
+		case "test_meta_and_asset_ctxs":
+			client.registerCassette("metaAndAssetCtxs", testName)
+		case "test_spot_meta_and_asset_ctxs":
+			client.registerCassette("spotMetaAndAssetCtxs", testName)
+		case "test_predicted_fundings":
+			client.registerCassette("predictedFundings", testName)
+		case "test_get_frontend_open_orders":
+			client.registerCassette("frontendOpenOrders", testName)
+		case "test_sub_accounts":
+			client.registerCassette("subAccounts", testName)
+		case "test_vault_details":
+			client.registerCassette("vaultDetails", testName)
+		case "test_portfolio":
+			client.registerCassette("portfolio", testName)
+		case "test_referral":
+			client.registerCassette("referral", testName)
+		case "test_delegations":
+			client.registerCassette("delegations", testName)
+		case "test_delegator_summary":
+			client.registerCassette("delegatorSummary", testName)
 		case "test_get_funding_history[None]":
 			client.registerCassette("fundingHistory", testName)
 		case "test_get_l2_snapshot":
@@ -221,6 +244,28 @@ func (s *InfoCassetteSuite) TestAllMids(assert, require *td.T) {
 	require.ContainsKey(mids, "MATIC")
 }
 
+func (s *InfoCassetteSuite) TestAllMidsMatchesRawStrings(
+	assert, require *td.T,
+) {
+	client := loadCassettes(require.TB, "test_get_all_mids")
+	info := &Info{rest: client}
+
+	data, err := loadCassetteFile("test_get_all_mids")
+	require.CmpNoError(err)
+	var raw map[string]string
+	require.CmpNoError(json.Unmarshal(data, &raw))
+
+	mids, err := info.AllMids(context.Background(), "")
+	require.CmpNoError(err)
+	require.Cmp(len(mids), len(raw))
+
+	for coin, rawMid := range raw {
+		expected, err := strconv.ParseFloat(rawMid, 64)
+		require.CmpNoError(err)
+		assert.Cmp(mids[coin], expected, "mid for %s", coin)
+	}
+}
+
 func (s *InfoCassetteSuite) TestUserState(assert, require *td.T) {
 	client := loadCassettes(require.TB, "test_get_user_state")
 	info := &Info{rest: client}
@@ -238,6 +283,42 @@ func (s *InfoCassetteSuite) TestUserState(assert, require *td.T) {
 	require.Cmp(response.MarginSummary.AccountValue.Raw(), 1182.312496)
 }
 
+func (s *InfoCassetteSuite) TestUserStatePositions(assert, require *td.T) {
+	client := loadCassettes(require.TB, "test_get_user_state")
+	info := &Info{rest: client}
+
+	response, err := info.UserState(
+		context.Background(),
+		common.HexToAddress("0x5e9ee1089755c3435139848e47e6635505d5a13a"),
+		"",
+	)
+	require.CmpNoError(err)
+
+	// A closed position is reported with zero size and is skipped
+	response.AssetPositions = append(response.AssetPositions, AssetPosition{
+		Position: Position{Coin: "DOGE", Szi: 0},
+		Type:     "oneWay",
+	})
+
+	positions := response.Positions()
+	require.Len(positions, 12)
+
+	btc := positions[0]
+	assert.Cmp(btc.Coin, "BTC")
+	assert.Cmp(btc.Size.Raw(), -0.00785)
+	require.NotNil(btc.EntryPx)
+	assert.Cmp(btc.EntryPx.Raw(), 26951.0)
+	assert.Cmp(btc.UnrealizedPnl.Raw(), -0.08007)
+	assert.Cmp(btc.Leverage, Leverage{Type: "cross", Value: 20})
+	require.NotNil(btc.LiquidationPx)
+	assert.Cmp(btc.LiquidationPx.Raw(), 173198.69592357)
+
+	eth := positions[1]
+	assert.Cmp(eth.Coin, "ETH")
+	assert.Cmp(eth.Size.Raw(), 0.1334)
+	assert.Nil(eth.LiquidationPx)
+}
+
 func (s *InfoCassetteSuite) TestOpenOrders(assert, require *td.T) {
 	client := loadCassettes(require.TB, "test_get_open_orders")
 	info := &Info{rest: client}
@@ -465,3 +546,396 @@ func (s *InfoCassetteSuite) TestUserFees(assert, require *td.T) {
 	// Check active staking discount
 	require.NotNil(feeInfo.ActiveStakingDiscount)
 }
+
+func (s *InfoCassetteSuite) TestInitializeMetadataFetchesMeta(
+	assert, require *td.T,
+) {
+	client := loadCassettes(require.TB, "test_get_info", "test_get_spot_meta")
+	info := &Info{
+		rest:              client,
+		coinToAsset:       make(map[string]int64),
+		nameToCoin:        make(map[string]string),
+		assetToSzDecimals: make(map[int64]int64),
+	}
+
+	require.CmpNoError(info.initializeMetadata(context.Background(), Config{}))
+
+	asset, ok := info.GetAsset("BTC")
+	require.True(ok)
+	require.Cmp(asset, int64(0))
+
+	szDecimals, ok := info.AssetToSzDecimals(asset)
+	require.True(ok)
+	require.Cmp(szDecimals, int64(5))
+
+	// Spot pairs are offset by 10000 and resolvable by friendly name
+	asset, ok = info.GetAsset("PURR/USDC")
+	require.True(ok)
+	require.Gte(asset, int64(10000))
+
+	asset, ok = info.GetAsset("HFUN/USDC")
+	require.True(ok)
+	require.Cmp(asset, int64(10001))
+}
+
+func (s *InfoCassetteSuite) TestLoadCoinMappingFromMeta(
+	assert, require *td.T,
+) {
+	client := loadCassettes(require.TB, "test_get_info", "test_get_spot_meta")
+	info := &Info{rest: client}
+
+	require.CmpNoError(info.LoadCoinMappingFromMeta(context.Background()))
+
+	// A friendly spot name resolves to the canonical "@index" coin
+	coin, ok := info.NameToCoin("HFUN/USDC")
+	require.True(ok)
+	require.Cmp(coin, "@1")
+
+	asset, ok := info.CoinToAsset(coin)
+	require.True(ok)
+	require.Cmp(asset, int64(10001))
+
+	coin, ok = info.NameToCoin("ETH")
+	require.True(ok)
+	require.Cmp(coin, "ETH")
+}
+
+func (s *InfoCassetteSuite) TestInitializeMetadataUsesProvidedMeta(
+	assert, require *td.T,
+) {
+	client := loadCassettes(require.TB, "test_get_info", "test_get_spot_meta")
+	meta, err := (&Info{rest: client}).Meta(context.Background(), "")
+	require.CmpNoError(err)
+	spotMeta, err := (&Info{rest: client}).SpotMeta(context.Background())
+	require.CmpNoError(err)
+
+	// Any network call fails the test
+	info := &Info{
+		rest: &mockRestClient{
+			postFunc: func(
+				ctx context.Context,
+				path string,
+				body any,
+				result any,
+			) error {
+				require.Fatalf("unexpected request: %v", body)
+				return nil
+			},
+		},
+		coinToAsset:       make(map[string]int64),
+		nameToCoin:        make(map[string]string),
+		assetToSzDecimals: make(map[int64]int64),
+	}
+
+	err = info.initializeMetadata(context.Background(), Config{
+		Meta:     &meta,
+		SpotMeta: &spotMeta,
+	})
+	require.CmpNoError(err)
+
+	asset, ok := info.GetAsset("BTC")
+	require.True(ok)
+	require.Cmp(asset, int64(0))
+
+	asset, ok = info.GetAsset("PURR/USDC")
+	require.True(ok)
+	require.Cmp(asset, int64(10000))
+
+	asset, ok = info.SpotAsset("PURR", "USDC")
+	require.True(ok)
+	require.Cmp(asset, int64(10000))
+}
+
+func (s *InfoCassetteSuite) TestUserFeesTiers(assert, require *td.T) {
+	client := loadCassettes(require.TB, "test_user_fees")
+	info := &Info{rest: client}
+
+	feeInfo, err := info.UserFees(
+		context.Background(),
+		common.HexToAddress("0xb7b6f3cea3f66bf525f5d8f965f6dbf6d9b017b2"),
+	)
+	require.CmpNoError(err)
+
+	// Tiers are decoded directly into typed values
+	require.Cmp(len(feeInfo.FeeSchedule.Tiers.Vip), 6)
+	require.Cmp(feeInfo.FeeSchedule.Tiers.Vip[0].NtlCutoff.Raw(), 5000000.0)
+	require.Cmp(feeInfo.FeeSchedule.Tiers.Vip[0].Cross.Raw(), 0.0004)
+	require.Cmp(feeInfo.FeeSchedule.Tiers.Mm[0].Add.Raw(), -0.00001)
+	require.Cmp(feeInfo.FeeSchedule.Cross.Raw(), 0.00045)
+}
+
+func (s *InfoCassetteSuite) TestUserFundingHistoryTyped(assert, require *td.T) {
+	client := loadCassettes(
+		require.TB,
+		"test_user_funding_history_with_end_time",
+	)
+	info := &Info{rest: client}
+
+	endTime := time.UnixMilli(1682010233000)
+	response, err := info.UserFundingHistory(
+		context.Background(),
+		common.HexToAddress("0xb7b6f3cea3f66bf525f5d8f965f6dbf6d9b017b2"),
+		time.UnixMilli(1681923833000),
+		&endTime,
+	)
+	require.CmpNoError(err)
+	require.Gt(len(response), 0)
+
+	// Each entry decodes into typed funding deltas
+	require.Cmp(response[0].Delta.Coin, "APE")
+	require.Cmp(response[0].Delta.Type, "funding")
+	require.Cmp(response[0].Delta.NSamples, int64(3))
+	require.Cmp(response[0].Delta.Usdc.Raw(), 0.145796)
+	require.Cmp(response[0].Time, int64(1681948800000))
+}
+
+func (s *InfoCassetteSuite) TestMetaAndAssetCtxs(assert, require *td.T) {
+	client := loadCassettes(require.TB, "test_meta_and_asset_ctxs")
+	info := &Info{rest: client}
+
+	meta, assetCtxs, err := info.MetaAndAssetCtxs(context.Background())
+	require.CmpNoError(err)
+
+	// One context per asset in the universe
+	require.Cmp(len(meta.Universe), 3)
+	require.Cmp(len(assetCtxs), len(meta.Universe))
+
+	require.Cmp(meta.Universe[0].Name, "BTC")
+	require.Cmp(assetCtxs[0].MarkPx.Raw(), 109350.0)
+	require.Cmp(assetCtxs[0].Funding.Raw(), 0.0000125)
+	require.NotNil(assetCtxs[0].ImpactPxs)
+
+	// Delisted assets have no mid or impact prices
+	require.Nil(assetCtxs[2].MidPx)
+	require.Nil(assetCtxs[2].ImpactPxs)
+}
+
+func (s *InfoCassetteSuite) TestSpotMetaAndAssetCtxs(assert, require *td.T) {
+	client := loadCassettes(require.TB, "test_spot_meta_and_asset_ctxs")
+	info := &Info{rest: client}
+
+	spotMeta, assetCtxs, err := info.SpotMetaAndAssetCtxs(context.Background())
+	require.CmpNoError(err)
+	require.Cmp(len(assetCtxs), len(spotMeta.Universe))
+
+	// Each context belongs to the spot pair at the same universe index
+	for idx, spot := range spotMeta.Universe {
+		require.Cmp(spot.Index, int64(idx))
+		require.Cmp(assetCtxs[idx].Coin, spot.Name)
+	}
+
+	require.Cmp(assetCtxs[0].CirculatingSupply.Raw(), 596952537.3658201694)
+	require.Cmp(assetCtxs[0].MarkPx.Raw(), 0.19925)
+	require.Nil(assetCtxs[1].MidPx)
+}
+
+func (s *InfoCassetteSuite) TestPredictedFundings(assert, require *td.T) {
+	client := loadCassettes(require.TB, "test_predicted_fundings")
+	info := &Info{rest: client}
+
+	fundings, err := info.PredictedFundings(context.Background())
+	require.CmpNoError(err)
+	require.Cmp(len(fundings), 2)
+
+	require.Cmp(fundings[0].Coin, "BTC")
+	require.Cmp(len(fundings[0].Venues), 3)
+	require.Cmp(fundings[0].Venues[1], VenueFunding{
+		Venue:                "HlPerp",
+		FundingRate:          0.0000125,
+		NextFundingTime:      1733958000000,
+		FundingIntervalHours: 1,
+	})
+
+	// Venues without a prediction are skipped
+	require.Cmp(fundings[1].Coin, "HYPE")
+	require.Cmp(len(fundings[1].Venues), 1)
+	require.Cmp(fundings[1].Venues[0].Venue, "HlPerp")
+	require.Cmp(fundings[1].Venues[0].FundingRate.Raw(), -0.0000046)
+}
+
+func (s *InfoCassetteSuite) TestFrontendOpenOrders(assert, require *td.T) {
+	client := loadCassettes(require.TB, "test_get_frontend_open_orders")
+	info := &Info{rest: client}
+
+	orders, err := info.FrontendOpenOrders(
+		context.Background(),
+		common.HexToAddress("0x5e9ee1089755c3435139848e47e6635505d5a13a"),
+		"",
+	)
+	require.CmpNoError(err)
+	require.Gt(len(orders), 0)
+
+	// Trigger orders carry their trigger details
+	require.Cmp(orders[0].Coin, "INJ")
+	require.True(orders[0].IsTrigger)
+	require.Cmp(orders[0].TriggerPx.Raw(), 10.004)
+	require.Cmp(orders[0].TriggerCondition, "Price above 10.004")
+	require.Cmp(orders[0].OrderType, "Take Profit Market")
+	require.True(orders[0].ReduceOnly)
+}
+
+func (s *InfoCassetteSuite) TestSubAccounts(assert, require *td.T) {
+	client := loadCassettes(require.TB, "test_sub_accounts")
+	info := &Info{rest: client}
+
+	master := common.HexToAddress("0x8c967e73e7b15087c42a10d344cff4c96d877f1d")
+	subAccounts, err := info.SubAccounts(context.Background(), master)
+	require.CmpNoError(err)
+	require.Cmp(len(subAccounts), 2)
+
+	require.Cmp(subAccounts[0].Name, "market-maker")
+	require.Cmp(
+		subAccounts[0].SubAccountUser,
+		common.HexToAddress("0x035605fc2f24d65300227189025e90a0d947f16c"),
+	)
+	require.Cmp(subAccounts[0].Master, master)
+	require.Cmp(
+		subAccounts[0].ClearinghouseState.MarginSummary.AccountValue.Raw(),
+		29.78001,
+	)
+	require.Cmp(subAccounts[0].SpotState.Balances[0].Coin, "USDC")
+
+	require.Cmp(subAccounts[1].Name, "hedger")
+	require.Cmp(
+		subAccounts[1].SubAccountUser,
+		common.HexToAddress("0x7e8c5ab1e2b3cd4a9f1e0a3f6a2b9c8d7e6f5a4b"),
+	)
+}
+
+func (s *InfoCassetteSuite) TestVaultDetails(assert, require *td.T) {
+	client := loadCassettes(require.TB, "test_vault_details")
+	info := &Info{rest: client}
+
+	user := common.HexToAddress("0x5e9ee1089755c3435139848e47e6635505d5a13a")
+	details, err := info.VaultDetails(
+		context.Background(),
+		common.HexToAddress("0xdfc24b077bc1425ad1dea75bcb6f8158e10df303"),
+		&user,
+	)
+	require.CmpNoError(err)
+
+	require.Cmp(details.Name, "Test Vault")
+	require.Cmp(
+		details.Leader,
+		common.HexToAddress("0x677d831aef5328190852e24f13c46cac05f984e7"),
+	)
+	require.Cmp(details.Apr, 0.0793)
+	require.False(details.IsClosed)
+	require.True(details.AllowDeposits)
+
+	// Portfolio history is keyed by period
+	require.Cmp(len(details.Portfolio["day"].AccountValueHistory), 2)
+	require.Cmp(
+		details.Portfolio["day"].PnlHistory[1],
+		HistoryPoint{Time: 1734398322736, Value: 1.5354},
+	)
+
+	require.NotNil(details.FollowerState)
+	require.Cmp(details.FollowerState.User, user)
+	require.Cmp(details.FollowerState.VaultEquity.Raw(), 1052.3811)
+	require.Cmp(len(details.Followers), 1)
+}
+
+func (s *InfoCassetteSuite) TestPortfolio(assert, require *td.T) {
+	client := loadCassettes(require.TB, "test_portfolio")
+	info := &Info{rest: client}
+
+	portfolio, err := info.Portfolio(
+		context.Background(),
+		common.HexToAddress("0x5e9ee1089755c3435139848e47e6635505d5a13a"),
+	)
+	require.CmpNoError(err)
+
+	// Every period bucket is present
+	for _, period := range []string{
+		PortfolioDay,
+		PortfolioWeek,
+		PortfolioMonth,
+		PortfolioAllTime,
+		PortfolioPerpDay,
+		PortfolioPerpWeek,
+		PortfolioPerpMonth,
+		PortfolioPerpAllTime,
+	} {
+		require.ContainsKey(portfolio, period)
+	}
+
+	day := portfolio[PortfolioDay]
+	require.Cmp(len(day.AccountValueHistory), 13)
+	require.Cmp(len(day.PnlHistory), 13)
+	require.Cmp(day.AccountValueHistory[0], HistoryPoint{
+		Time:  1755772320063,
+		Value: 160794563.9261809886,
+	})
+
+	require.Cmp(len(portfolio[PortfolioAllTime].PnlHistory), 76)
+	require.NotZero(portfolio[PortfolioAllTime].Vlm)
+}
+
+func (s *InfoCassetteSuite) TestReferral(assert, require *td.T) {
+	client := loadCassettes(require.TB, "test_referral")
+	info := &Info{rest: client}
+
+	state, err := info.Referral(
+		context.Background(),
+		common.HexToAddress("0x5e9ee1089755c3435139848e47e6635505d5a13a"),
+	)
+	require.CmpNoError(err)
+
+	require.NotNil(state.ReferredBy)
+	require.Cmp(state.ReferredBy.Code, "TESTNET")
+	require.Cmp(
+		state.ReferredBy.Referrer,
+		common.HexToAddress("0x5ac99df645f3414876c816caa18b2d234024b487"),
+	)
+	require.Cmp(state.CumVlm.Raw(), 149428030.6628420055)
+	require.Cmp(state.UnclaimedRewards.Raw(), 11.047361)
+	require.Cmp(state.ClaimedRewards.Raw(), 22.743781)
+
+	require.Cmp(state.ReferrerState.Stage, "ready")
+	require.NotNil(state.ReferrerState.Data)
+	require.Cmp(state.ReferrerState.Data.Code, "TEST")
+	require.Cmp(len(state.ReferrerState.Data.ReferralStates), 1)
+	require.Cmp(
+		state.ReferrerState.Data.ReferralStates[0].TimeJoined,
+		int64(1679425029416),
+	)
+}
+
+func (s *InfoCassetteSuite) TestDelegations(assert, require *td.T) {
+	client := loadCassettes(require.TB, "test_delegations")
+	info := &Info{rest: client}
+
+	delegations, err := info.Delegations(
+		context.Background(),
+		common.HexToAddress("0x5e9ee1089755c3435139848e47e6635505d5a13a"),
+	)
+	require.CmpNoError(err)
+	require.Cmp(len(delegations), 2)
+
+	require.Cmp(delegations[0], Delegation{
+		Validator: common.HexToAddress(
+			"0xb8f45222a3246a2b0104696a1df26842007c5bc5",
+		),
+		Amount:               99968.1,
+		LockedUntilTimestamp: 1751468638782,
+	})
+}
+
+func (s *InfoCassetteSuite) TestDelegatorSummary(assert, require *td.T) {
+	client := loadCassettes(require.TB, "test_delegator_summary")
+	info := &Info{rest: client}
+
+	summary, err := info.DelegatorSummary(
+		context.Background(),
+		common.HexToAddress("0x5e9ee1089755c3435139848e47e6635505d5a13a"),
+	)
+	require.CmpNoError(err)
+
+	require.Cmp(summary, DelegatorSummary{
+		Delegated:              102468.1,
+		Undelegated:            12.5,
+		TotalPendingWithdrawal: 100.0,
+		NPendingWithdrawals:    1,
+	})
+}
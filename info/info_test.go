@@ -2,13 +2,19 @@ package info
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/banky/go-hyperliquid/internal/utils"
 	"github.com/banky/go-hyperliquid/types"
 	"github.com/banky/go-hyperliquid/ws"
+	"github.com/coder/websocket"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/maxatome/go-testdeep/helpers/tdsuite"
 	"github.com/maxatome/go-testdeep/td"
@@ -50,17 +56,23 @@ func (m *mockRestClient) NetworkName() string {
 
 // Mock WS client for testing
 type mockWsClient struct {
-	startFunc                   func(ctx context.Context) error
-	stopFunc                    func()
-	subscribeAllMidsFunc        func(ctx context.Context, ch chan<- ws.AllMidsMessage) (ws.Subscription, error)
-	subscribeL2BookFunc         func(ctx context.Context, coin string, ch chan<- ws.L2BookMessage) (ws.Subscription, error)
-	subscribeTradesFunc         func(ctx context.Context, coin string, ch chan<- ws.TradesMessage) (ws.Subscription, error)
-	subscribeCandleFunc         func(ctx context.Context, coin string, interval string, ch chan<- ws.CandleMessage) (ws.Subscription, error)
-	subscribeBboFunc            func(ctx context.Context, coin string, ch chan<- ws.BboMessage) (ws.Subscription, error)
-	subscribeActiveAssetCtxFunc func(ctx context.Context, coin string, ch chan<- ws.ActiveAssetCtxMessage) (ws.Subscription, error)
-	subscribeUserEventsFunc     func(ctx context.Context, user common.Address, ch chan<- ws.UserEventsMessage) (ws.Subscription, error)
-	subscribeUserFillsFunc      func(ctx context.Context, user string, ch chan<- ws.UserFillsMessage) (ws.Subscription, error)
-	subscribeOrderUpdatesFunc   func(ctx context.Context, user string, ch chan<- ws.OrderUpdatesMessage) (ws.Subscription, error)
+	startFunc                       func(ctx context.Context) error
+	stopFunc                        func()
+	subscribeAllMidsFunc            func(ctx context.Context, ch chan<- ws.AllMidsMessage) (ws.Subscription, error)
+	subscribeL2BookFunc             func(ctx context.Context, coin string, ch chan<- ws.L2BookMessage) (ws.Subscription, error)
+	subscribeTradesFunc             func(ctx context.Context, coin string, ch chan<- ws.TradesMessage) (ws.Subscription, error)
+	subscribeCandleFunc             func(ctx context.Context, coin string, interval string, ch chan<- ws.CandleMessage) (ws.Subscription, error)
+	subscribeBboFunc                func(ctx context.Context, coin string, ch chan<- ws.BboMessage) (ws.Subscription, error)
+	subscribeActiveAssetCtxFunc     func(ctx context.Context, coin string, ch chan<- ws.ActiveAssetCtxMessage) (ws.Subscription, error)
+	subscribeUserEventsFunc         func(ctx context.Context, user common.Address, ch chan<- ws.UserEventsMessage) (ws.Subscription, error)
+	subscribeUserFillsFunc          func(ctx context.Context, user string, ch chan<- ws.UserFillsMessage) (ws.Subscription, error)
+	subscribeOrderUpdatesFunc       func(ctx context.Context, user string, ch chan<- ws.OrderUpdatesMessage) (ws.Subscription, error)
+	subscribeUserFundingsFunc       func(ctx context.Context, user string, ch chan<- ws.UserFundingsMessage) (ws.Subscription, error)
+	subscribeWebData2Func           func(ctx context.Context, user string, ch chan<- ws.WebData2Message) (ws.Subscription, error)
+	subscribeActiveAssetDataFunc    func(ctx context.Context, coin string, user string, ch chan<- ws.ActiveAssetDataMessage) (ws.Subscription, error)
+	subscribeActiveSpotAssetCtxFunc func(ctx context.Context, coin string, ch chan<- ws.ActiveSpotAssetCtxMessage) (ws.Subscription, error)
+	subscribeNotificationFunc       func(ctx context.Context, user common.Address, ch chan<- ws.NotificationMessage) (ws.Subscription, error)
+	reconnected                     chan struct{}
 }
 
 var _ ws.ClientInterface = (*mockWsClient)(nil)
@@ -78,6 +90,10 @@ func (m *mockWsClient) Close() {
 	}
 }
 
+func (m *mockWsClient) Reconnected() <-chan struct{} {
+	return m.reconnected
+}
+
 func (m *mockWsClient) SubscribeAllMids(
 	ctx context.Context,
 	ch chan<- ws.AllMidsMessage,
@@ -177,6 +193,62 @@ func (m *mockWsClient) SubscribeOrderUpdates(
 	return nil, nil
 }
 
+func (m *mockWsClient) SubscribeUserFundings(
+	ctx context.Context,
+	user string,
+	ch chan<- ws.UserFundingsMessage,
+) (ws.Subscription, error) {
+	if m.subscribeUserFundingsFunc != nil {
+		return m.subscribeUserFundingsFunc(ctx, user, ch)
+	}
+	return nil, nil
+}
+
+func (m *mockWsClient) SubscribeWebData2(
+	ctx context.Context,
+	user string,
+	ch chan<- ws.WebData2Message,
+) (ws.Subscription, error) {
+	if m.subscribeWebData2Func != nil {
+		return m.subscribeWebData2Func(ctx, user, ch)
+	}
+	return nil, nil
+}
+
+func (m *mockWsClient) SubscribeNotification(
+	ctx context.Context,
+	user common.Address,
+	ch chan<- ws.NotificationMessage,
+) (ws.Subscription, error) {
+	if m.subscribeNotificationFunc != nil {
+		return m.subscribeNotificationFunc(ctx, user, ch)
+	}
+	return nil, nil
+}
+
+func (m *mockWsClient) SubscribeActiveSpotAssetCtx(
+	ctx context.Context,
+	coin string,
+	ch chan<- ws.ActiveSpotAssetCtxMessage,
+) (ws.Subscription, error) {
+	if m.subscribeActiveSpotAssetCtxFunc != nil {
+		return m.subscribeActiveSpotAssetCtxFunc(ctx, coin, ch)
+	}
+	return nil, nil
+}
+
+func (m *mockWsClient) SubscribeActiveAssetData(
+	ctx context.Context,
+	coin string,
+	user string,
+	ch chan<- ws.ActiveAssetDataMessage,
+) (ws.Subscription, error) {
+	if m.subscribeActiveAssetDataFunc != nil {
+		return m.subscribeActiveAssetDataFunc(ctx, coin, user, ch)
+	}
+	return nil, nil
+}
+
 // ===== REST API Tests =====
 
 func (s *InfoSuite) TestAllMidsSuccess(assert, require *td.T) {
@@ -387,6 +459,34 @@ func (s *InfoSuite) TestUserStateSuccess(assert, require *td.T) {
 	require.Cmp(state.Withdrawable.Raw(), 50000.00)
 }
 
+func (s *InfoSuite) TestSpotUserStateTyped(assert, require *td.T) {
+	raw := `{"balances":[` +
+		`{"coin":"USDC","token":0,"total":"14.625485","hold":"0.0","entryNtl":"0.0"},` +
+		`{"coin":"PURR","token":1,"total":"2000","hold":"0","entryNtl":"1234.56"}]}`
+
+	info := &Info{
+		rest: &mockRestClient{
+			postFunc: func(ctx context.Context, path string, body any, result any) error {
+				req := body.(map[string]any)
+				require.Cmp(req["type"], "spotClearinghouseState")
+				return json.Unmarshal([]byte(raw), result)
+			},
+		},
+	}
+
+	state, err := info.SpotUserState(
+		context.Background(),
+		common.HexToAddress("0x123"),
+	)
+	require.CmpNoError(err)
+
+	require.Cmp(len(state.Balances), 2)
+	require.Cmp(state.Balances[0].Coin, "USDC")
+	require.Cmp(state.Balances[0].Total.Raw(), 14.625485)
+	require.Cmp(state.Balances[1].Token, int64(1))
+	require.Cmp(state.Balances[1].EntryNtl.Raw(), 1234.56)
+}
+
 func (s *InfoSuite) TestOpenOrdersSuccess(assert, require *td.T) {
 	expectedOrders := []OpenOrder{
 		{
@@ -427,6 +527,149 @@ func (s *InfoSuite) TestOpenOrdersSuccess(assert, require *td.T) {
 	require.Cmp(len(orders), len(expectedOrders))
 }
 
+func (s *InfoSuite) TestOpenOrdersForCoin(assert, require *td.T) {
+	openOrders := []OpenOrder{
+		{Coin: "BTC", LimitPx: 45000, Oid: 1, Side: "A", Sz: 1},
+		{Coin: "@107", LimitPx: 20, Oid: 2, Side: "B", Sz: 5},
+		{Coin: "ETH", LimitPx: 3000, Oid: 3, Side: "B", Sz: 10},
+		{Coin: "@107", LimitPx: 21, Oid: 4, Side: "A", Sz: 5},
+	}
+
+	info := &Info{
+		rest: &mockRestClient{
+			postFunc: func(ctx context.Context, path string, body any, result any) error {
+				req := body.(map[string]any)
+				require.Cmp(req["type"], "openOrders")
+				*result.(*[]OpenOrder) = openOrders
+				return nil
+			},
+		},
+		nameToCoin: map[string]string{
+			"BTC":       "BTC",
+			"ETH":       "ETH",
+			"HYPE/USDC": "@107",
+		},
+	}
+
+	orders, err := info.OpenOrdersForCoin(
+		context.Background(),
+		common.HexToAddress("0x123"),
+		"HYPE/USDC",
+		"",
+	)
+	require.CmpNoError(err)
+	require.Cmp(len(orders), 2)
+	require.Cmp(orders[0].Oid, int64(2))
+	require.Cmp(orders[1].Oid, int64(4))
+
+	orders, err = info.OpenOrdersForCoin(
+		context.Background(),
+		common.HexToAddress("0x123"),
+		"ETH",
+		"",
+	)
+	require.CmpNoError(err)
+	require.Cmp(len(orders), 1)
+	require.Cmp(orders[0].Oid, int64(3))
+}
+
+func (s *InfoSuite) TestMaxBuilderFee(assert, require *td.T) {
+	user := common.HexToAddress("0x123")
+	builder := common.HexToAddress("0x456")
+
+	info := &Info{
+		rest: &mockRestClient{
+			postFunc: func(ctx context.Context, path string, body any, result any) error {
+				req := body.(map[string]any)
+				require.Cmp(req["type"], "maxBuilderFee")
+				require.Cmp(req["user"], user)
+				require.Cmp(req["builder"], builder)
+				return json.Unmarshal([]byte("10"), result)
+			},
+		},
+	}
+
+	fee, err := info.MaxBuilderFee(context.Background(), user, builder)
+	require.CmpNoError(err)
+	require.Cmp(fee, int64(10))
+}
+
+func (s *InfoSuite) TestMetaCachedWithinTTL(assert, require *td.T) {
+	calls := map[string]int{}
+	info := &Info{
+		rest: &mockRestClient{
+			postFunc: func(ctx context.Context, path string, body any, result any) error {
+				req := body.(map[string]any)
+				reqType := req["type"].(string)
+				calls[reqType]++
+				switch reqType {
+				case "meta":
+					return json.Unmarshal(
+						[]byte(`{"universe":[{"name":"BTC","szDecimals":5}]}`),
+						result,
+					)
+				case "spotMeta":
+					return json.Unmarshal(
+						[]byte(`{"universe":[],"tokens":[]}`),
+						result,
+					)
+				}
+				return fmt.Errorf("unexpected request: %v", req)
+			},
+		},
+		coinToAsset:       make(map[string]int64),
+		nameToCoin:        make(map[string]string),
+		assetToSzDecimals: make(map[int64]int64),
+		metaTTL:           time.Hour,
+	}
+	ctx := context.Background()
+
+	for range 3 {
+		meta, err := info.Meta(ctx, "")
+		require.CmpNoError(err)
+		require.Cmp(meta.Universe[0].Name, "BTC")
+
+		_, err = info.SpotMeta(ctx)
+		require.CmpNoError(err)
+	}
+	require.Cmp(calls, map[string]int{"meta": 1, "spotMeta": 1})
+
+	// Each dex is cached separately
+	_, err := info.Meta(ctx, "test")
+	require.CmpNoError(err)
+	require.Cmp(calls["meta"], 2)
+
+	// RefreshMeta reloads everything and rebuilds the mappings
+	info.perpDexs = []string{""}
+	require.CmpNoError(info.RefreshMeta(ctx))
+	require.Cmp(calls, map[string]int{"meta": 3, "spotMeta": 2})
+	asset, ok := info.GetAsset("BTC")
+	require.True(ok)
+	require.Cmp(asset, int64(0))
+
+	_, err = info.Meta(ctx, "")
+	require.CmpNoError(err)
+	require.Cmp(calls["meta"], 3)
+}
+
+func (s *InfoSuite) TestMetaNotCachedWithoutTTL(assert, require *td.T) {
+	calls := 0
+	info := &Info{
+		rest: &mockRestClient{
+			postFunc: func(ctx context.Context, path string, body any, result any) error {
+				calls++
+				return json.Unmarshal([]byte(`{"universe":[]}`), result)
+			},
+		},
+	}
+
+	for range 2 {
+		_, err := info.Meta(context.Background(), "")
+		require.CmpNoError(err)
+	}
+	require.Cmp(calls, 2)
+}
+
 func (s *InfoSuite) TestUserFillsSuccess(assert, require *td.T) {
 	expectedFills := []Fill{
 		{
@@ -596,6 +839,111 @@ func (s *InfoSuite) TestSubscribeAllMidsSuccess(assert, require *td.T) {
 	require.NotNil(sub)
 }
 
+func (s *InfoSuite) TestSubscribeManagedSurvivesReconnect(
+	assert, require *td.T,
+) {
+	t := require.TB
+
+	// The first connection sends one update and is then dropped. Every later
+	// connection answers the replayed subscribe with another update.
+	var connCount atomic.Int32
+	drop := make(chan struct{})
+	subscribes := make(chan struct{}, 4)
+	server := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			conn, err := websocket.Accept(w, r, nil)
+			if err != nil {
+				t.Logf("websocket accept error: %v", err)
+				return
+			}
+			defer conn.CloseNow()
+			n := connCount.Add(1)
+
+			ctx, cancel := context.WithTimeout(
+				context.Background(),
+				5*time.Second,
+			)
+			defer cancel()
+
+			if _, _, err := conn.Read(ctx); err != nil {
+				return
+			}
+			subscribes <- struct{}{}
+
+			mid := "50000"
+			if n > 1 {
+				mid = "51000"
+			}
+			_ = conn.Write(ctx, websocket.MessageText, []byte(
+				`{"channel":"allMids","data":{"mids":{"BTC":"`+mid+`"}}}`,
+			))
+
+			if n == 1 {
+				select {
+				case <-drop:
+				case <-ctx.Done():
+				}
+				conn.Close(websocket.StatusGoingAway, "server restart")
+				return
+			}
+			_, _, _ = conn.Read(ctx)
+		}),
+	)
+	defer server.Close()
+
+	client := ws.New(
+		server.URL,
+		ws.WithMaxReconnectBackoff(50*time.Millisecond),
+	)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.CmpNoError(client.Start(ctx))
+	defer client.Close()
+
+	info := &Info{ws: client}
+	ch := make(chan ManagedMessage[ws.AllMidsMessage], 1)
+	sub, err := SubscribeManaged(ctx, info, info.SubscribeAllMids, ch)
+	require.CmpNoError(err)
+	defer sub.Unsubscribe()
+
+	receive := func() ManagedMessage[ws.AllMidsMessage] {
+		select {
+		case msg := <-ch:
+			return msg
+		case <-time.After(2 * time.Second):
+			require.True(false, "timeout waiting for message")
+			return ManagedMessage[ws.AllMidsMessage]{}
+		}
+	}
+
+	msg := receive()
+	assert.False(msg.Reset)
+	assert.Cmp(msg.Data.Mids, map[string]string{"BTC": "50000"})
+
+	close(drop)
+
+	assert.Cmp(receive(), ManagedMessage[ws.AllMidsMessage]{Reset: true})
+	msg = receive()
+	assert.False(msg.Reset)
+	assert.Cmp(msg.Data.Mids, map[string]string{"BTC": "51000"})
+
+	// The replayed subscribe came from the ws client, not the consumer
+	assert.Cmp(len(subscribes), 2)
+}
+
+func (s *InfoSuite) TestSubscribeManagedNoWS(assert, require *td.T) {
+	info := &Info{}
+
+	ch := make(chan ManagedMessage[ws.AllMidsMessage])
+	_, err := SubscribeManaged(
+		context.Background(),
+		info,
+		info.SubscribeAllMids,
+		ch,
+	)
+	require.CmpError(err)
+}
+
 func (s *InfoSuite) TestSubscribeL2BookSuccess(assert, require *td.T) {
 	mockWS := &mockWsClient{
 		subscribeL2BookFunc: func(ctx context.Context, coin string, ch chan<- ws.L2BookMessage) (ws.Subscription, error) {
@@ -738,6 +1086,106 @@ func (s *InfoSuite) TestSubscribeOrderUpdatesSuccess(assert, require *td.T) {
 	require.NotNil(sub)
 }
 
+func (s *InfoSuite) TestSubscribeUserFundingsSuccess(assert, require *td.T) {
+	mockWS := &mockWsClient{
+		subscribeUserFundingsFunc: func(ctx context.Context, user string, ch chan<- ws.UserFundingsMessage) (ws.Subscription, error) {
+			require.Cmp(user, "0x789")
+			return &mockSubscription{}, nil
+		},
+	}
+
+	info := &Info{ws: mockWS}
+
+	ch := make(chan ws.UserFundingsMessage)
+	sub, err := info.SubscribeUserFundings(context.Background(), "0x789", ch)
+	require.CmpNoError(err)
+	require.NotNil(sub)
+}
+
+func (s *InfoSuite) TestSubscribeWebData2Success(assert, require *td.T) {
+	mockWS := &mockWsClient{
+		subscribeWebData2Func: func(ctx context.Context, user string, ch chan<- ws.WebData2Message) (ws.Subscription, error) {
+			require.Cmp(user, "0x789")
+			return &mockSubscription{}, nil
+		},
+	}
+
+	info := &Info{ws: mockWS}
+
+	ch := make(chan ws.WebData2Message)
+	sub, err := info.SubscribeWebData2(context.Background(), "0x789", ch)
+	require.CmpNoError(err)
+	require.NotNil(sub)
+}
+
+func (s *InfoSuite) TestSubscribeNotificationSuccess(assert, require *td.T) {
+	mockWS := &mockWsClient{
+		subscribeNotificationFunc: func(ctx context.Context, user common.Address, ch chan<- ws.NotificationMessage) (ws.Subscription, error) {
+			require.Cmp(user, common.HexToAddress("0x123"))
+			return &mockSubscription{}, nil
+		},
+	}
+
+	info := &Info{ws: mockWS}
+
+	ch := make(chan ws.NotificationMessage)
+	sub, err := info.SubscribeNotification(
+		context.Background(),
+		common.HexToAddress("0x123"),
+		ch,
+	)
+	require.CmpNoError(err)
+	require.NotNil(sub)
+}
+
+func (s *InfoSuite) TestSubscribeActiveAssetDataSuccess(assert, require *td.T) {
+	mockWS := &mockWsClient{
+		subscribeActiveAssetDataFunc: func(ctx context.Context, coin string, user string, ch chan<- ws.ActiveAssetDataMessage) (ws.Subscription, error) {
+			require.Cmp(coin, "@107")
+			require.Cmp(user, "0x789")
+			return &mockSubscription{}, nil
+		},
+	}
+
+	info := &Info{
+		ws:         mockWS,
+		nameToCoin: map[string]string{"HYPE/USDC": "@107"},
+	}
+
+	ch := make(chan ws.ActiveAssetDataMessage)
+	sub, err := info.SubscribeActiveAssetData(
+		context.Background(),
+		"HYPE/USDC",
+		"0x789",
+		ch,
+	)
+	require.CmpNoError(err)
+	require.NotNil(sub)
+}
+
+func (s *InfoSuite) TestSubscribeActiveSpotAssetCtxSuccess(assert, require *td.T) {
+	mockWS := &mockWsClient{
+		subscribeActiveSpotAssetCtxFunc: func(ctx context.Context, coin string, ch chan<- ws.ActiveSpotAssetCtxMessage) (ws.Subscription, error) {
+			require.Cmp(coin, "@107")
+			return &mockSubscription{}, nil
+		},
+	}
+
+	info := &Info{
+		ws:         mockWS,
+		nameToCoin: map[string]string{"HYPE/USDC": "@107"},
+	}
+
+	ch := make(chan ws.ActiveSpotAssetCtxMessage)
+	sub, err := info.SubscribeActiveSpotAssetCtx(
+		context.Background(),
+		"HYPE/USDC",
+		ch,
+	)
+	require.CmpNoError(err)
+	require.NotNil(sub)
+}
+
 // ===== Coin/Asset Management Tests =====
 
 func (s *InfoSuite) TestSetCoinMapping(assert, require *td.T) {
@@ -797,6 +1245,215 @@ func (s *InfoSuite) TestGetAssetNotFound(assert, require *td.T) {
 	require.False(ok, "expected asset not to be found")
 }
 
+func (s *InfoSuite) TestGetAssetForDex(assert, require *td.T) {
+	metas := map[string]string{
+		"":    `{"universe":[{"name":"BTC","szDecimals":5}]}`,
+		"abc": `{"universe":[{"name":"abc:FOO","szDecimals":2}]}`,
+		"xyz": `{"universe":[{"name":"xyz:BAR","szDecimals":1},` +
+			`{"name":"xyz:FOO","szDecimals":3}]}`,
+	}
+	newInfo := func() *Info {
+		return &Info{
+			rest: &mockRestClient{
+				postFunc: func(ctx context.Context, path string, body any, result any) error {
+					req := body.(map[string]any)
+					switch req["type"] {
+					case "spotMeta":
+						return json.Unmarshal(
+							[]byte(`{"universe":[],"tokens":[]}`),
+							result,
+						)
+					case "perpDexs":
+						return json.Unmarshal(
+							[]byte(`[null,{"name":"abc"},{"name":"other"},`+
+								`{"name":"xyz"}]`),
+							result,
+						)
+					}
+					meta := metas[req["dex"].(string)]
+					return json.Unmarshal([]byte(meta), result)
+				},
+			},
+			coinToAsset:       make(map[string]int64),
+			nameToCoin:        make(map[string]string),
+			assetToSzDecimals: make(map[int64]int64),
+		}
+	}
+
+	// Asset ids follow the perpDexs order, not the order of Config.PerpDexs,
+	// and dexes the caller skips still take up their range
+	info := newInfo()
+	require.CmpNoError(info.initializeMetadata(
+		context.Background(),
+		Config{PerpDexs: []string{"", "xyz", "abc"}},
+	))
+
+	tests := []struct {
+		name  string
+		dex   string
+		asset int64
+	}{
+		{"BTC", "", 0},
+		{"abc:FOO", "abc", 110000},
+		{"FOO", "abc", 110000},
+		{"xyz:FOO", "xyz", 130001},
+		{"FOO", "xyz", 130001},
+		{"xyz:BAR", "xyz", 130000},
+	}
+	for _, tt := range tests {
+		asset, ok := info.GetAssetForDex(tt.name, tt.dex)
+		assert.True(ok, "%s on %q", tt.name, tt.dex)
+		assert.Cmp(asset, tt.asset, "%s on %q", tt.name, tt.dex)
+	}
+
+	_, ok := info.GetAssetForDex("BAR", "abc")
+	assert.False(ok)
+	_, ok = info.GetAssetForDex("FOO", "")
+	assert.False(ok)
+
+	szDecimals, ok := info.AssetToSzDecimals(130001)
+	assert.True(ok)
+	assert.Cmp(szDecimals, int64(3))
+
+	// A dex the API does not list is an error
+	err := newInfo().initializeMetadata(
+		context.Background(),
+		Config{PerpDexs: []string{"", "missing"}},
+	)
+	require.CmpError(err)
+	assert.Contains(err.Error(), `unknown perp dex "missing"`)
+}
+
+func (s *InfoSuite) TestSpotPairNameResolution(assert, require *td.T) {
+	info := &Info{
+		coinToAsset:       make(map[string]int64),
+		nameToCoin:        make(map[string]string),
+		assetToSzDecimals: make(map[int64]int64),
+	}
+
+	info.initializeSpotMetadata(&SpotMeta{
+		Universe: []SpotAssetInfo{
+			{Name: "PURR/USDC", Tokens: [2]int64{1, 0}, Index: 0},
+			{Name: "@7", Tokens: [2]int64{2, 0}, Index: 7},
+		},
+		Tokens: []SpotTokenInfo{
+			{Name: "USDC", SzDecimals: 8, Index: 0},
+			{Name: "PURR", SzDecimals: 0, Index: 1},
+			{Name: "FOO", SzDecimals: 2, Index: 2},
+		},
+	})
+
+	// Pairs are addressed as "@index" on the wire but resolve by BASE/QUOTE
+	coin, ok := info.NameToCoin("FOO/USDC")
+	require.True(ok)
+	require.Cmp(coin, "@7")
+
+	asset, ok := info.GetAsset("FOO/USDC")
+	require.True(ok)
+	require.Cmp(asset, int64(10007))
+
+	szDecimals, ok := info.AssetToSzDecimals(asset)
+	require.True(ok)
+	require.Cmp(szDecimals, int64(2))
+
+	// The wire name and canonical pair names resolve to themselves
+	coin, ok = info.NameToCoin("@7")
+	require.True(ok)
+	require.Cmp(coin, "@7")
+
+	coin, ok = info.NameToCoin("PURR/USDC")
+	require.True(ok)
+	require.Cmp(coin, "PURR/USDC")
+}
+
+func (s *InfoSuite) TestSpotAsset(assert, require *td.T) {
+	info := &Info{
+		coinToAsset:       make(map[string]int64),
+		nameToCoin:        make(map[string]string),
+		assetToSzDecimals: make(map[int64]int64),
+	}
+
+	info.initializeSpotMetadata(&SpotMeta{
+		Universe: []SpotAssetInfo{
+			{Name: "PURR/USDC", Tokens: [2]int64{1, 0}, Index: 0},
+			{Name: "@7", Tokens: [2]int64{2, 0}, Index: 7},
+			{Name: "@12", Tokens: [2]int64{2, 1}, Index: 12},
+		},
+		Tokens: []SpotTokenInfo{
+			{Name: "USDC", SzDecimals: 8, Index: 0},
+			{Name: "PURR", SzDecimals: 0, Index: 1},
+			{Name: "FOO", SzDecimals: 2, Index: 2},
+		},
+	})
+
+	asset, ok := info.SpotAsset("PURR", "USDC")
+	require.True(ok)
+	require.Cmp(asset, int64(10000))
+
+	asset, ok = info.SpotAsset("FOO", "USDC")
+	require.True(ok)
+	require.Cmp(asset, int64(10007))
+
+	asset, ok = info.SpotAsset("FOO", "PURR")
+	require.True(ok)
+	require.Cmp(asset, int64(10012))
+
+	// Base and quote are not interchangeable
+	_, ok = info.SpotAsset("USDC", "FOO")
+	require.False(ok)
+
+	_, ok = info.SpotAsset("BAR", "USDC")
+	require.False(ok)
+}
+
+// assetLookup is the set of accessors the exchange package relies on to
+// resolve assets
+type assetLookup interface {
+	GetAsset(name string) (int64, bool)
+	CoinToAsset(coin string) (int64, bool)
+	NameToAsset(name string) (int64, bool)
+	NameToCoin(name string) (string, bool)
+	AssetToSzDecimals(asset int64) (int64, bool)
+}
+
+var _ assetLookup = (*Info)(nil)
+
+func (s *InfoSuite) TestAssetAccessors(assert, require *td.T) {
+	info := &Info{
+		coinToAsset:       map[string]int64{"BTC": 0, "@107": 10107},
+		nameToCoin:        map[string]string{"BTC": "BTC", "HYPE/USDC": "@107"},
+		assetToSzDecimals: map[int64]int64{0: 5, 10107: 2},
+	}
+
+	asset, ok := info.CoinToAsset("@107")
+	require.True(ok)
+	require.Cmp(asset, int64(10107))
+
+	asset, ok = info.NameToAsset("HYPE/USDC")
+	require.True(ok)
+	require.Cmp(asset, int64(10107))
+
+	coin, ok := info.NameToCoin("HYPE/USDC")
+	require.True(ok)
+	require.Cmp(coin, "@107")
+
+	szDecimals, ok := info.AssetToSzDecimals(0)
+	require.True(ok)
+	require.Cmp(szDecimals, int64(5))
+
+	_, ok = info.CoinToAsset("HYPE/USDC")
+	require.False(ok, "coin lookup should not resolve names")
+
+	_, ok = info.NameToAsset("UNKNOWN")
+	require.False(ok)
+
+	_, ok = info.NameToCoin("UNKNOWN")
+	require.False(ok)
+
+	_, ok = info.AssetToSzDecimals(1)
+	require.False(ok)
+}
+
 func (s *InfoSuite) TestPullRealData(assert, require *td.T) {
 	// Manual test
 	tb := require.TB
@@ -838,6 +1495,104 @@ func (s *InfoSuite) TestPullRealData(assert, require *td.T) {
 
 // ===== Helper Functions =====
 
+func testBook() L2BookSnapshot {
+	return L2BookSnapshot{
+		Coin: "BTC",
+		Levels: [2][]L2Level{
+			{
+				{Px: 100, Sz: 1, N: 1},
+				{Px: 99, Sz: 2, N: 1},
+				{Px: 98, Sz: 3, N: 2},
+			},
+			{
+				{Px: 101, Sz: 1, N: 1},
+				{Px: 102, Sz: 2, N: 1},
+				{Px: 104, Sz: 3, N: 2},
+			},
+		},
+	}
+}
+
+func (s *InfoSuite) TestL2BookBestPrices(assert, require *td.T) {
+	book := testBook()
+
+	bid, ok := book.BestBid()
+	require.True(ok)
+	assert.Cmp(bid, 100.0)
+
+	ask, ok := book.BestAsk()
+	require.True(ok)
+	assert.Cmp(ask, 101.0)
+
+	mid, ok := book.Mid()
+	require.True(ok)
+	assert.Cmp(mid, 100.5)
+
+	spread, ok := book.Spread()
+	require.True(ok)
+	assert.Cmp(spread, 1.0)
+}
+
+func (s *InfoSuite) TestL2BookEmptySide(assert, require *td.T) {
+	book := testBook()
+	book.Levels[1] = nil
+
+	bid, ok := book.BestBid()
+	assert.True(ok)
+	assert.Cmp(bid, 100.0)
+
+	_, ok = book.BestAsk()
+	assert.False(ok)
+	_, ok = book.Mid()
+	assert.False(ok)
+	_, ok = book.Spread()
+	assert.False(ok)
+
+	_, ok = L2BookSnapshot{}.BestBid()
+	assert.False(ok)
+}
+
+func (s *InfoSuite) TestL2BookImpactPrice(assert, require *td.T) {
+	book := testBook()
+
+	// Fills 1 @ 101 and 2 @ 102
+	px, err := book.ImpactPrice(true, 3)
+	require.CmpNoError(err)
+	assert.Cmp(px, td.Between(101.666666, 101.666667))
+
+	// Fills 1 @ 100 and 0.5 @ 99
+	px, err = book.ImpactPrice(false, 1.5)
+	require.CmpNoError(err)
+	assert.Cmp(px, td.Between(99.666666, 99.666667))
+
+	// Within the first level
+	px, err = book.ImpactPrice(true, 0.5)
+	require.CmpNoError(err)
+	assert.Cmp(px, 101.0)
+
+	// Exactly the whole side, with sizes that do not sum exactly in floats
+	book.Levels[0] = []L2Level{{Px: 10, Sz: 0.1}, {Px: 10, Sz: 0.2}}
+	px, err = book.ImpactPrice(false, 0.3)
+	require.CmpNoError(err)
+	assert.Cmp(px, td.Between(9.999999, 10.000001))
+}
+
+func (s *InfoSuite) TestL2BookImpactPriceInsufficientLiquidity(
+	assert, require *td.T,
+) {
+	book := testBook()
+
+	_, err := book.ImpactPrice(true, 7)
+	assert.True(errors.Is(err, ErrInsufficientLiquidity))
+
+	book.Levels[0] = nil
+	_, err = book.ImpactPrice(false, 1)
+	assert.True(errors.Is(err, ErrInsufficientLiquidity))
+
+	_, err = book.ImpactPrice(true, 0)
+	assert.CmpError(err)
+}
+
 func ptr[T any](s T) *T {
 	return &s
 }
@@ -850,3 +1605,7 @@ func (m *mockSubscription) Unsubscribe() {}
 func (m *mockSubscription) Err() <-chan error {
 	return make(chan error)
 }
+
+func (m *mockSubscription) Dropped() uint64 {
+	return 0
+}
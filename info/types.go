@@ -1,6 +1,10 @@
 package info
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
 	"github.com/banky/go-hyperliquid/types"
 	"github.com/ethereum/go-ethereum/common"
 )
@@ -19,6 +23,83 @@ type L2BookSnapshot struct {
 	Time   int64        `json:"time"`
 }
 
+// BestBid returns the highest bid price. ok is false if there are no bids.
+func (b L2BookSnapshot) BestBid() (px float64, ok bool) {
+	if len(b.Levels[0]) == 0 {
+		return 0, false
+	}
+	return b.Levels[0][0].Px.Raw(), true
+}
+
+// BestAsk returns the lowest ask price. ok is false if there are no asks.
+func (b L2BookSnapshot) BestAsk() (px float64, ok bool) {
+	if len(b.Levels[1]) == 0 {
+		return 0, false
+	}
+	return b.Levels[1][0].Px.Raw(), true
+}
+
+// Mid returns the midpoint of the best bid and ask. ok is false if either
+// side of the book is empty.
+func (b L2BookSnapshot) Mid() (px float64, ok bool) {
+	bid, okBid := b.BestBid()
+	ask, okAsk := b.BestAsk()
+	if !okBid || !okAsk {
+		return 0, false
+	}
+	return (bid + ask) / 2, true
+}
+
+// Spread returns the best ask minus the best bid. ok is false if either side
+// of the book is empty.
+func (b L2BookSnapshot) Spread() (spread float64, ok bool) {
+	bid, okBid := b.BestBid()
+	ask, okAsk := b.BestAsk()
+	if !okBid || !okAsk {
+		return 0, false
+	}
+	return ask - bid, true
+}
+
+// ErrInsufficientLiquidity is returned by ImpactPrice when the book does not
+// hold enough size to fill the order
+var ErrInsufficientLiquidity = errors.New("insufficient liquidity")
+
+// ImpactPrice returns the average price of filling sz against the book,
+// walking the asks for a buy and the bids for a sell. It returns
+// ErrInsufficientLiquidity if the book holds less than sz on that side.
+func (b L2BookSnapshot) ImpactPrice(isBuy bool, sz float64) (float64, error) {
+	if sz <= 0 {
+		return 0, fmt.Errorf("size must be positive, got %v", sz)
+	}
+
+	levels := b.Levels[0]
+	if isBuy {
+		levels = b.Levels[1]
+	}
+
+	// Allow for rounding left over from subtracting decimal level sizes
+	epsilon := sz * 1e-12
+
+	remaining := sz
+	var notional float64
+	for _, level := range levels {
+		fill := min(remaining, level.Sz.Raw())
+		notional += fill * level.Px.Raw()
+		remaining -= fill
+		if remaining <= epsilon {
+			return notional / sz, nil
+		}
+	}
+
+	return 0, fmt.Errorf(
+		"%w: %v of %v unfilled",
+		ErrInsufficientLiquidity,
+		remaining,
+		sz,
+	)
+}
+
 // AssetInfo contains metadata about an asset
 type AssetInfo struct {
 	Name       string `json:"name"`
@@ -30,6 +111,16 @@ type Meta struct {
 	Universe []AssetInfo `json:"universe"`
 }
 
+// PerpDex describes a perp dex. The main dex is returned as null by the API
+// and decodes to the zero value.
+type PerpDex struct {
+	Name          string          `json:"name"`
+	FullName      string          `json:"fullName"`
+	Deployer      common.Address  `json:"deployer"`
+	OracleUpdater *common.Address `json:"oracleUpdater"`
+	FeeRecipient  *common.Address `json:"feeRecipient"`
+}
+
 // SpotAssetInfo contains spot asset metadata
 type SpotAssetInfo struct {
 	Name        string   `json:"name"`
@@ -103,6 +194,40 @@ type UserState struct {
 	Withdrawable       types.FloatString `json:"withdrawable"`
 }
 
+// PositionSummary is a flattened open position
+type PositionSummary struct {
+	Coin string
+	// Size is the signed position size, negative for shorts
+	Size          types.FloatString
+	EntryPx       *types.FloatString
+	UnrealizedPnl types.FloatString
+	Leverage      Leverage
+	// LiquidationPx is nil if the position cannot be liquidated
+	LiquidationPx *types.FloatString
+}
+
+// Positions returns a summary of every open position, skipping entries with
+// zero size. UnrealizedPnl is the value reported by the exchange, not one
+// recomputed from a mark price.
+func (u UserState) Positions() []PositionSummary {
+	positions := make([]PositionSummary, 0, len(u.AssetPositions))
+	for _, ap := range u.AssetPositions {
+		p := ap.Position
+		if p.Szi.IsZero() {
+			continue
+		}
+		positions = append(positions, PositionSummary{
+			Coin:          p.Coin,
+			Size:          p.Szi,
+			EntryPx:       p.EntryPx,
+			UnrealizedPnl: p.UnrealizedPnl,
+			Leverage:      p.Leverage,
+			LiquidationPx: p.LiquidationPx,
+		})
+	}
+	return positions
+}
+
 type Balance struct {
 	Coin     string            `json:"coin"`
 	Token    int64             `json:"token"`
@@ -304,6 +429,10 @@ type OrderData struct {
 	Cloid            *types.Cloid      `json:"cloid"`
 }
 
+// FrontendOpenOrder is a resting order as returned by frontendOpenOrders,
+// including trigger details and the cloid
+type FrontendOpenOrder = OrderData
+
 // OrderResponse represents an order with its metadata
 type OrderResponse struct {
 	Order           OrderData   `json:"order"`
@@ -396,3 +525,255 @@ type UserFeeInfo struct {
 	StakingLink               *string               `json:"stakingLink"`
 	ActiveStakingDiscount     StakingDiscountTier   `json:"activeStakingDiscount"`
 }
+
+// PerpAssetCtx contains the market context of a perpetual asset
+type PerpAssetCtx struct {
+	Funding      types.FloatString     `json:"funding"`
+	OpenInterest types.FloatString     `json:"openInterest"`
+	PrevDayPx    types.FloatString     `json:"prevDayPx"`
+	DayNtlVlm    types.FloatString     `json:"dayNtlVlm"`
+	Premium      *types.FloatString    `json:"premium"`
+	OraclePx     types.FloatString     `json:"oraclePx"`
+	MarkPx       types.FloatString     `json:"markPx"`
+	MidPx        *types.FloatString    `json:"midPx"`
+	ImpactPxs    *[2]types.FloatString `json:"impactPxs"`
+	DayBaseVlm   types.FloatString     `json:"dayBaseVlm"`
+}
+
+// SpotAssetCtx contains the market context of a spot pair
+type SpotAssetCtx struct {
+	DayNtlVlm         types.FloatString  `json:"dayNtlVlm"`
+	MarkPx            types.FloatString  `json:"markPx"`
+	MidPx             *types.FloatString `json:"midPx"`
+	PrevDayPx         types.FloatString  `json:"prevDayPx"`
+	CirculatingSupply types.FloatString  `json:"circulatingSupply"`
+	Coin              string             `json:"coin"`
+	TotalSupply       types.FloatString  `json:"totalSupply"`
+	DayBaseVlm        types.FloatString  `json:"dayBaseVlm"`
+}
+
+// VenueFunding is the predicted funding of a coin on a single venue
+type VenueFunding struct {
+	Venue                string            `json:"venue"`
+	FundingRate          types.FloatString `json:"fundingRate"`
+	NextFundingTime      int64             `json:"nextFundingTime"`
+	FundingIntervalHours int64             `json:"fundingIntervalHours"`
+}
+
+// PredictedFunding contains the predicted funding of a coin on every venue
+// that lists it
+type PredictedFunding struct {
+	Coin   string         `json:"coin"`
+	Venues []VenueFunding `json:"venues"`
+}
+
+// UnmarshalJSON decodes the [coin, [[venue, funding], ...]] tuple returned by
+// the API. Venues without a prediction are skipped.
+func (p *PredictedFunding) UnmarshalJSON(data []byte) error {
+	var raw [2]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("failed to decode predicted funding: %w", err)
+	}
+
+	if err := json.Unmarshal(raw[0], &p.Coin); err != nil {
+		return fmt.Errorf("failed to decode predicted funding coin: %w", err)
+	}
+
+	var venues [][2]json.RawMessage
+	if err := json.Unmarshal(raw[1], &venues); err != nil {
+		return fmt.Errorf("failed to decode predicted funding venues: %w", err)
+	}
+
+	p.Venues = make([]VenueFunding, 0, len(venues))
+	for _, v := range venues {
+		var venue string
+		if err := json.Unmarshal(v[0], &venue); err != nil {
+			return fmt.Errorf("failed to decode venue name: %w", err)
+		}
+
+		var funding *VenueFunding
+		if err := json.Unmarshal(v[1], &funding); err != nil {
+			return fmt.Errorf("failed to decode %s funding: %w", venue, err)
+		}
+		if funding == nil {
+			continue
+		}
+
+		funding.Venue = venue
+		p.Venues = append(p.Venues, *funding)
+	}
+
+	return nil
+}
+
+// SubAccount contains a sub-account of a master account along with its perp
+// and spot state
+type SubAccount struct {
+	Name               string         `json:"name"`
+	SubAccountUser     common.Address `json:"subAccountUser"`
+	Master             common.Address `json:"master"`
+	ClearinghouseState UserState      `json:"clearinghouseState"`
+	SpotState          SpotUserState  `json:"spotState"`
+}
+
+// HistoryPoint is a single [timestamp, value] sample of a time series
+type HistoryPoint struct {
+	Time  int64             `json:"time"`
+	Value types.FloatString `json:"value"`
+}
+
+// UnmarshalJSON decodes the [timestamp, value] tuple returned by the API
+func (h *HistoryPoint) UnmarshalJSON(data []byte) error {
+	var raw [2]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("failed to decode history point: %w", err)
+	}
+
+	if err := json.Unmarshal(raw[0], &h.Time); err != nil {
+		return fmt.Errorf("failed to decode history point time: %w", err)
+	}
+
+	if err := json.Unmarshal(raw[1], &h.Value); err != nil {
+		return fmt.Errorf("failed to decode history point value: %w", err)
+	}
+
+	return nil
+}
+
+// PortfolioPeriod contains the account value and PnL history over a period
+type PortfolioPeriod struct {
+	AccountValueHistory []HistoryPoint    `json:"accountValueHistory"`
+	PnlHistory          []HistoryPoint    `json:"pnlHistory"`
+	Vlm                 types.FloatString `json:"vlm"`
+}
+
+// Portfolio period names. The perp prefixed periods only include perp
+// activity.
+const (
+	PortfolioDay         = "day"
+	PortfolioWeek        = "week"
+	PortfolioMonth       = "month"
+	PortfolioAllTime     = "allTime"
+	PortfolioPerpDay     = "perpDay"
+	PortfolioPerpWeek    = "perpWeek"
+	PortfolioPerpMonth   = "perpMonth"
+	PortfolioPerpAllTime = "perpAllTime"
+)
+
+// Portfolio maps a period name (e.g. "day", "week", "month", "allTime") to
+// the history over that period
+type Portfolio map[string]PortfolioPeriod
+
+// UnmarshalJSON decodes the [[period, history], ...] list returned by the API
+func (p *Portfolio) UnmarshalJSON(data []byte) error {
+	var raw [][2]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("failed to decode portfolio: %w", err)
+	}
+
+	portfolio := make(Portfolio, len(raw))
+	for _, entry := range raw {
+		var period string
+		if err := json.Unmarshal(entry[0], &period); err != nil {
+			return fmt.Errorf("failed to decode portfolio period: %w", err)
+		}
+
+		var history PortfolioPeriod
+		if err := json.Unmarshal(entry[1], &history); err != nil {
+			return fmt.Errorf("failed to decode %s portfolio: %w", period, err)
+		}
+
+		portfolio[period] = history
+	}
+
+	*p = portfolio
+	return nil
+}
+
+// VaultFollower contains the state of a depositor in a vault
+type VaultFollower struct {
+	User           common.Address    `json:"user"`
+	VaultEquity    types.FloatString `json:"vaultEquity"`
+	Pnl            types.FloatString `json:"pnl"`
+	AllTimePnl     types.FloatString `json:"allTimePnl"`
+	DaysFollowing  int64             `json:"daysFollowing"`
+	VaultEntryTime int64             `json:"vaultEntryTime"`
+	LockupUntil    int64             `json:"lockupUntil"`
+}
+
+// VaultDetails contains the configuration, performance and followers of a
+// vault
+type VaultDetails struct {
+	Name                  string            `json:"name"`
+	VaultAddress          common.Address    `json:"vaultAddress"`
+	Leader                common.Address    `json:"leader"`
+	Description           string            `json:"description"`
+	Portfolio             Portfolio         `json:"portfolio"`
+	Apr                   float64           `json:"apr"`
+	FollowerState         *VaultFollower    `json:"followerState"`
+	LeaderFraction        float64           `json:"leaderFraction"`
+	LeaderCommission      float64           `json:"leaderCommission"`
+	Followers             []VaultFollower   `json:"followers"`
+	MaxDistributable      types.FloatString `json:"maxDistributable"`
+	MaxWithdrawable       types.FloatString `json:"maxWithdrawable"`
+	IsClosed              bool              `json:"isClosed"`
+	AllowDeposits         bool              `json:"allowDeposits"`
+	AlwaysCloseOnWithdraw bool              `json:"alwaysCloseOnWithdraw"`
+}
+
+// ReferredBy identifies the referrer of a user and the code they used
+type ReferredBy struct {
+	Referrer common.Address `json:"referrer"`
+	Code     string         `json:"code"`
+}
+
+// ReferredUserState contains the referral stats of a user referred by the
+// queried user
+type ReferredUserState struct {
+	User                         common.Address    `json:"user"`
+	CumVlm                       types.FloatString `json:"cumVlm"`
+	CumRewardedFeesSinceReferred types.FloatString `json:"cumRewardedFeesSinceReferred"`
+	CumFeesRewardedToReferrer    types.FloatString `json:"cumFeesRewardedToReferrer"`
+	TimeJoined                   int64             `json:"timeJoined"`
+}
+
+// ReferrerStateData holds the stage specific data of a referrer. Code and
+// ReferralStates are set once the stage is "ready", Required is set while the
+// stage is "needToTrade".
+type ReferrerStateData struct {
+	Code           string              `json:"code"`
+	ReferralStates []ReferredUserState `json:"referralStates"`
+	Required       types.FloatString   `json:"required"`
+}
+
+// ReferrerState describes whether the user can refer others, e.g. "ready",
+// "needToCreateCode" or "needToTrade"
+type ReferrerState struct {
+	Stage string             `json:"stage"`
+	Data  *ReferrerStateData `json:"data"`
+}
+
+// ReferralState contains the referral status and rewards of a user
+type ReferralState struct {
+	ReferredBy       *ReferredBy       `json:"referredBy"`
+	CumVlm           types.FloatString `json:"cumVlm"`
+	UnclaimedRewards types.FloatString `json:"unclaimedRewards"`
+	ClaimedRewards   types.FloatString `json:"claimedRewards"`
+	BuilderRewards   types.FloatString `json:"builderRewards"`
+	ReferrerState    ReferrerState     `json:"referrerState"`
+}
+
+// Delegation is an amount of HYPE staked to a validator
+type Delegation struct {
+	Validator            common.Address    `json:"validator"`
+	Amount               types.FloatString `json:"amount"`
+	LockedUntilTimestamp int64             `json:"lockedUntilTimestamp"`
+}
+
+// DelegatorSummary contains the staking totals of a delegator
+type DelegatorSummary struct {
+	Delegated              types.FloatString `json:"delegated"`
+	Undelegated            types.FloatString `json:"undelegated"`
+	TotalPendingWithdrawal types.FloatString `json:"totalPendingWithdrawal"`
+	NPendingWithdrawals    int64             `json:"nPendingWithdrawals"`
+}
@@ -0,0 +1,34 @@
+// Package logging defines the logger and request observer used by the
+// exchange, info and ws clients.
+package logging
+
+// Logger receives structured log events. keyvals holds alternating keys and
+// values, for example "nonce", 1700000000000. *slog.Logger implements Logger,
+// so slog.Default() can be passed directly.
+type Logger interface {
+	Debug(msg string, keyvals ...any)
+	Info(msg string, keyvals ...any)
+	Warn(msg string, keyvals ...any)
+	Error(msg string, keyvals ...any)
+}
+
+// Nop returns a Logger that discards every event. It is the default for all
+// clients.
+func Nop() Logger {
+	return nop{}
+}
+
+// OrNop returns l, or a no-op Logger if l is nil
+func OrNop(l Logger) Logger {
+	if l == nil {
+		return Nop()
+	}
+	return l
+}
+
+type nop struct{}
+
+func (nop) Debug(string, ...any) {}
+func (nop) Info(string, ...any)  {}
+func (nop) Warn(string, ...any)  {}
+func (nop) Error(string, ...any) {}
@@ -0,0 +1,40 @@
+package logging
+
+import "time"
+
+// Request types passed to Observer. They match the request types accepted by
+// the websocket post channel.
+const (
+	RequestTypeInfo   = "info"
+	RequestTypeAction = "action"
+)
+
+// Observer is notified once for every /info and /exchange call, whether it is
+// sent over REST or the websocket post channel. requestType is
+// RequestTypeInfo or RequestTypeAction, duration covers the whole call
+// including retries, and err is the error returned to the caller, or nil.
+// Implementations must be safe for concurrent use.
+type Observer interface {
+	ObserveRequest(requestType string, duration time.Duration, err error)
+}
+
+// ObserverFunc adapts a function to an Observer
+type ObserverFunc func(requestType string, duration time.Duration, err error)
+
+// ObserveRequest calls f
+func (f ObserverFunc) ObserveRequest(
+	requestType string,
+	duration time.Duration,
+	err error,
+) {
+	f(requestType, duration, err)
+}
+
+// Observe reports a call that began at start to o. It does nothing if o is
+// nil, so callers need not check.
+func Observe(o Observer, requestType string, start time.Time, err error) {
+	if o == nil {
+		return
+	}
+	o.ObserveRequest(requestType, time.Since(start), err)
+}
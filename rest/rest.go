@@ -5,16 +5,22 @@ package rest
 import (
 	"context"
 	"encoding/json"
+	"net/http"
 	"time"
 
 	"github.com/banky/go-hyperliquid/constants"
+	"github.com/banky/go-hyperliquid/logging"
 	"github.com/go-resty/resty/v2"
 	"github.com/samber/mo"
 )
 
 type Client struct {
-	baseUrl string
-	timeout mo.Option[time.Duration]
+	http          *resty.Client
+	baseUrl       string
+	timeout       mo.Option[time.Duration]
+	retry         RetryPolicy
+	exchangeRetry RetryPolicy
+	observer      logging.Observer
 }
 
 // ClientInterface defines the contract for REST API calls
@@ -32,6 +38,20 @@ type Config struct {
 	// Timeout is the timeout for network requests
 	// If none is provided, no timeout will be enforced
 	Timeout time.Duration
+	// Retry is the retry policy for every path except /exchange
+	// If none is provided, requests are not retried
+	Retry RetryPolicy
+	// ExchangeRetry is the retry policy for /exchange. RetryOnResponse is
+	// ignored so that an action the server has answered is never sent twice.
+	// If none is provided, requests are not retried
+	ExchangeRetry RetryPolicy
+	// Observer is notified of the duration and outcome of every /info and
+	// /exchange request. If none is provided, requests are not observed
+	Observer logging.Observer
+	// HTTPClient is the client used to send every request, for example to
+	// configure a proxy, TLS or connection pooling. It is shared by
+	// concurrent requests. If none is provided, a default client is used
+	HTTPClient *http.Client
 }
 
 // New creates a new client instance with the
@@ -47,9 +67,27 @@ func New(c Config) *Client {
 		timeout = mo.Some(c.Timeout)
 	}
 
+	exchangeRetry := c.ExchangeRetry
+	exchangeRetry.RetryOnResponse = false
+
+	var httpClient *resty.Client
+	if c.HTTPClient != nil {
+		httpClient = resty.NewWithClient(c.HTTPClient)
+	} else {
+		httpClient = resty.New()
+	}
+	httpClient.
+		// SetDebug(true).
+		SetJSONMarshaler(json.Marshal).
+		SetJSONUnmarshaler(json.Unmarshal)
+
 	client := &Client{
-		baseUrl: baseUrl,
-		timeout: timeout,
+		http:          httpClient,
+		baseUrl:       baseUrl,
+		timeout:       timeout,
+		retry:         c.Retry,
+		exchangeRetry: exchangeRetry,
+		observer:      c.Observer,
 	}
 
 	return client
@@ -72,17 +110,17 @@ func (c *Client) NetworkName() string {
 }
 
 // Post sends a POST request to the specified path with the provided body.
+// Failed requests are retried according to the policy configured for path.
 func (c *Client) Post(
 	ctx context.Context,
 	path string,
 	body any,
 	result any,
-) error {
-	r := resty.
-		New().
-		// SetDebug(true).
-		SetJSONMarshaler(json.Marshal).
-		SetJSONUnmarshaler(json.Unmarshal)
+) (err error) {
+	if requestType, ok := observedRequestType(path); ok {
+		start := time.Now()
+		defer func() { logging.Observe(c.observer, requestType, start, err) }()
+	}
 
 	url := c.baseUrl + path
 
@@ -93,20 +131,50 @@ func (c *Client) Post(
 		defer cancel()
 	}
 
-	resp, err := r.R().
-		SetContext(ctx).
-		SetHeader("Content-Type", "application/json").
-		SetBody(body).
-		SetResult(&result).
-		Post(url)
-
-	if err != nil {
-		return err
+	policy := c.retry
+	if path == "/exchange" {
+		policy = c.exchangeRetry
 	}
 
-	if err := handleException(resp); err != nil {
-		return err
+	for attempt := 1; ; attempt++ {
+		var resp *resty.Response
+		resp, err = c.http.R().
+			SetContext(ctx).
+			SetHeader("Content-Type", "application/json").
+			// Set explicitly so responses are compressed whatever the
+			// transport. resty decompresses them.
+			SetHeader("Accept-Encoding", "gzip").
+			SetBody(body).
+			SetResult(&result).
+			Post(url)
+
+		if err == nil {
+			err = handleException(resp)
+			if err == nil {
+				return nil
+			}
+		}
+
+		if !policy.shouldRetry(ctx, attempt, resp, err) {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(policy.backoff(attempt)):
+		}
 	}
+}
 
-	return nil
+// observedRequestType returns the observer request type for path, and false
+// for paths that are not observed
+func observedRequestType(path string) (string, bool) {
+	switch path {
+	case "/info":
+		return logging.RequestTypeInfo, true
+	case "/exchange":
+		return logging.RequestTypeAction, true
+	}
+	return "", false
 }
@@ -1,12 +1,17 @@
 package rest
 
 import (
+	"compress/gzip"
 	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
+
+	"github.com/banky/go-hyperliquid/logging"
 )
 
 type testRequest struct {
@@ -203,3 +208,202 @@ func TestPostWithTimeout(t *testing.T) {
 		t.Errorf("expected {ok 42}, got {%s %d}", result.Status, result.Value)
 	}
 }
+
+type observation struct {
+	requestType string
+	duration    time.Duration
+	err         error
+}
+
+func TestPostObserver(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/exchange" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(testResponse{Status: "ok", Value: 42})
+		}),
+	)
+	defer server.Close()
+
+	var observed []observation
+	client := New(Config{
+		BaseUrl: server.URL,
+		Observer: logging.ObserverFunc(
+			func(requestType string, duration time.Duration, err error) {
+				observed = append(
+					observed,
+					observation{requestType, duration, err},
+				)
+			},
+		),
+	})
+
+	var result testResponse
+	ctx := context.Background()
+	infoErr := client.Post(ctx, "/info", testRequest{Name: "test"}, &result)
+	exchangeErr := client.Post(ctx, "/exchange", testRequest{}, &result)
+	if err := client.Post(ctx, "/test", testRequest{}, &result); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if infoErr != nil {
+		t.Fatalf("expected no error, got %v", infoErr)
+	}
+	if exchangeErr == nil {
+		t.Fatal("expected an error from /exchange")
+	}
+
+	// /test is not an /info or /exchange call and is not observed
+	if len(observed) != 2 {
+		t.Fatalf("expected 2 observations, got %d", len(observed))
+	}
+	want := []observation{
+		{requestType: logging.RequestTypeInfo},
+		{requestType: logging.RequestTypeAction, err: exchangeErr},
+	}
+	for i, o := range observed {
+		if o.requestType != want[i].requestType {
+			t.Errorf(
+				"observation %d: expected type %s, got %s",
+				i,
+				want[i].requestType,
+				o.requestType,
+			)
+		}
+		if o.duration < 0 {
+			t.Errorf("observation %d: negative duration %v", i, o.duration)
+		}
+		if o.err != want[i].err {
+			t.Errorf(
+				"observation %d: expected err %v, got %v",
+				i,
+				want[i].err,
+				o.err,
+			)
+		}
+	}
+}
+
+func TestPostNilObserver(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(testResponse{Status: "ok", Value: 42})
+		}),
+	)
+	defer server.Close()
+
+	client := New(Config{BaseUrl: server.URL})
+	var result testResponse
+	err := client.Post(context.Background(), "/info", testRequest{}, &result)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+}
+
+// countingTransport counts the requests it sends before delegating to the
+// default transport
+type countingTransport struct {
+	count atomic.Int64
+}
+
+func (c *countingTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	c.count.Add(1)
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+func TestPostUsesHTTPClient(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(testResponse{Status: "ok", Value: 42})
+		}),
+	)
+	defer server.Close()
+
+	transport := &countingTransport{}
+	client := New(Config{
+		BaseUrl:    server.URL,
+		HTTPClient: &http.Client{Transport: transport},
+	})
+
+	for range 2 {
+		var result testResponse
+		err := client.Post(
+			context.Background(),
+			"/test",
+			testRequest{Name: "test"},
+			&result,
+		)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if result.Value != 42 {
+			t.Errorf("expected value 42, got %d", result.Value)
+		}
+	}
+
+	if got := transport.count.Load(); got != 2 {
+		t.Errorf("expected 2 requests through the transport, got %d", got)
+	}
+}
+
+func TestPostDecodesGzipResponse(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+				t.Errorf(
+					"expected gzip in Accept-Encoding, got %q",
+					r.Header.Get("Accept-Encoding"),
+				)
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("Content-Encoding", "gzip")
+			gz := gzip.NewWriter(w)
+			defer gz.Close()
+			json.NewEncoder(gz).Encode(testResponse{Status: "ok", Value: 42})
+		}),
+	)
+	defer server.Close()
+
+	// Compression is requested even when the transport would not ask for it
+	clients := map[string]*Client{
+		"default": New(Config{BaseUrl: server.URL}),
+		"compression disabled": New(Config{
+			BaseUrl: server.URL,
+			HTTPClient: &http.Client{
+				Transport: &http.Transport{DisableCompression: true},
+			},
+		}),
+	}
+
+	for name, client := range clients {
+		var result testResponse
+		err := client.Post(
+			context.Background(),
+			"/info",
+			testRequest{Name: "test"},
+			&result,
+		)
+		if err != nil {
+			t.Fatalf("%s: expected no error, got %v", name, err)
+		}
+
+		if result.Status != "ok" || result.Value != 42 {
+			t.Errorf(
+				"%s: expected {ok 42}, got {%s %d}",
+				name,
+				result.Status,
+				result.Value,
+			)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package rest
+
+import (
+	"context"
+	"math/rand/v2"
+	"net/http"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// RetryPolicy controls how failed requests are retried. Delays grow
+// exponentially from InitialBackoff up to MaxBackoff, with jitter. The zero
+// value disables retries.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first.
+	// Values below 2 disable retries.
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry
+	InitialBackoff time.Duration
+	// MaxBackoff caps the delay between retries. If zero, the delay is not
+	// capped.
+	MaxBackoff time.Duration
+	// RetryOnResponse also retries when the server answers with a 5xx or 429
+	// status. Without it only network errors, where no response was
+	// received, are retried.
+	RetryOnResponse bool
+}
+
+// DefaultRetryPolicy is a policy suited to idempotent info queries
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:     3,
+	InitialBackoff:  200 * time.Millisecond,
+	MaxBackoff:      2 * time.Second,
+	RetryOnResponse: true,
+}
+
+// DefaultExchangeRetryPolicy is a policy suited to /exchange. Only network
+// errors are retried. Resending is safe in that case because the payload
+// keeps its nonce, which the exchange accepts at most once.
+var DefaultExchangeRetryPolicy = RetryPolicy{
+	MaxAttempts:    3,
+	InitialBackoff: 100 * time.Millisecond,
+	MaxBackoff:     1 * time.Second,
+}
+
+// shouldRetry reports whether a request that failed on attempt with err
+// should be tried again. resp is nil when no response was received.
+func (p RetryPolicy) shouldRetry(
+	ctx context.Context,
+	attempt int,
+	resp *resty.Response,
+	err error,
+) bool {
+	if attempt >= p.MaxAttempts || ctx.Err() != nil {
+		return false
+	}
+
+	// No response means the request failed at the network level
+	if resp == nil || resp.RawResponse == nil {
+		return true
+	}
+
+	if !p.RetryOnResponse {
+		return false
+	}
+
+	status := resp.StatusCode()
+	return status >= 500 || status == http.StatusTooManyRequests
+}
+
+// backoff returns the delay before the retry following attempt. Half the
+// delay is fixed and half is random, so concurrent clients spread out.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	d := p.InitialBackoff << (attempt - 1)
+	if d <= 0 || (p.MaxBackoff > 0 && d > p.MaxBackoff) {
+		d = p.MaxBackoff
+	}
+	if d <= 0 {
+		return 0
+	}
+
+	half := d / 2
+	return half + rand.N(d-half+1)
+}
@@ -0,0 +1,225 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+var testRetryPolicy = RetryPolicy{
+	MaxAttempts:     3,
+	InitialBackoff:  time.Millisecond,
+	MaxBackoff:      5 * time.Millisecond,
+	RetryOnResponse: true,
+}
+
+// flakyServer answers with fail for the first failures requests and with a
+// successful response afterwards. It returns the server and its request
+// counter.
+func flakyServer(
+	t *testing.T,
+	failures int64,
+	fail func(w http.ResponseWriter),
+) (*httptest.Server, *atomic.Int64) {
+	t.Helper()
+
+	var attempts atomic.Int64
+	server := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if attempts.Add(1) <= failures {
+				fail(w)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(testResponse{Status: "ok", Value: 42})
+		}),
+	)
+	t.Cleanup(server.Close)
+
+	return server, &attempts
+}
+
+func badGateway(w http.ResponseWriter) {
+	w.WriteHeader(http.StatusBadGateway)
+}
+
+// dropConnection closes the connection without writing a response
+func dropConnection(w http.ResponseWriter) {
+	conn, _, err := w.(http.Hijacker).Hijack()
+	if err == nil {
+		conn.Close()
+	}
+}
+
+func TestPostRetriesInfoOnServerError(t *testing.T) {
+	t.Parallel()
+	server, attempts := flakyServer(t, 2, badGateway)
+
+	client := New(Config{BaseUrl: server.URL, Retry: testRetryPolicy})
+	var result testResponse
+	err := client.Post(
+		context.Background(),
+		"/info",
+		testRequest{Name: "test"},
+		&result,
+	)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if got := attempts.Load(); got != 3 {
+		t.Errorf("expected 3 attempts, got %d", got)
+	}
+	if result.Value != 42 {
+		t.Errorf("expected value 42, got %d", result.Value)
+	}
+}
+
+func TestPostRetryGivesUp(t *testing.T) {
+	t.Parallel()
+	server, attempts := flakyServer(t, 10, badGateway)
+
+	client := New(Config{BaseUrl: server.URL, Retry: testRetryPolicy})
+	var result testResponse
+	err := client.Post(
+		context.Background(),
+		"/info",
+		testRequest{Name: "test"},
+		&result,
+	)
+
+	if _, ok := err.(*ServerError); !ok {
+		t.Fatalf("expected ServerError, got %T", err)
+	}
+	if got := attempts.Load(); got != 3 {
+		t.Errorf("expected 3 attempts, got %d", got)
+	}
+}
+
+func TestPostDoesNotRetryClientError(t *testing.T) {
+	t.Parallel()
+	server, attempts := flakyServer(t, 1, func(w http.ResponseWriter) {
+		w.WriteHeader(http.StatusBadRequest)
+	})
+
+	client := New(Config{BaseUrl: server.URL, Retry: testRetryPolicy})
+	var result testResponse
+	err := client.Post(
+		context.Background(),
+		"/info",
+		testRequest{Name: "test"},
+		&result,
+	)
+
+	if _, ok := err.(*ClientError); !ok {
+		t.Fatalf("expected ClientError, got %T", err)
+	}
+	if got := attempts.Load(); got != 1 {
+		t.Errorf("expected 1 attempt, got %d", got)
+	}
+}
+
+func TestPostDoesNotRetryExchangeAfterResponse(t *testing.T) {
+	t.Parallel()
+	server, attempts := flakyServer(t, 1, badGateway)
+
+	// RetryOnResponse is ignored for /exchange
+	client := New(Config{
+		BaseUrl:       server.URL,
+		Retry:         testRetryPolicy,
+		ExchangeRetry: testRetryPolicy,
+	})
+	var result testResponse
+	err := client.Post(
+		context.Background(),
+		"/exchange",
+		testRequest{Name: "test"},
+		&result,
+	)
+
+	if _, ok := err.(*ServerError); !ok {
+		t.Fatalf("expected ServerError, got %T", err)
+	}
+	if got := attempts.Load(); got != 1 {
+		t.Errorf("expected 1 attempt, got %d", got)
+	}
+}
+
+func TestPostRetriesExchangeOnNetworkError(t *testing.T) {
+	t.Parallel()
+	server, attempts := flakyServer(t, 1, dropConnection)
+
+	client := New(Config{BaseUrl: server.URL, ExchangeRetry: testRetryPolicy})
+	var result testResponse
+	err := client.Post(
+		context.Background(),
+		"/exchange",
+		testRequest{Name: "test"},
+		&result,
+	)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if got := attempts.Load(); got != 2 {
+		t.Errorf("expected 2 attempts, got %d", got)
+	}
+}
+
+func TestPostWithoutRetryPolicy(t *testing.T) {
+	t.Parallel()
+	server, attempts := flakyServer(t, 1, dropConnection)
+
+	client := New(Config{BaseUrl: server.URL})
+	var result testResponse
+	err := client.Post(
+		context.Background(),
+		"/info",
+		testRequest{Name: "test"},
+		&result,
+	)
+
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if got := attempts.Load(); got != 1 {
+		t.Errorf("expected 1 attempt, got %d", got)
+	}
+}
+
+func TestRetryBackoff(t *testing.T) {
+	t.Parallel()
+	policy := RetryPolicy{
+		MaxAttempts:    10,
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     time.Second,
+	}
+
+	tests := []struct {
+		attempt int
+		max     time.Duration
+	}{
+		{1, 100 * time.Millisecond},
+		{2, 200 * time.Millisecond},
+		{3, 400 * time.Millisecond},
+		{5, time.Second},
+		{60, time.Second},
+	}
+
+	for _, tt := range tests {
+		got := policy.backoff(tt.attempt)
+		if got < tt.max/2 || got > tt.max {
+			t.Errorf(
+				"backoff(%d) = %v, want between %v and %v",
+				tt.attempt,
+				got,
+				tt.max/2,
+				tt.max,
+			)
+		}
+	}
+}
@@ -1,10 +1,13 @@
 package types
 
 import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
 	"math/big"
 	"reflect"
+	"strings"
 
-	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/vmihailenco/msgpack/v5"
 )
@@ -23,10 +26,53 @@ func BytesToCloid(b []byte) Cloid {
 	return c
 }
 
-// HexToCloid returns Cloid with byte values of s.
-// If s is larger than len(c), s will be cropped from the left.
-func HexToCloid(s string) Cloid {
-	return BytesToCloid(common.FromHex(s))
+// NewCloid returns a random Cloid read from crypto/rand.
+func NewCloid() Cloid {
+	var c Cloid
+	// rand.Read never returns an error and always fills c.
+	_, _ = rand.Read(c[:])
+	return c
+}
+
+// HexToCloid returns Cloid with byte values of s. s must encode exactly
+// len(Cloid) bytes, with or without a 0x prefix.
+func HexToCloid(s string) (Cloid, error) {
+	var c Cloid
+	h := strings.TrimPrefix(strings.TrimPrefix(s, "0x"), "0X")
+	if len(h) != 2*cloidLength {
+		return c, fmt.Errorf(
+			"invalid cloid %q: want %d bytes", s, cloidLength,
+		)
+	}
+	if _, err := hex.Decode(c[:], []byte(h)); err != nil {
+		return Cloid{}, fmt.Errorf("invalid cloid %q: %w", s, err)
+	}
+	return c, nil
+}
+
+// MustHexToCloid is like HexToCloid but panics if s is not a valid cloid.
+func MustHexToCloid(s string) Cloid {
+	c, err := HexToCloid(s)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// UuidToCloid returns the Cloid holding the 16 bytes of the canonical
+// 36-character UUID string u (e.g. "123e4567-e89b-12d3-a456-426614174000").
+func UuidToCloid(u string) (Cloid, error) {
+	var c Cloid
+	if len(u) != 36 || u[8] != '-' || u[13] != '-' || u[18] != '-' ||
+		u[23] != '-' {
+		return c, fmt.Errorf("invalid uuid %q", u)
+	}
+
+	s := u[0:8] + u[9:13] + u[14:18] + u[19:23] + u[24:]
+	if _, err := hex.Decode(c[:], []byte(s)); err != nil {
+		return Cloid{}, fmt.Errorf("invalid uuid %q: %w", u, err)
+	}
+	return c, nil
 }
 
 // BigToHash sets byte representation of b to cloid.
@@ -48,6 +94,13 @@ func (c *Cloid) SetBytes(b []byte) {
 // Hex converts a Cloid to a hex string.
 func (c Cloid) Hex() string { return hexutil.Encode(c[:]) }
 
+// Uuid returns c formatted as a canonical lowercase UUID string.
+func (c Cloid) Uuid() string {
+	h := hex.EncodeToString(c[:])
+	return h[0:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" +
+		h[20:]
+}
+
 // String implements the stringer interface and is used also by the logger when
 // doing full logging into a file.
 func (c Cloid) String() string {
@@ -76,6 +129,6 @@ func (c *Cloid) DecodeMsgpack(dec *msgpack.Decoder) error {
 	}
 
 	// Parse back from hex string (e.g. "0x0000...")
-	*c = HexToCloid(s)
-	return nil
+	*c, err = HexToCloid(s)
+	return err
 }
@@ -0,0 +1,85 @@
+package types
+
+import (
+	"testing"
+)
+
+func TestUuidToCloidRoundTrip(t *testing.T) {
+	u := "123e4567-e89b-12d3-a456-426614174000"
+
+	c, err := UuidToCloid(u)
+	if err != nil {
+		t.Fatalf("UuidToCloid: %v", err)
+	}
+	if got, want := c.Hex(), "0x123e4567e89b12d3a456426614174000"; got != want {
+		t.Fatalf("Hex() = %s, want %s", got, want)
+	}
+	if got := c.Uuid(); got != u {
+		t.Fatalf("Uuid() = %s, want %s", got, u)
+	}
+
+	upper, err := UuidToCloid("123E4567-E89B-12D3-A456-426614174000")
+	if err != nil {
+		t.Fatalf("UuidToCloid uppercase: %v", err)
+	}
+	if upper != c {
+		t.Fatalf("uppercase uuid = %s, want %s", upper, c)
+	}
+}
+
+func TestUuidToCloidInvalid(t *testing.T) {
+	for _, u := range []string{
+		"",
+		"123e4567e89b12d3a456426614174000",
+		"123e4567-e89b-12d3-a456-42661417400",
+		"123e4567-e89b-12d3-a456_426614174000",
+		"123e4567-e89b-12d3-a456-42661417400z",
+	} {
+		if _, err := UuidToCloid(u); err == nil {
+			t.Errorf("UuidToCloid(%q) succeeded, want error", u)
+		}
+	}
+}
+
+func TestNewCloidUnique(t *testing.T) {
+	const n = 10000
+	seen := make(map[Cloid]struct{}, n)
+	for range n {
+		c := NewCloid()
+		if _, ok := seen[c]; ok {
+			t.Fatalf("duplicate cloid %s", c)
+		}
+		seen[c] = struct{}{}
+	}
+}
+
+func TestHexToCloid(t *testing.T) {
+	c, err := HexToCloid("0x00000000000000000000000000000001")
+	if err != nil {
+		t.Fatalf("HexToCloid: %v", err)
+	}
+	if c != BytesToCloid([]byte{1}) {
+		t.Fatalf("HexToCloid = %s", c)
+	}
+
+	noPrefix, err := HexToCloid("00000000000000000000000000000001")
+	if err != nil || noPrefix != c {
+		t.Fatalf("HexToCloid without prefix = %s, %v", noPrefix, err)
+	}
+}
+
+func TestHexToCloidInvalid(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"0x",
+		// 10 bytes
+		"0x00000000000000000001",
+		// 17 bytes
+		"0x0000000000000000000000000000000001",
+		"0x0000000000000000000000000000000z",
+	} {
+		if _, err := HexToCloid(s); err == nil {
+			t.Errorf("HexToCloid(%q) succeeded, want error", s)
+		}
+	}
+}
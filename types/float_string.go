@@ -2,6 +2,8 @@ package types
 
 import (
 	"encoding/json"
+	"math/big"
+	"strconv"
 
 	"github.com/banky/go-hyperliquid/internal/utils"
 )
@@ -46,3 +48,74 @@ func (f FloatString) String() string {
 func (f FloatString) Raw() float64 {
 	return float64(f)
 }
+
+// IsZero reports whether f is zero
+func (f FloatString) IsZero() bool {
+	return f == 0
+}
+
+// Cmp compares f and other and returns -1 if f < other, 0 if they are equal
+// and +1 if f > other
+func (f FloatString) Cmp(other FloatString) int {
+	switch {
+	case f < other:
+		return -1
+	case f > other:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Add returns f + other. The arithmetic methods work on the shortest decimal
+// form of each operand, as it appears on the wire, so 0.1 + 0.2 is 0.3
+// rather than 0.30000000000000004. Only the final result is rounded to the
+// nearest float64. NaN and infinite operands use plain float arithmetic.
+func (f FloatString) Add(other FloatString) FloatString {
+	x, y, ok := rats(f, other)
+	if !ok {
+		return f + other
+	}
+	return fromRat(x.Add(x, y))
+}
+
+// Sub returns f - other, computed as described on Add
+func (f FloatString) Sub(other FloatString) FloatString {
+	x, y, ok := rats(f, other)
+	if !ok {
+		return f - other
+	}
+	return fromRat(x.Sub(x, y))
+}
+
+// Mul returns f * other, computed as described on Add
+func (f FloatString) Mul(other FloatString) FloatString {
+	x, y, ok := rats(f, other)
+	if !ok {
+		return f * other
+	}
+	return fromRat(x.Mul(x, y))
+}
+
+// rats returns the exact values of the shortest decimals that parse to a and
+// b. It reports false if either has no decimal form.
+func rats(a, b FloatString) (*big.Rat, *big.Rat, bool) {
+	x, ok := new(big.Rat).SetString(
+		strconv.FormatFloat(float64(a), 'g', -1, 64),
+	)
+	if !ok {
+		return nil, nil, false
+	}
+	y, ok := new(big.Rat).SetString(
+		strconv.FormatFloat(float64(b), 'g', -1, 64),
+	)
+	if !ok {
+		return nil, nil, false
+	}
+	return x, y, true
+}
+
+func fromRat(r *big.Rat) FloatString {
+	v, _ := r.Float64()
+	return FloatString(v)
+}
@@ -0,0 +1,61 @@
+package types
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFloatStringAddIsDecimal(t *testing.T) {
+	sum := FloatString(0.1).Add(0.2)
+	if sum != 0.3 {
+		t.Fatalf("expected 0.3, got %v", sum.Raw())
+	}
+
+	// Summing notionals one by one must not accumulate float error
+	var total FloatString
+	for range 10 {
+		total = total.Add(0.1)
+	}
+	if total != 1 {
+		t.Fatalf("expected 1, got %v", total.Raw())
+	}
+}
+
+func TestFloatStringSubAndMul(t *testing.T) {
+	tests := []struct {
+		name string
+		got  FloatString
+		want FloatString
+	}{
+		{"sub", FloatString(0.3).Sub(0.1), 0.2},
+		{"sub negative", FloatString(1.1).Sub(2.2), -1.1},
+		{"mul", FloatString(1.1).Mul(1.1), 1.21},
+		{"notional", FloatString(0.0123).Mul(64321.5), 791.15445},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, tt.got)
+		}
+	}
+}
+
+func TestFloatStringNonFinite(t *testing.T) {
+	inf := FloatString(math.Inf(1))
+	if got := inf.Add(1); !math.IsInf(got.Raw(), 1) {
+		t.Errorf("expected +Inf, got %v", got)
+	}
+	if got := FloatString(math.NaN()).Mul(2); !math.IsNaN(got.Raw()) {
+		t.Errorf("expected NaN, got %v", got)
+	}
+}
+
+func TestFloatStringCmpAndIsZero(t *testing.T) {
+	if FloatString(1).Cmp(2) != -1 ||
+		FloatString(2).Cmp(1) != 1 ||
+		FloatString(1.5).Cmp(1.5) != 0 {
+		t.Error("unexpected Cmp result")
+	}
+	if !FloatString(0).IsZero() || FloatString(0.00000001).IsZero() {
+		t.Error("unexpected IsZero result")
+	}
+}
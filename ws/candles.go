@@ -0,0 +1,164 @@
+package ws
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// AggregateCandles builds candles of any interval from a trades feed, such as
+// one from SubscribeTrades, and sends them on out. Candles start on multiples
+// of interval since the Unix epoch, in trade time, and are kept separately for
+// each coin.
+//
+// A candle is sent once the first trade of a later interval arrives, so
+// intervals without trades produce no candle. Trades older than a coin's
+// current candle and trades with an unparseable price or size are skipped.
+// When trades is closed, the candles still open are sent and
+// AggregateCandles returns nil. It returns ctx.Err() if ctx is done first.
+func AggregateCandles(
+	ctx context.Context,
+	trades <-chan TradesMessage,
+	interval time.Duration,
+	out chan<- CandleMessage,
+) error {
+	step := interval.Milliseconds()
+	if step <= 0 {
+		return fmt.Errorf(
+			"candle interval must be at least 1ms, got %s",
+			interval,
+		)
+	}
+	label := intervalLabel(interval)
+	open := make(map[string]*candleBuilder)
+
+	send := func(c *candleBuilder) error {
+		select {
+		case out <- c.message(label):
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case msg, ok := <-trades:
+			if !ok {
+				for _, coin := range slices.Sorted(maps.Keys(open)) {
+					if err := send(open[coin]); err != nil {
+						return err
+					}
+				}
+				return nil
+			}
+
+			for _, trade := range msg.Trades {
+				px, err := strconv.ParseFloat(trade.Px, 64)
+				if err != nil {
+					continue
+				}
+				sz, err := strconv.ParseFloat(trade.Sz, 64)
+				if err != nil {
+					continue
+				}
+				start := trade.Time - trade.Time%step
+
+				c := open[trade.Coin]
+				if c != nil && start < c.start {
+					continue
+				}
+				if c != nil && start > c.start {
+					if err := send(c); err != nil {
+						return err
+					}
+					c = nil
+				}
+				if c == nil {
+					c = newCandleBuilder(trade.Coin, start, trade.Px, px)
+					open[trade.Coin] = c
+				}
+				c.add(trade.Px, px, sz)
+			}
+		}
+	}
+}
+
+// candleBuilder accumulates the trades of one coin in one interval
+type candleBuilder struct {
+	coin   string
+	start  int64
+	open   string
+	close  string
+	high   string
+	low    string
+	highPx float64
+	lowPx  float64
+	volume float64
+}
+
+func newCandleBuilder(
+	coin string,
+	start int64,
+	rawPx string,
+	px float64,
+) *candleBuilder {
+	return &candleBuilder{
+		coin:   coin,
+		start:  start,
+		open:   rawPx,
+		high:   rawPx,
+		low:    rawPx,
+		highPx: px,
+		lowPx:  px,
+	}
+}
+
+func (c *candleBuilder) add(rawPx string, px float64, sz float64) {
+	c.close = rawPx
+	if px > c.highPx {
+		c.high, c.highPx = rawPx, px
+	}
+	if px < c.lowPx {
+		c.low, c.lowPx = rawPx, px
+	}
+	c.volume += sz
+}
+
+func (c *candleBuilder) message(interval string) CandleMessage {
+	return CandleMessage{
+		S: c.coin,
+		I: interval,
+		O: c.open,
+		C: c.close,
+		H: c.high,
+		L: c.low,
+		V: strconv.FormatFloat(c.volume, 'f', -1, 64),
+		T: c.start,
+	}
+}
+
+// intervalLabel formats interval the way Hyperliquid names candle intervals,
+// such as "15m" or "4h", falling back to seconds or milliseconds
+func intervalLabel(interval time.Duration) string {
+	units := []struct {
+		size   time.Duration
+		suffix string
+	}{
+		{24 * time.Hour, "d"},
+		{time.Hour, "h"},
+		{time.Minute, "m"},
+		{time.Second, "s"},
+	}
+	for _, unit := range units {
+		if interval%unit.size == 0 {
+			return fmt.Sprintf("%d%s", interval/unit.size, unit.suffix)
+		}
+	}
+	return fmt.Sprintf("%dms", interval.Milliseconds())
+}
@@ -3,7 +3,6 @@ package ws
 import (
 	"encoding/json"
 	"fmt"
-	"log"
 	"strings"
 )
 
@@ -12,19 +11,23 @@ import (
 func (m *Client) handleMessage(data []byte) {
 	var raw map[string]any
 	if err := json.Unmarshal(data, &raw); err != nil {
-		log.Printf("failed to unmarshal ws message: %v", err)
+		m.logger.Warn("failed to unmarshal websocket message", "err", err)
 		return
 	}
 
 	channel, ok := raw["channel"].(string)
 	if !ok {
-		log.Println("websocket message missing channel field")
+		m.logger.Warn("websocket message missing channel field")
 		return
 	}
 
 	// Handle pong messages
 	if channel == "pong" {
-		log.Println("websocket received pong")
+		m.logger.Debug("websocket received pong")
+		select {
+		case m.pongChan <- struct{}{}:
+		default:
+		}
 		return
 	}
 
@@ -52,15 +55,22 @@ func (m *Client) handleMessage(data []byte) {
 		m.handleUserNonFundingLedgerUpdates(raw)
 	case "webData2":
 		m.handleWebData2(raw)
+	case "notification":
+		m.handleNotification(raw)
 	case "activeAssetCtx", "activeSpotAssetCtx":
 		m.handleActiveAssetCtx(raw, channel)
 	case "activeAssetData":
 		m.handleActiveAssetData(raw)
+	case "explorerBlock":
+		m.handleExplorerBlock(raw)
+	case "explorerTxs":
+		m.handleExplorerTxs(raw)
 	case "subscriptionResponse":
-		// Don't care about these
-		break
+		m.handleSubscriptionResponse(raw)
+	case "post":
+		m.handlePost(raw)
 	default:
-		log.Printf("websocket unknown channel: %s", channel)
+		m.logger.Warn("websocket unknown channel", "channel", channel)
 	}
 }
 
@@ -75,7 +85,11 @@ func (m *Client) handleAllMids(raw map[string]any) {
 	msgBytes, _ := json.Marshal(dataRaw)
 	var msg AllMidsMessage
 	if err := json.Unmarshal(msgBytes, &msg); err != nil {
-		log.Printf("failed to unmarshal allMids message: %v", err)
+		m.logger.Warn(
+			"failed to unmarshal websocket message",
+			"channel", "allMids",
+			"err", err,
+		)
 		return
 	}
 
@@ -91,7 +105,11 @@ func (m *Client) handleL2Book(raw map[string]any) {
 	msgBytes, _ := json.Marshal(dataRaw)
 	var msg L2BookMessage
 	if err := json.Unmarshal(msgBytes, &msg); err != nil {
-		log.Printf("failed to unmarshal l2Book message: %v", err)
+		m.logger.Warn(
+			"failed to unmarshal websocket message",
+			"channel", "l2Book",
+			"err", err,
+		)
 		return
 	}
 
@@ -109,7 +127,11 @@ func (m *Client) handleTrades(raw map[string]any) {
 	var trades []Trade
 	dataBytes, _ := json.Marshal(dataRaw)
 	if err := json.Unmarshal(dataBytes, &trades); err != nil {
-		log.Printf("failed to unmarshal trades message: %v", err)
+		m.logger.Warn(
+			"failed to unmarshal websocket message",
+			"channel", "trades",
+			"err", err,
+		)
 		return
 	}
 
@@ -131,7 +153,11 @@ func (m *Client) handleUserEvents(raw map[string]any) {
 	msgBytes, _ := json.Marshal(dataRaw)
 	var msg UserEventsMessage
 	if err := json.Unmarshal(msgBytes, &msg); err != nil {
-		log.Printf("failed to unmarshal userEvents message: %v", err)
+		m.logger.Warn(
+			"failed to unmarshal websocket message",
+			"channel", "userEvents",
+			"err", err,
+		)
 		return
 	}
 
@@ -147,7 +173,11 @@ func (m *Client) handleUserFills(raw map[string]any) {
 	msgBytes, _ := json.Marshal(dataRaw)
 	var msg UserFillsMessage
 	if err := json.Unmarshal(msgBytes, &msg); err != nil {
-		log.Printf("failed to unmarshal userFills message: %v", err)
+		m.logger.Warn(
+			"failed to unmarshal websocket message",
+			"channel", "userFills",
+			"err", err,
+		)
 		return
 	}
 
@@ -164,7 +194,11 @@ func (m *Client) handleBbo(raw map[string]any) {
 	msgBytes, _ := json.Marshal(dataRaw)
 	var msg BboMessage
 	if err := json.Unmarshal(msgBytes, &msg); err != nil {
-		log.Printf("failed to unmarshal bbo message: %v", err)
+		m.logger.Warn(
+			"failed to unmarshal websocket message",
+			"channel", "bbo",
+			"err", err,
+		)
 		return
 	}
 
@@ -181,7 +215,11 @@ func (m *Client) handleCandle(raw map[string]any) {
 	msgBytes, _ := json.Marshal(dataRaw)
 	var msg CandleMessage
 	if err := json.Unmarshal(msgBytes, &msg); err != nil {
-		log.Printf("failed to unmarshal candle message: %v", err)
+		m.logger.Warn(
+			"failed to unmarshal websocket message",
+			"channel", "candle",
+			"err", err,
+		)
 		return
 	}
 
@@ -195,7 +233,17 @@ func (m *Client) handleOrderUpdates(raw map[string]any, identifier string) {
 		return
 	}
 
-	msg := OrderUpdatesMessage(dataRaw.(map[string]any))
+	msgBytes, _ := json.Marshal(dataRaw)
+	var msg OrderUpdatesMessage
+	if err := json.Unmarshal(msgBytes, &msg); err != nil {
+		m.logger.Warn(
+			"failed to unmarshal websocket message",
+			"channel", "orderUpdates",
+			"err", err,
+		)
+		return
+	}
+
 	routeMessage(m, identifier, msg)
 }
 
@@ -250,6 +298,26 @@ func (m *Client) handleWebData2(raw map[string]any) {
 	}
 }
 
+func (m *Client) handleNotification(raw map[string]any) {
+	dataRaw, ok := raw["data"]
+	if !ok {
+		return
+	}
+
+	msgBytes, _ := json.Marshal(dataRaw)
+	var msg NotificationMessage
+	if err := json.Unmarshal(msgBytes, &msg); err != nil {
+		m.logger.Warn(
+			"failed to unmarshal websocket message",
+			"channel", "notification",
+			"err", err,
+		)
+		return
+	}
+
+	routeMessage(m, "notification", msg)
+}
+
 func (m *Client) handleActiveAssetCtx(raw map[string]any, channel string) {
 	dataRaw, ok := raw["data"]
 	if !ok {
@@ -258,12 +326,15 @@ func (m *Client) handleActiveAssetCtx(raw map[string]any, channel string) {
 
 	msgBytes, _ := json.Marshal(dataRaw)
 
+	// Perp and spot subscribers share an identifier, and routeMessage only
+	// delivers to those expecting the decoded message type
 	if channel == "activeSpotAssetCtx" {
 		var msg ActiveSpotAssetCtxMessage
 		if err := json.Unmarshal(msgBytes, &msg); err != nil {
-			log.Printf(
-				"failed to unmarshal activeSpotAssetCtx message: %v",
-				err,
+			m.logger.Warn(
+				"failed to unmarshal websocket message",
+				"channel", "activeSpotAssetCtx",
+				"err", err,
 			)
 			return
 		}
@@ -275,7 +346,11 @@ func (m *Client) handleActiveAssetCtx(raw map[string]any, channel string) {
 	} else {
 		var msg ActiveAssetCtxMessage
 		if err := json.Unmarshal(msgBytes, &msg); err != nil {
-			log.Printf("failed to unmarshal activeAssetCtx message: %v", err)
+			m.logger.Warn(
+				"failed to unmarshal websocket message",
+				"channel", "activeAssetCtx",
+				"err", err,
+			)
 			return
 		}
 		identifier := fmt.Sprintf("activeAssetCtx:%s", strings.ToLower(msg.Coin))
@@ -292,7 +367,11 @@ func (m *Client) handleActiveAssetData(raw map[string]any) {
 	msgBytes, _ := json.Marshal(dataRaw)
 	var msg ActiveAssetDataMessage
 	if err := json.Unmarshal(msgBytes, &msg); err != nil {
-		log.Printf("failed to unmarshal activeAssetData message: %v", err)
+		m.logger.Warn(
+			"failed to unmarshal websocket message",
+			"channel", "activeAssetData",
+			"err", err,
+		)
 		return
 	}
 
@@ -304,17 +383,75 @@ func (m *Client) handleActiveAssetData(raw map[string]any) {
 	routeMessage(m, identifier, msg)
 }
 
+// handleSubscriptionResponse marks the subscriptions matching a subscribe
+// acknowledgement as acknowledged
+func (m *Client) handleSubscriptionResponse(raw map[string]any) {
+	data, ok := raw["data"].(map[string]any)
+	if !ok {
+		return
+	}
+
+	if method, _ := data["method"].(string); method != "subscribe" {
+		return
+	}
+
+	payload, ok := data["subscription"].(map[string]any)
+	if !ok {
+		return
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, subscriptions := range m.activeSubscriptions {
+		for _, sub := range subscriptions {
+			if matchesSubscriptionPayload(sub.sub, payload) {
+				sub.ack()
+			}
+		}
+	}
+}
+
+// matchesSubscriptionPayload reports whether an acknowledged subscription
+// payload refers to sub. The server may echo extra fields, so only the
+// fields sent by the client are compared.
+func matchesSubscriptionPayload(
+	sub SubscriptionType,
+	payload map[string]any,
+) bool {
+	expected, ok := sub.subscriptionPayload().(map[string]any)
+	if !ok {
+		return false
+	}
+
+	for key, value := range expected {
+		got, ok := payload[key]
+		if !ok || !strings.EqualFold(fmt.Sprint(value), fmt.Sprint(got)) {
+			return false
+		}
+	}
+
+	return true
+}
+
 // routeMessage routes a message to all subscriptions registered for that
-// identifier
+// identifier whose channel carries messages of type T. Subscriptions of
+// another type can share an identifier when the server keeps one
+// subscription for both, as with perp and spot asset contexts.
+//
+// Sends never block: if a subscription's buffer is full the message is
+// dropped for that subscription only and counted in its dropped counter.
 func routeMessage[T any](m *Client, identifier string, msg T) {
+	// Hold the read lock while sending so unsubscribe cannot close a channel
+	// underneath us. Sends are non-blocking, so this is never held for long.
 	m.mu.RLock()
+	defer m.mu.RUnlock()
 	subscriptions := m.activeSubscriptions[identifier]
-	m.mu.RUnlock()
 
 	if len(subscriptions) == 0 {
-		log.Printf(
-			"websocket message from unexpected subscription: %s",
-			identifier,
+		m.logger.Warn(
+			"websocket message from unexpected subscription",
+			"subscription", identifier,
 		)
 		return
 	}
@@ -322,15 +459,59 @@ func routeMessage[T any](m *Client, identifier string, msg T) {
 	for _, sub := range subscriptions {
 		ch, ok := sub.internalChan.(chan T)
 		if !ok {
-			panic(
-				fmt.Sprintf(
-					"subscription internal channel has wrong type for %s (id: %d)",
-					identifier,
-					sub.id,
-				),
-			)
+			continue
+		}
+		sub.received.Store(true)
+
+		select {
+		case ch <- msg:
+		default:
+			if sub.dropped.Add(1) == 1 {
+				m.logger.Warn(
+					"websocket subscriber is falling behind, dropping messages",
+					"subscription", identifier,
+				)
+			}
 		}
+	}
+}
+
+func (m *Client) handleExplorerBlock(raw map[string]any) {
+	dataRaw, ok := raw["data"]
+	if !ok {
+		return
+	}
+
+	msgBytes, _ := json.Marshal(dataRaw)
+	var msg ExplorerBlockMessage
+	if err := json.Unmarshal(msgBytes, &msg); err != nil {
+		m.logger.Warn(
+			"failed to unmarshal websocket message",
+			"channel", "explorerBlock",
+			"err", err,
+		)
+		return
+	}
+
+	routeMessage(m, "explorerBlock", msg)
+}
 
-		ch <- msg
+func (m *Client) handleExplorerTxs(raw map[string]any) {
+	dataRaw, ok := raw["data"]
+	if !ok {
+		return
+	}
+
+	msgBytes, _ := json.Marshal(dataRaw)
+	var msg ExplorerTxsMessage
+	if err := json.Unmarshal(msgBytes, &msg); err != nil {
+		m.logger.Warn(
+			"failed to unmarshal websocket message",
+			"channel", "explorerTxs",
+			"err", err,
+		)
+		return
 	}
+
+	routeMessage(m, "explorerTxs", msg)
 }
@@ -0,0 +1,118 @@
+package ws
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// OrderBook keeps a live copy of a coin's level 2 book from the l2Book feed.
+// The feed sends a full snapshot on every update, so each snapshot replaces
+// the whole book. Levels are kept in the order the server sends them: bids
+// from highest to lowest price and asks from lowest to highest. All methods
+// are safe for concurrent use.
+type OrderBook struct {
+	sub  Subscription
+	done chan struct{}
+
+	mu      sync.RWMutex
+	book    L2BookMessage
+	hasBook bool
+
+	closeOnce sync.Once
+}
+
+// NewOrderBook subscribes to the l2Book feed for coin and keeps the returned
+// OrderBook up to date until Close is called. The book is empty until the
+// first snapshot arrives. ctx bounds only the subscribe request.
+func NewOrderBook(
+	ctx context.Context,
+	client ClientInterface,
+	coin string,
+) (*OrderBook, error) {
+	ch := make(chan L2BookMessage, 1)
+	sub, err := client.SubscribeL2Book(ctx, coin, ch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to subscribe to l2Book: %w", err)
+	}
+
+	b := &OrderBook{
+		sub:  sub,
+		done: make(chan struct{}),
+	}
+
+	go func() {
+		for {
+			select {
+			case <-b.done:
+				return
+			case msg := <-ch:
+				b.update(msg)
+			}
+		}
+	}()
+
+	return b, nil
+}
+
+// update replaces the book with msg unless msg is older than the current book
+func (b *OrderBook) update(msg L2BookMessage) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.hasBook && msg.Time < b.book.Time {
+		return
+	}
+	b.book = msg
+	b.hasBook = true
+}
+
+// BestBid returns the highest bid. ok is false if there are no bids or no
+// snapshot has arrived yet.
+func (b *OrderBook) BestBid() (level L2Level, ok bool) {
+	return b.best(0)
+}
+
+// BestAsk returns the lowest ask. ok is false if there are no asks or no
+// snapshot has arrived yet.
+func (b *OrderBook) BestAsk() (level L2Level, ok bool) {
+	return b.best(1)
+}
+
+func (b *OrderBook) best(side int) (L2Level, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if len(b.book.Levels[side]) == 0 {
+		return L2Level{}, false
+	}
+	return b.book.Levels[side][0], true
+}
+
+// Snapshot returns a copy of the latest book. ok is false if no snapshot has
+// arrived yet.
+func (b *OrderBook) Snapshot() (book L2BookMessage, ok bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	book = b.book
+	book.Levels = [2][]L2Level{
+		slices.Clone(b.book.Levels[0]),
+		slices.Clone(b.book.Levels[1]),
+	}
+	return book, b.hasBook
+}
+
+// Err returns the error channel of the underlying subscription
+func (b *OrderBook) Err() <-chan error {
+	return b.sub.Err()
+}
+
+// Close unsubscribes from the l2Book feed. The last snapshot stays readable.
+func (b *OrderBook) Close() {
+	b.closeOnce.Do(func() {
+		b.sub.Unsubscribe()
+		close(b.done)
+	})
+}
@@ -0,0 +1,152 @@
+package ws
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/banky/go-hyperliquid/logging"
+
+	"github.com/coder/websocket"
+)
+
+// Request types accepted by Post
+const (
+	PostTypeInfo   = logging.RequestTypeInfo
+	PostTypeAction = logging.RequestTypeAction
+)
+
+// postResponse is the data of a "post" channel message
+type postResponse struct {
+	ID       int64 `json:"id"`
+	Response struct {
+		Type    string          `json:"type"`
+		Payload json.RawMessage `json:"payload"`
+	} `json:"response"`
+
+	// err is set instead of a response when the connection carrying the
+	// request dropped
+	err error
+}
+
+// Post sends an info or action request over the websocket and waits for the
+// matching response. requestType is PostTypeInfo or PostTypeAction and
+// payload is the body that would otherwise be sent to the /info or /exchange
+// REST endpoint. The raw response payload is returned as-is.
+//
+// Post returns ErrNotConnected without sending anything if the Client has no
+// open connection. If the connection drops or the Client is closed before
+// the response arrives, Post returns ErrConnectionLost, since the server
+// never answers a request sent on a dead connection. The request may or may
+// not have been processed.
+func (m *Client) Post(
+	ctx context.Context,
+	requestType string,
+	payload any,
+) (raw json.RawMessage, err error) {
+	respChan := make(chan postResponse, 1)
+
+	m.mu.Lock()
+	conn := m.conn
+	if conn == nil {
+		m.mu.Unlock()
+		return nil, ErrNotConnected
+	}
+	m.postIDCounter++
+	id := m.postIDCounter
+	m.pendingPosts[id] = respChan
+	m.mu.Unlock()
+
+	start := time.Now()
+	defer func() { logging.Observe(m.observer, requestType, start, err) }()
+
+	defer func() {
+		m.mu.Lock()
+		delete(m.pendingPosts, id)
+		m.mu.Unlock()
+	}()
+
+	msg := map[string]any{
+		"method": "post",
+		"id":     id,
+		"request": map[string]any{
+			"type":    requestType,
+			"payload": payload,
+		},
+	}
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal post request: %w", err)
+	}
+
+	if err := conn.Write(ctx, websocket.MessageText, data); err != nil {
+		return nil, fmt.Errorf("failed to send post request: %w", err)
+	}
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-m.stopChan:
+		return nil, ErrConnectionLost
+	case resp := <-respChan:
+		if resp.err != nil {
+			return nil, resp.err
+		}
+		if resp.Response.Type == "error" {
+			var reason string
+			if err := json.Unmarshal(resp.Response.Payload, &reason); err != nil {
+				reason = string(resp.Response.Payload)
+			}
+			return nil, fmt.Errorf("websocket post error: %s", reason)
+		}
+		return resp.Response.Payload, nil
+	}
+}
+
+// failPendingPosts delivers err to every Post waiting for a response. The
+// caller must hold m.mu.
+func (m *Client) failPendingPosts(err error) {
+	for id, respChan := range m.pendingPosts {
+		// respChan is buffered and each id gets exactly one response
+		select {
+		case respChan <- postResponse{ID: id, err: err}:
+		default:
+		}
+		delete(m.pendingPosts, id)
+	}
+}
+
+// handlePost delivers a post response to the waiting Post call
+func (m *Client) handlePost(raw map[string]any) {
+	dataRaw, ok := raw["data"]
+	if !ok {
+		return
+	}
+
+	msgBytes, _ := json.Marshal(dataRaw)
+	var resp postResponse
+	if err := json.Unmarshal(msgBytes, &resp); err != nil {
+		m.logger.Warn(
+			"failed to unmarshal websocket message",
+			"channel", "post",
+			"err", err,
+		)
+		return
+	}
+
+	m.mu.RLock()
+	respChan, ok := m.pendingPosts[resp.ID]
+	m.mu.RUnlock()
+
+	if !ok {
+		m.logger.Warn("websocket post response for unknown id", "id", resp.ID)
+		return
+	}
+
+	// respChan is buffered and each id gets exactly one response
+	select {
+	case respChan <- resp:
+	default:
+	}
+}
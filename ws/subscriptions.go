@@ -4,10 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
 	"strings"
 	"time"
 
+	"github.com/banky/go-hyperliquid/logging"
 	"github.com/coder/websocket"
 	"github.com/ethereum/go-ethereum/common"
 )
@@ -114,6 +114,16 @@ func (m *Client) SubscribeWebData2(
 	return newWSSubscription(ctx, m, WebData2Subscription{User: user}, ch)
 }
 
+// SubscribeNotification subscribes to notifications for a user, such as
+// liquidation warnings
+func (m *Client) SubscribeNotification(
+	ctx context.Context,
+	user common.Address,
+	ch chan<- NotificationMessage,
+) (Subscription, error) {
+	return newWSSubscription(ctx, m, NotificationSubscription{User: user}, ch)
+}
+
 // SubscribeBbo subscribes to best bid/offer data
 func (m *Client) SubscribeBbo(
 	ctx context.Context,
@@ -132,6 +142,21 @@ func (m *Client) SubscribeActiveAssetCtx(
 	return newWSSubscription(ctx, m, ActiveAssetCtxSubscription{Coin: coin}, ch)
 }
 
+// SubscribeActiveSpotAssetCtx subscribes to active asset context for a spot
+// pair
+func (m *Client) SubscribeActiveSpotAssetCtx(
+	ctx context.Context,
+	coin string,
+	ch chan<- ActiveSpotAssetCtxMessage,
+) (Subscription, error) {
+	return newWSSubscription(
+		ctx,
+		m,
+		ActiveSpotAssetCtxSubscription{Coin: coin},
+		ch,
+	)
+}
+
 // SubscribeActiveAssetData subscribes to active asset data
 func (m *Client) SubscribeActiveAssetData(
 	ctx context.Context,
@@ -147,22 +172,44 @@ func (m *Client) SubscribeActiveAssetData(
 	)
 }
 
-// newWSSubscription sets up a websocket subscription, wires it to ctx,
-// and returns a Subscription. It centralizes error-channel and goroutine logic.
+// SubscribeExplorerBlocks subscribes to new L1 blocks. The client must be
+// connected to the RPC websocket.
+func (m *Client) SubscribeExplorerBlocks(
+	ctx context.Context,
+	ch chan<- ExplorerBlockMessage,
+) (Subscription, error) {
+	return newWSSubscription(ctx, m, ExplorerBlockSubscription{}, ch)
+}
+
+// SubscribeExplorerTxs subscribes to transactions in new L1 blocks. The client
+// must be connected to the RPC websocket.
+func (m *Client) SubscribeExplorerTxs(
+	ctx context.Context,
+	ch chan<- ExplorerTxsMessage,
+) (Subscription, error) {
+	return newWSSubscription(ctx, m, ExplorerTxsSubscription{}, ch)
+}
+
+// newWSSubscription sets up a websocket subscription and returns a
+// Subscription. It centralizes error-channel and goroutine logic. ctx bounds
+// only sending the subscribe request; the subscription lives until
+// Unsubscribe.
 func newWSSubscription[T any](
 	ctx context.Context,
 	m *Client,
 	sub SubscriptionType,
 	ch chan<- T,
 ) (Subscription, error) {
-	// Derived context that represents the lifetime of this subscription.
-	subCtx, cancel := context.WithCancel(ctx)
+	// Context that represents the lifetime of this subscription. It keeps
+	// ctx's values but is only cancelled by Unsubscribe.
+	subCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
 
 	errChan := make(chan error, 1)
 	id := m.nextSubscriptionID()
 
 	// Register with the remote WS + internal maps.
-	if err := subscribe(m, sub, ch, id); err != nil {
+	cs, err := subscribe(ctx, m, sub, ch, id)
+	if err != nil {
 		cancel()
 		close(errChan)
 		return nil, err
@@ -171,6 +218,11 @@ func newWSSubscription[T any](
 	s := &subscription{
 		cancel:  cancel,
 		errChan: errChan,
+		cs:      cs,
+	}
+
+	if m.firstMessageTimeout > 0 {
+		go watchFirstMessage(subCtx, s, cs, m.firstMessageTimeout, m.logger)
 	}
 
 	// Single owner of errChan and of unsubscribeInternal cleanup.
@@ -178,12 +230,8 @@ func newWSSubscription[T any](
 		<-subCtx.Done()
 
 		// Best-effort send of the terminal error; non-blocking.
-		select {
-		case errChan <- subCtx.Err():
-		default:
-		}
-
-		close(errChan)
+		s.sendErr(subCtx.Err())
+		s.closeErr()
 
 		// Remove from client's subscription map.
 		unsubscribeInternal[T](m, sub, id)
@@ -192,6 +240,44 @@ func newWSSubscription[T any](
 	return s, nil
 }
 
+// watchFirstMessage reports ErrFirstMessageTimeout if the subscription has not
+// received any data within timeout of the server acknowledging it
+func watchFirstMessage(
+	ctx context.Context,
+	s *subscription,
+	cs *channelSubscription,
+	timeout time.Duration,
+	logger logging.Logger,
+) {
+	select {
+	case <-ctx.Done():
+		return
+	case <-cs.acked:
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return
+	case <-timer.C:
+	}
+
+	if cs.received.Load() {
+		return
+	}
+
+	err := fmt.Errorf(
+		"%w: %s after %s",
+		ErrFirstMessageTimeout,
+		cs.sub.identifier(),
+		timeout,
+	)
+	logger.Warn("websocket subscription warning", "err", err)
+	s.sendErr(err)
+}
+
 // nextSubscriptionID increments and returns a unique subscription ID.
 func (m *Client) nextSubscriptionID() int64 {
 	m.mu.Lock()
@@ -200,56 +286,85 @@ func (m *Client) nextSubscriptionID() int64 {
 	return m.subscriptionIDCounter
 }
 
+// subscribe registers a local subscriber and, if it is the first for its
+// identifier, sends the subscribe request to the server. It fails without
+// registering anything if ctx is done before the request is sent. Other send
+// errors are left to the reconnect logic, which replays every subscription.
 func subscribe[T any](
+	ctx context.Context,
 	m *Client,
 	sub SubscriptionType,
 	subscriberChan chan<- T,
 	id int64,
-) error {
+) (*channelSubscription, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	identifier := sub.identifier()
-	internalChan := make(chan T)
 
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
 	// Check for duplicate restrictions
-	if identifier == "userEvents" || identifier == "orderUpdates" {
+	if identifier == "userEvents" || identifier == "orderUpdates" ||
+		identifier == "notification" {
 		if len(m.activeSubscriptions[identifier]) != 0 {
-			return fmt.Errorf(
+			return nil, fmt.Errorf(
 				"cannot subscribe to %s multiple times",
 				identifier,
 			)
 		}
 	}
 
-	// Add to active subscriptions
-	m.activeSubscriptions[identifier] = append(
-		m.activeSubscriptions[identifier],
-		&channelSubscription{
-			internalChan: internalChan,
-			id:           id,
-		},
-	)
-
-	// Launch delivery goroutine that forwards from internal channel to
-	// subscriber channel
-	go deliveryLoop(internalChan, subscriberChan)
-
-	// Send subscription message to server (if connected)
-	if m.conn != nil {
+	// Send subscription message to server (if connected). If the server
+	// already streams this identifier for an earlier subscriber, that
+	// subscription is shared instead.
+	existing := m.activeSubscriptions[identifier]
+	if len(existing) == 0 && m.conn != nil {
 		msg := map[string]any{
 			"method":       "subscribe",
 			"subscription": sub.subscriptionPayload(),
 		}
 		data, _ := json.Marshal(msg)
 
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-		defer cancel()
-		m.conn.Write(ctx, websocket.MessageText, data)
+		writeCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		err := m.conn.Write(writeCtx, websocket.MessageText, data)
+		cancel()
+		if ctxErr := ctx.Err(); err != nil && ctxErr != nil {
+			return nil, fmt.Errorf(
+				"failed to send subscribe request: %w",
+				ctxErr,
+			)
+		}
+	}
+
+	internalChan := make(chan T, m.bufferSize)
+	cs := &channelSubscription{
+		internalChan: internalChan,
+		id:           id,
+		sub:          sub,
+		acked:        make(chan struct{}),
 	}
 
-	return nil
+	// Add to active subscriptions
+	m.activeSubscriptions[identifier] = append(existing, cs)
+
+	// Launch delivery goroutine that forwards from internal channel to
+	// subscriber channel
+	go deliveryLoop(internalChan, subscriberChan)
+
+	// A shared subscription's pending acknowledgement, if any, covers every
+	// local subscriber
+	if len(existing) != 0 {
+		select {
+		case <-existing[0].acked:
+			cs.ack()
+		default:
+		}
+	}
 
+	return cs, nil
 }
 
 func deliveryLoop[T any](
@@ -295,10 +410,15 @@ func unsubscribeInternal[T any](
 	if internalChan != nil {
 		close(internalChan)
 	}
+	// Drop the closed channel from the map before releasing the lock so
+	// routeMessage never sends on it
+	m.activeSubscriptions[identifier] = newActiveSubscriptions
 
-	// If no more subscriptions for this identifier, send unsubscribe (if
-	// connected)
-	if len(newActiveSubscriptions) == 0 && m.conn != nil {
+	// Only the last local subscriber sends unsubscribe (if connected), since
+	// the server keeps a single subscription per identifier. The frame is
+	// written under the lock, like subscribe frames, so a new subscribe for
+	// the same identifier always reaches the server after it.
+	if conn := m.conn; len(newActiveSubscriptions) == 0 && conn != nil {
 		msg := map[string]any{
 			"method":       "unsubscribe",
 			"subscription": sub.subscriptionPayload(),
@@ -306,9 +426,8 @@ func unsubscribeInternal[T any](
 		data, _ := json.Marshal(msg)
 
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-		m.mu.Unlock()
 		defer cancel()
-		err := m.conn.Write(ctx, websocket.MessageText, data)
+		err := conn.Write(ctx, websocket.MessageText, data)
 		if err != nil {
 			// Ignore errors that are clearly “connection is gone”
 			if strings.Contains(
@@ -316,15 +435,16 @@ func unsubscribeInternal[T any](
 				"use of closed network connection",
 			) ||
 				websocket.CloseStatus(err) == websocket.StatusNormalClosure {
-				// maybe log at debug-level if you have such a thing
+				m.logger.Debug(
+					"unsubscribe not sent, connection is closed",
+					"err", err,
+				)
 			} else {
-				log.Printf("error sending unsubscribe message: %v\n", err)
+				m.logger.Warn("error sending unsubscribe message", "err", err)
 			}
 		}
-		m.mu.Lock()
 	}
 
-	m.activeSubscriptions[identifier] = newActiveSubscriptions
 	m.mu.Unlock()
 
 	return internalChan != nil
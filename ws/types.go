@@ -1,9 +1,11 @@
 package ws
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 
+	"github.com/banky/go-hyperliquid/internal/utils"
 	"github.com/ethereum/go-ethereum/common"
 )
 
@@ -151,6 +153,19 @@ func (s WebData2Subscription) subscriptionPayload() any {
 	return map[string]any{"type": "webData2", "user": s.User}
 }
 
+// NotificationSubscription subscribes to notifications for a user, such as
+// liquidation warnings. Notifications do not name the user, so only one
+// notification subscription can be active per connection.
+type NotificationSubscription struct {
+	User common.Address
+}
+
+func (s NotificationSubscription) channelName() string { return "notification" }
+func (s NotificationSubscription) identifier() string  { return "notification" }
+func (s NotificationSubscription) subscriptionPayload() any {
+	return map[string]any{"type": "notification", "user": s.User}
+}
+
 // BboSubscription subscribes to best bid/offer for a coin
 type BboSubscription struct {
 	Coin string
@@ -176,6 +191,25 @@ func (s ActiveAssetCtxSubscription) subscriptionPayload() any {
 	return map[string]any{"type": "activeAssetCtx", "coin": s.Coin}
 }
 
+// ActiveSpotAssetCtxSubscription subscribes to active asset context for a spot
+// pair. The server uses the same subscription type as perps but replies on
+// the activeSpotAssetCtx channel with a spot shaped context. It shares the
+// perp identifier, since the server keeps a single subscription for both, and
+// messages are dispatched to each subscriber by their type.
+type ActiveSpotAssetCtxSubscription struct {
+	Coin string
+}
+
+func (s ActiveSpotAssetCtxSubscription) channelName() string {
+	return "activeSpotAssetCtx"
+}
+func (s ActiveSpotAssetCtxSubscription) identifier() string {
+	return fmt.Sprintf("activeAssetCtx:%s", strings.ToLower(s.Coin))
+}
+func (s ActiveSpotAssetCtxSubscription) subscriptionPayload() any {
+	return map[string]any{"type": "activeAssetCtx", "coin": s.Coin}
+}
+
 // ActiveAssetDataSubscription subscribes to active asset data for a user and
 // coin
 type ActiveAssetDataSubscription struct {
@@ -199,6 +233,27 @@ func (s ActiveAssetDataSubscription) subscriptionPayload() any {
 	}
 }
 
+// ExplorerBlockSubscription subscribes to new L1 blocks. The explorer feeds
+// are served by the RPC websocket (wss://rpc.hyperliquid.xyz/ws) rather than
+// the API websocket.
+type ExplorerBlockSubscription struct{}
+
+func (s ExplorerBlockSubscription) channelName() string { return "explorerBlock" }
+func (s ExplorerBlockSubscription) identifier() string  { return "explorerBlock" }
+func (s ExplorerBlockSubscription) subscriptionPayload() any {
+	return map[string]any{"type": "explorerBlock"}
+}
+
+// ExplorerTxsSubscription subscribes to transactions included in new L1
+// blocks. Like ExplorerBlockSubscription it is served by the RPC websocket.
+type ExplorerTxsSubscription struct{}
+
+func (s ExplorerTxsSubscription) channelName() string { return "explorerTxs" }
+func (s ExplorerTxsSubscription) identifier() string  { return "explorerTxs" }
+func (s ExplorerTxsSubscription) subscriptionPayload() any {
+	return map[string]any{"type": "explorerTxs"}
+}
+
 // ===== Message Types =====
 
 // L2Level represents a single level in the order book
@@ -213,6 +268,19 @@ type AllMidsMessage struct {
 	Mids map[string]string `json:"mids"`
 }
 
+// Floats parses every mid-price in the message
+func (m AllMidsMessage) Floats() (map[string]float64, error) {
+	mids := make(map[string]float64, len(m.Mids))
+	for coin, px := range m.Mids {
+		f, err := utils.StringToFloat(px)
+		if err != nil {
+			return nil, fmt.Errorf("invalid mid for %s: %w", coin, err)
+		}
+		mids[coin] = f
+	}
+	return mids, nil
+}
+
 // L2BookMessage contains level 2 order book data
 type L2BookMessage struct {
 	Coin   string       `json:"coin"`
@@ -225,7 +293,7 @@ type Trade struct {
 	Coin string `json:"coin"`
 	Side string `json:"side"` // "A" or "B"
 	Px   string `json:"px"`
-	Sz   int64  `json:"sz"`
+	Sz   string `json:"sz"`
 	Hash string `json:"hash"`
 	Time int64  `json:"time"`
 }
@@ -253,9 +321,38 @@ type Fill struct {
 	FeeToken      string `json:"feeToken"`
 }
 
-// UserEventsMessage contains user event data (fills, etc.)
+// Funding represents a funding payment delivered on the userEvents feed
+type Funding struct {
+	Time        int64  `json:"time"`
+	Coin        string `json:"coin"`
+	Usdc        string `json:"usdc"`
+	Szi         string `json:"szi"`
+	FundingRate string `json:"fundingRate"`
+}
+
+// Liquidation represents a liquidation event delivered on the userEvents feed
+type Liquidation struct {
+	Lid                    int64  `json:"lid"`
+	Liquidator             string `json:"liquidator"`
+	LiquidatedUser         string `json:"liquidated_user"`
+	LiquidatedNtlPos       string `json:"liquidated_ntl_pos"`
+	LiquidatedAccountValue string `json:"liquidated_account_value"`
+}
+
+// NonUserCancel represents an order cancelled by the system rather than the
+// user
+type NonUserCancel struct {
+	Coin string `json:"coin"`
+	Oid  int64  `json:"oid"`
+}
+
+// UserEventsMessage contains user event data. Each message carries exactly
+// one kind of event, so only one of the fields will be populated.
 type UserEventsMessage struct {
-	Fills []Fill `json:"fills"`
+	Fills         []Fill          `json:"fills,omitempty"`
+	Funding       *Funding        `json:"funding,omitempty"`
+	Liquidation   *Liquidation    `json:"liquidation,omitempty"`
+	NonUserCancel []NonUserCancel `json:"nonUserCancel,omitempty"`
 }
 
 // UserFillsMessage contains user fill data
@@ -291,8 +388,28 @@ type CandleMessage struct {
 	T int64  `json:"t"` // Timestamp
 }
 
-// OrderUpdatesMessage contains order update data
-type OrderUpdatesMessage map[string]any
+// BasicOrder is the order carried by an order update
+type BasicOrder struct {
+	Coin      string  `json:"coin"`
+	Side      string  `json:"side"` // "B" for bid, "A" for ask
+	LimitPx   string  `json:"limitPx"`
+	Sz        string  `json:"sz"`
+	Oid       int64   `json:"oid"`
+	Timestamp int64   `json:"timestamp"`
+	OrigSz    string  `json:"origSz"`
+	Cloid     *string `json:"cloid,omitempty"`
+}
+
+// OrderUpdate reports a change in the status of one of the user's orders,
+// such as "open", "filled" or "canceled"
+type OrderUpdate struct {
+	Order           BasicOrder `json:"order"`
+	Status          string     `json:"status"`
+	StatusTimestamp int64      `json:"statusTimestamp"`
+}
+
+// OrderUpdatesMessage contains the order updates delivered in one message
+type OrderUpdatesMessage []OrderUpdate
 
 // UserFundingsMessage contains user funding data
 type UserFundingsMessage map[string]any
@@ -303,6 +420,12 @@ type UserNonFundingLedgerUpdatesMessage map[string]any
 // WebData2Message contains web data
 type WebData2Message map[string]any
 
+// NotificationMessage contains a notification pushed to a user, such as a
+// liquidation warning
+type NotificationMessage struct {
+	Notification string `json:"notification"`
+}
+
 // PerpAssetCtx contains perp market context
 type PerpAssetCtx struct {
 	Funding      string     `json:"funding"`
@@ -356,5 +479,32 @@ type ActiveAssetDataMessage struct {
 	MarkPx           string    `json:"markPx"`
 }
 
+// ExplorerBlock is the summary of a single L1 block
+type ExplorerBlock struct {
+	Height    int64  `json:"height"`
+	BlockTime int64  `json:"blockTime"`
+	Hash      string `json:"hash"`
+	Proposer  string `json:"proposer"`
+	NumTxs    int64  `json:"numTxs"`
+}
+
+// ExplorerBlockMessage contains the blocks produced since the last message
+type ExplorerBlockMessage []ExplorerBlock
+
+// ExplorerTx is a transaction included in an L1 block. Action is left raw as
+// its shape depends on the action type.
+type ExplorerTx struct {
+	Time   int64           `json:"time"`
+	User   string          `json:"user"`
+	Action json.RawMessage `json:"action"`
+	Block  int64           `json:"block"`
+	Hash   string          `json:"hash"`
+	Error  *string         `json:"error"`
+}
+
+// ExplorerTxsMessage contains the transactions included since the last
+// message
+type ExplorerTxsMessage []ExplorerTx
+
 // PongMessage is a ping/pong response
 type PongMessage struct{}
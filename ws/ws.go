@@ -3,20 +3,28 @@ package ws
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
 	"net/url"
 	"path"
+	"slices"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/banky/go-hyperliquid/constants"
+	"github.com/banky/go-hyperliquid/logging"
 	"github.com/coder/websocket"
 	"github.com/ethereum/go-ethereum/common"
 )
 
 // Subscription represents an event subscription where events are
 // delivered on a data channel.
+//
+// The context passed to a Subscribe method bounds only sending the subscribe
+// request: if it is done first, the call fails and nothing is subscribed.
+// Cancelling it after the call returns has no effect. A subscription stays
+// active until Unsubscribe is called.
 type Subscription interface {
 	// Unsubscribe cancels the sending of events to the data channel
 	// and closes the error channel.
@@ -28,12 +36,35 @@ type Subscription interface {
 	// delivering the events has been closed). Only one value will ever be sent.
 	// The error channel is closed by Unsubscribe.
 	Err() <-chan error
+
+	// Dropped returns the number of messages discarded because the
+	// subscription's buffer was full when they arrived.
+	Dropped() uint64
 }
 
+// ErrFirstMessageTimeout is sent on a subscription's error channel when the
+// server acknowledges the subscription but no data arrives within the
+// configured first message timeout. The subscription stays active.
+var ErrFirstMessageTimeout = errors.New("no data received on subscription")
+
+// ErrNotConnected is returned by Post when the Client has no open connection
+// and the request was never sent
+var ErrNotConnected = errors.New("websocket not connected")
+
+// ErrConnectionLost is returned by Post when the connection dropped or the
+// Client was closed after the request was sent but before its response
+// arrived. The request may or may not have been processed.
+var ErrConnectionLost = errors.New("websocket connection lost")
+
 // subscription implements the Subscription interface
 type subscription struct {
 	cancel  func()
 	errChan chan error
+	cs      *channelSubscription
+
+	errOnce sync.Once
+	mu      sync.Mutex
+	closed  bool
 }
 
 func (s *subscription) Unsubscribe() {
@@ -44,10 +75,39 @@ func (s *subscription) Err() <-chan error {
 	return s.errChan
 }
 
+func (s *subscription) Dropped() uint64 {
+	return s.cs.dropped.Load()
+}
+
+// sendErr delivers err on the error channel. Only the first error is ever
+// delivered, and nothing is sent once the channel has been closed.
+func (s *subscription) sendErr(err error) {
+	s.errOnce.Do(func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		if s.closed {
+			return
+		}
+		// errChan is buffered and this is the only send, so it never blocks
+		s.errChan <- err
+	})
+}
+
+// closeErr closes the error channel
+func (s *subscription) closeErr() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.closed = true
+	close(s.errChan)
+}
+
 // ClientInterface defines the contract for WebSocket subscriptions
 type ClientInterface interface {
 	Start(ctx context.Context) error
 	Close()
+	Reconnected() <-chan struct{}
 	SubscribeAllMids(
 		ctx context.Context,
 		ch chan<- AllMidsMessage,
@@ -78,6 +138,11 @@ type ClientInterface interface {
 		coin string,
 		ch chan<- ActiveAssetCtxMessage,
 	) (Subscription, error)
+	SubscribeActiveSpotAssetCtx(
+		ctx context.Context,
+		coin string,
+		ch chan<- ActiveSpotAssetCtxMessage,
+	) (Subscription, error)
 	SubscribeUserEvents(
 		ctx context.Context,
 		user common.Address,
@@ -93,15 +158,49 @@ type ClientInterface interface {
 		user string,
 		ch chan<- OrderUpdatesMessage,
 	) (Subscription, error)
+	SubscribeUserFundings(
+		ctx context.Context,
+		user string,
+		ch chan<- UserFundingsMessage,
+	) (Subscription, error)
+	SubscribeWebData2(
+		ctx context.Context,
+		user string,
+		ch chan<- WebData2Message,
+	) (Subscription, error)
+	SubscribeNotification(
+		ctx context.Context,
+		user common.Address,
+		ch chan<- NotificationMessage,
+	) (Subscription, error)
+	SubscribeActiveAssetData(
+		ctx context.Context,
+		coin string,
+		user string,
+		ch chan<- ActiveAssetDataMessage,
+	) (Subscription, error)
 }
 
 // Client manages WebSocket subscriptions and message routing
 type Client struct {
 	baseURL               string
+	wsURL                 string
+	firstMessageTimeout   time.Duration
+	bufferSize            int
+	pingInterval          time.Duration
+	pongTimeout           time.Duration
+	pongChan              chan struct{}
+	reconnect             bool
+	maxReconnectBackoff   time.Duration
 	conn                  *websocket.Conn
 	wsReady               bool
 	subscriptionIDCounter int64
 	activeSubscriptions   map[string][]*channelSubscription
+	postIDCounter         int64
+	pendingPosts          map[int64]chan postResponse
+	reconnected           chan struct{}
+	logger                logging.Logger
+	observer              logging.Observer
 	stopChan              chan struct{}
 	wg                    sync.WaitGroup
 	mu                    sync.RWMutex
@@ -111,19 +210,134 @@ type Client struct {
 type channelSubscription struct {
 	internalChan any
 	id           int64
+	sub          SubscriptionType
+
+	// acked is closed once the server acknowledges the subscription
+	acked    chan struct{}
+	ackOnce  sync.Once
+	received atomic.Bool
+
+	// dropped counts messages discarded because internalChan was full
+	dropped atomic.Uint64
+}
+
+// ack marks the subscription as acknowledged by the server
+func (c *channelSubscription) ack() {
+	c.ackOnce.Do(func() { close(c.acked) })
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithFirstMessageTimeout makes every subscription report
+// ErrFirstMessageTimeout on its error channel if no data arrives within d of
+// the server acknowledging it. This helps detect feeds that will never
+// produce data, such as a subscription to a delisted coin. A zero duration
+// disables the check, which is the default.
+func WithFirstMessageTimeout(d time.Duration) Option {
+	return func(c *Client) {
+		c.firstMessageTimeout = d
+	}
+}
+
+// WithSubscriptionBuffer sets how many messages each subscription buffers
+// while its consumer is busy. Once the buffer is full, further messages for
+// that subscription are dropped and counted by Subscription.Dropped, so one
+// slow consumer never stalls the others. The default is 100.
+func WithSubscriptionBuffer(n int) Option {
+	return func(c *Client) {
+		c.bufferSize = n
+	}
+}
+
+// WithPingInterval sets how often the Client pings the server to keep the
+// connection alive. The default is 50 seconds.
+func WithPingInterval(d time.Duration) Option {
+	return func(c *Client) {
+		c.pingInterval = d
+	}
+}
+
+// WithPongTimeout sets how long the Client waits for a pong after each ping
+// before treating the connection as dead and closing it, which triggers a
+// reconnect when enabled. This catches connections that go stale silently,
+// for example behind a load balancer. The default is 10 seconds; a zero
+// duration disables the check.
+func WithPongTimeout(d time.Duration) Option {
+	return func(c *Client) {
+		c.pongTimeout = d
+	}
+}
+
+// WithReconnect controls whether the Client redials after the connection
+// drops. Reconnection is enabled by default; every active subscription is
+// re-sent to the server once the new connection is up.
+func WithReconnect(enabled bool) Option {
+	return func(c *Client) {
+		c.reconnect = enabled
+	}
+}
+
+// WithLogger sets the logger that receives connection events such as
+// reconnects, and messages that could not be decoded or delivered. Events are
+// discarded by default.
+func WithLogger(l logging.Logger) Option {
+	return func(c *Client) {
+		c.logger = logging.OrNop(l)
+	}
+}
+
+// WithObserver sets the observer notified of the duration and outcome of
+// every Post. Posts made while disconnected fail with ErrNotConnected before
+// anything is sent and are not observed.
+func WithObserver(o logging.Observer) Option {
+	return func(c *Client) {
+		c.observer = o
+	}
 }
 
+// WithMaxReconnectBackoff caps the exponential backoff between reconnect
+// attempts. The default is 30 seconds.
+func WithMaxReconnectBackoff(d time.Duration) Option {
+	return func(c *Client) {
+		c.maxReconnectBackoff = d
+	}
+}
+
+const (
+	defaultSubscriptionBuffer = 100
+	defaultPingInterval       = 50 * time.Second
+	defaultPongTimeout        = 10 * time.Second
+	initialReconnectBackoff   = 250 * time.Millisecond
+	defaultMaxBackoff         = 30 * time.Second
+)
+
 // New creates a new WebSocket Client
-func New(baseURL string) *Client {
+func New(baseURL string, opts ...Option) *Client {
 	if baseURL == "" {
 		baseURL = constants.MAINNET_API_URL
 	}
 
-	return &Client{
+	c := &Client{
 		baseURL:             baseURL,
+		bufferSize:          defaultSubscriptionBuffer,
+		pingInterval:        defaultPingInterval,
+		pongTimeout:         defaultPongTimeout,
+		pongChan:            make(chan struct{}, 1),
+		reconnect:           true,
+		maxReconnectBackoff: defaultMaxBackoff,
 		activeSubscriptions: make(map[string][]*channelSubscription),
+		pendingPosts:        make(map[int64]chan postResponse),
+		reconnected:         make(chan struct{}),
+		logger:              logging.Nop(),
 		stopChan:            make(chan struct{}),
 	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
 }
 
 // Start initializes the WebSocket connection and starts the read/ping loops
@@ -147,9 +361,9 @@ func (m *Client) Start(ctx context.Context) error {
 	// make sure we append "/ws" correctly, without double slashes
 	u.Path = path.Join(u.Path, "ws")
 
-	wsURL := u.String()
+	m.wsURL = u.String()
 
-	conn, _, err := websocket.Dial(ctx, wsURL, nil)
+	conn, _, err := websocket.Dial(ctx, m.wsURL, nil)
 	if err != nil {
 		return fmt.Errorf("failed to connect to websocket: %w", err)
 	}
@@ -172,12 +386,52 @@ func (m *Client) Close() {
 	m.mu.Lock()
 	if m.conn != nil {
 		m.conn.Close(websocket.StatusNormalClosure, "closing")
+		m.conn = nil
 	}
 	m.mu.Unlock()
 
 	m.wg.Wait()
 }
 
+// ActiveSubscriptions returns the sorted identifiers, such as "l2Book:btc",
+// that have at least one local subscriber. Several subscribers to the same
+// feed share one identifier.
+func (m *Client) ActiveSubscriptions() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	identifiers := make([]string, 0, len(m.activeSubscriptions))
+	for identifier, subs := range m.activeSubscriptions {
+		if len(subs) != 0 {
+			identifiers = append(identifiers, identifier)
+		}
+	}
+	slices.Sort(identifiers)
+
+	return identifiers
+}
+
+// Reconnected returns a channel that is closed the next time the Client
+// reconnects after its connection dropped. It is closed before any message
+// from the new connection is delivered. Every subscription is then replayed,
+// so the server resends its snapshots. Call Reconnected again to wait for the
+// next reconnect.
+func (m *Client) Reconnected() <-chan struct{} {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.reconnected
+}
+
+// IsConnected reports whether the Client currently has a connection. It is
+// false before Start and while reconnecting after the connection dropped.
+func (m *Client) IsConnected() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.conn != nil
+}
+
 // readLoop handles incoming messages from the WebSocket
 func (m *Client) readLoop() {
 	defer m.wg.Done()
@@ -193,12 +447,26 @@ func (m *Client) readLoop() {
 
 		_, data, err := conn.Read(context.Background())
 		if err != nil {
-			// Normal closure or context cancellation - exit gracefully
-			if websocket.CloseStatus(err) == websocket.StatusNormalClosure {
+			if m.stopped() {
 				return
 			}
-			log.Printf("websocket read error: %v", err)
-			return
+			if !m.reconnect {
+				m.mu.Lock()
+				m.failPendingPosts(ErrConnectionLost)
+				m.mu.Unlock()
+
+				// Normal closure - exit gracefully
+				if websocket.CloseStatus(err) != websocket.StatusNormalClosure {
+					m.logger.Error("websocket read error", "err", err)
+				}
+				return
+			}
+
+			m.logger.Warn("websocket read error, reconnecting", "err", err)
+			if !m.redial(conn) {
+				return
+			}
+			continue
 		}
 
 		message := string(data)
@@ -217,7 +485,7 @@ func (m *Client) readLoop() {
 func (m *Client) pingLoop() {
 	defer m.wg.Done()
 
-	ticker := time.NewTicker(50 * time.Second)
+	ticker := time.NewTicker(m.pingInterval)
 	defer ticker.Stop()
 
 	for {
@@ -230,7 +498,13 @@ func (m *Client) pingLoop() {
 			m.mu.RUnlock()
 
 			if conn == nil {
-				return
+				continue
+			}
+
+			// Discard a pong left over from an earlier ping
+			select {
+			case <-m.pongChan:
+			default:
 			}
 
 			msg := map[string]string{"method": "ping"}
@@ -244,9 +518,131 @@ func (m *Client) pingLoop() {
 			cancel()
 
 			if err != nil {
-				log.Printf("websocket ping error: %v", err)
+				// The read loop notices the broken connection and
+				// reconnects; keep pinging whatever conn comes next.
+				m.logger.Warn("websocket ping error", "err", err)
+				if !m.reconnect {
+					return
+				}
+				continue
+			}
+
+			if m.pongTimeout <= 0 {
+				continue
+			}
+
+			select {
+			case <-m.stopChan:
 				return
+			case <-m.pongChan:
+			case <-time.After(m.pongTimeout):
+				// Closing the conn makes the read loop fail, which triggers a
+				// reconnect when enabled
+				m.logger.Warn(
+					"websocket pong not received, closing connection",
+					"timeout", m.pongTimeout,
+				)
+				conn.Close(websocket.StatusGoingAway, "pong timeout")
 			}
 		}
 	}
 }
+
+// stopped reports whether Close has been called
+func (m *Client) stopped() bool {
+	select {
+	case <-m.stopChan:
+		return true
+	default:
+		return false
+	}
+}
+
+// redial replaces a dropped connection, retrying with exponential backoff
+// until it succeeds or the Client is closed. Posts still waiting on the old
+// connection fail with ErrConnectionLost. Once connected it replays every
+// active subscription. It returns false if the Client was closed first.
+func (m *Client) redial(old *websocket.Conn) bool {
+	old.CloseNow()
+
+	m.mu.Lock()
+	m.conn = nil
+	m.wsReady = false
+	m.failPendingPosts(ErrConnectionLost)
+	m.mu.Unlock()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-m.stopChan:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	backoff := min(initialReconnectBackoff, m.maxReconnectBackoff)
+	for {
+		conn, _, err := websocket.Dial(ctx, m.wsURL, nil)
+		if err == nil {
+			m.logger.Info("websocket reconnected")
+			m.resubscribe(conn)
+			return true
+		}
+		if m.stopped() {
+			return false
+		}
+		m.logger.Warn(
+			"websocket reconnect failed",
+			"retryIn", backoff,
+			"err", err,
+		)
+
+		select {
+		case <-m.stopChan:
+			return false
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, m.maxReconnectBackoff)
+	}
+}
+
+// resubscribe installs conn as the active connection and re-sends a subscribe
+// message for every identifier that still has subscribers.
+func (m *Client) resubscribe(conn *websocket.Conn) {
+	m.mu.Lock()
+	if m.stopped() {
+		m.mu.Unlock()
+		conn.Close(websocket.StatusNormalClosure, "closing")
+		return
+	}
+	m.conn = conn
+	close(m.reconnected)
+	m.reconnected = make(chan struct{})
+
+	// Subscriptions added after this point are sent by subscribe itself, since
+	// it sees the new conn.
+	payloads := make([]any, 0, len(m.activeSubscriptions))
+	for _, subs := range m.activeSubscriptions {
+		if len(subs) == 0 {
+			continue
+		}
+		payloads = append(payloads, subs[0].sub.subscriptionPayload())
+	}
+	m.mu.Unlock()
+
+	for _, payload := range payloads {
+		msg := map[string]any{
+			"method":       "subscribe",
+			"subscription": payload,
+		}
+		data, _ := json.Marshal(msg)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		err := conn.Write(ctx, websocket.MessageText, data)
+		cancel()
+		if err != nil {
+			m.logger.Warn("error replaying subscription", "err", err)
+		}
+	}
+}
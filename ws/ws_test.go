@@ -3,12 +3,19 @@ package ws
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/banky/go-hyperliquid/logging"
 	"github.com/coder/websocket"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/maxatome/go-testdeep/helpers/tdsuite"
@@ -80,6 +87,23 @@ func (s *WSSuite) TestSubscriptionIdentifiers(assert, require *td.T) {
 			sub:        ActiveAssetDataSubscription{Coin: "ETH", User: "0xXYZ"},
 			expectedID: "activeAssetData:eth,0xxyz",
 		},
+		{
+			name: "Notification",
+			sub: NotificationSubscription{
+				User: common.HexToAddress("0xABC"),
+			},
+			expectedID: "notification",
+		},
+		{
+			name:       "ExplorerBlock",
+			sub:        ExplorerBlockSubscription{},
+			expectedID: "explorerBlock",
+		},
+		{
+			name:       "ExplorerTxs",
+			sub:        ExplorerTxsSubscription{},
+			expectedID: "explorerTxs",
+		},
 	}
 
 	for _, tt := range tests {
@@ -94,9 +118,13 @@ func (s *WSSuite) TestSubscriptionIdentifiers(assert, require *td.T) {
 type mockWSServer struct {
 	server *httptest.Server
 	url    string
+
+	mu      sync.Mutex
+	methods []string
 }
 
 func newMockWSServer(t testing.TB) *mockWSServer {
+	s := &mockWSServer{}
 	server := httptest.NewServer(
 		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			conn, err := websocket.Accept(w, r, nil)
@@ -132,6 +160,10 @@ func newMockWSServer(t testing.TB) *mockWSServer {
 				}
 
 				method, _ := msg["method"].(string)
+				s.mu.Lock()
+				s.methods = append(s.methods, method)
+				s.mu.Unlock()
+
 				switch method {
 				case "ping":
 					pongMsg := map[string]string{"channel": "pong"}
@@ -143,19 +175,82 @@ func newMockWSServer(t testing.TB) *mockWSServer {
 					)
 				case "subscribe":
 					// Server acknowledges subscription
-					_ = msg["subscription"]
+					ackMsg := map[string]any{
+						"channel": "subscriptionResponse",
+						"data":    msg,
+					}
+					ackData, _ := json.Marshal(ackMsg)
+					_ = conn.Write(
+						context.Background(),
+						websocket.MessageText,
+						ackData,
+					)
 				case "unsubscribe":
 					// Server acknowledges unsubscription
 					_ = msg["subscription"]
+				case "post":
+					// Server echoes the request payload under the same id
+					request, _ := msg["request"].(map[string]any)
+					response := map[string]any{
+						"type":    request["type"],
+						"payload": request["payload"],
+					}
+					if t := request["type"]; t != "info" && t != "action" {
+						response = map[string]any{
+							"type":    "error",
+							"payload": "unknown request type",
+						}
+					}
+					postMsg := map[string]any{
+						"channel": "post",
+						"data": map[string]any{
+							"id":       msg["id"],
+							"response": response,
+						},
+					}
+					postData, _ := json.Marshal(postMsg)
+					_ = conn.Write(
+						context.Background(),
+						websocket.MessageText,
+						postData,
+					)
 				}
 			}
 		}),
 	)
 
-	return &mockWSServer{
-		server: server,
-		url:    "http" + strings.TrimPrefix(server.URL, "http"),
+	s.server = server
+	s.url = "http" + strings.TrimPrefix(server.URL, "http")
+	return s
+}
+
+// count returns how many messages with method the server has received
+func (s *mockWSServer) count(method string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n := 0
+	for _, m := range s.methods {
+		if m == method {
+			n++
+		}
+	}
+	return n
+}
+
+// subscriptionFrames returns the subscribe and unsubscribe methods the
+// server has received, in order
+func (s *mockWSServer) subscriptionFrames() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var frames []string
+	for _, m := range s.methods {
+		if m == "subscribe" || m == "unsubscribe" {
+			frames = append(frames, m)
+		}
 	}
+	return frames
 }
 
 func (s *mockWSServer) close() {
@@ -184,6 +279,211 @@ func (s *WSSuite) TestClientStartStop(assert, require *td.T) {
 	client.Close()
 }
 
+func (s *WSSuite) TestReconnectReplaysSubscriptions(assert, require *td.T) {
+	t := require.TB
+	require.Parallel()
+
+	// The first connection is dropped as soon as the subscription arrives.
+	// Every later connection acknowledges the replayed subscribe and then
+	// pushes a mids update.
+	var connCount atomic.Int32
+	replayed := make(chan map[string]any, 1)
+	server := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			conn, err := websocket.Accept(w, r, nil)
+			if err != nil {
+				t.Logf("websocket accept error: %v", err)
+				return
+			}
+			defer conn.CloseNow()
+			n := connCount.Add(1)
+
+			ctx, cancel := context.WithTimeout(
+				context.Background(),
+				5*time.Second,
+			)
+			defer cancel()
+
+			_, data, err := conn.Read(ctx)
+			if err != nil {
+				return
+			}
+			if n == 1 {
+				conn.Close(websocket.StatusGoingAway, "server restart")
+				return
+			}
+
+			var msg map[string]any
+			_ = json.Unmarshal(data, &msg)
+			replayed <- msg
+
+			_ = conn.Write(
+				ctx,
+				websocket.MessageText,
+				[]byte(`{"channel":"allMids","data":{"mids":{"BTC":"50000"}}}`),
+			)
+			_, _, _ = conn.Read(ctx)
+		}),
+	)
+	defer server.Close()
+
+	client := New(server.URL, WithMaxReconnectBackoff(50*time.Millisecond))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := client.Start(ctx)
+	require.CmpNoError(err)
+	defer client.Close()
+
+	msgChan := make(chan AllMidsMessage, 1)
+	sub, err := client.SubscribeAllMids(ctx, msgChan)
+	require.CmpNoError(err)
+	defer sub.Unsubscribe()
+
+	select {
+	case msg := <-replayed:
+		require.Cmp(msg, map[string]any{
+			"method":       "subscribe",
+			"subscription": map[string]any{"type": "allMids"},
+		})
+	case <-time.After(2 * time.Second):
+		require.True(false, "timeout waiting for replayed subscription")
+	}
+
+	select {
+	case msg := <-msgChan:
+		assert.Cmp(msg.Mids, map[string]string{"BTC": "50000"})
+	case <-time.After(2 * time.Second):
+		require.True(false, "timeout waiting for message after reconnect")
+	}
+	assert.Cmp(connCount.Load(), int32(2))
+}
+
+func (s *WSSuite) TestReconnectDisabled(assert, require *td.T) {
+	t := require.TB
+	require.Parallel()
+
+	var connCount atomic.Int32
+	server := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			conn, err := websocket.Accept(w, r, nil)
+			if err != nil {
+				t.Logf("websocket accept error: %v", err)
+				return
+			}
+			connCount.Add(1)
+			conn.Close(websocket.StatusGoingAway, "server restart")
+		}),
+	)
+	defer server.Close()
+
+	client := New(server.URL, WithReconnect(false))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := client.Start(ctx)
+	require.CmpNoError(err)
+	defer client.Close()
+
+	time.Sleep(200 * time.Millisecond)
+	assert.Cmp(connCount.Load(), int32(1))
+}
+
+func (s *WSSuite) TestPingInterval(assert, require *td.T) {
+	t := require.TB
+	require.Parallel()
+
+	pinged := make(chan struct{}, 1)
+	server := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			conn, err := websocket.Accept(w, r, nil)
+			if err != nil {
+				t.Logf("websocket accept error: %v", err)
+				return
+			}
+			defer conn.CloseNow()
+
+			for {
+				_, data, err := conn.Read(context.Background())
+				if err != nil {
+					return
+				}
+				if string(data) == `{"method":"ping"}` {
+					select {
+					case pinged <- struct{}{}:
+					default:
+					}
+					_ = conn.Write(
+						context.Background(),
+						websocket.MessageText,
+						[]byte(`{"channel":"pong"}`),
+					)
+				}
+			}
+		}),
+	)
+	defer server.Close()
+
+	client := New(server.URL, WithPingInterval(50*time.Millisecond))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := client.Start(ctx)
+	require.CmpNoError(err)
+	defer client.Close()
+
+	select {
+	case <-pinged:
+	case <-time.After(1 * time.Second):
+		require.True(false, "timeout waiting for ping")
+	}
+}
+
+func (s *WSSuite) TestPongTimeoutReconnects(assert, require *td.T) {
+	t := require.TB
+	require.Parallel()
+
+	// The server reads pings but never answers them
+	var connCount atomic.Int32
+	server := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			conn, err := websocket.Accept(w, r, nil)
+			if err != nil {
+				t.Logf("websocket accept error: %v", err)
+				return
+			}
+			defer conn.CloseNow()
+			connCount.Add(1)
+
+			for {
+				if _, _, err := conn.Read(context.Background()); err != nil {
+					return
+				}
+			}
+		}),
+	)
+	defer server.Close()
+
+	client := New(
+		server.URL,
+		WithPingInterval(20*time.Millisecond),
+		WithPongTimeout(20*time.Millisecond),
+		WithMaxReconnectBackoff(10*time.Millisecond),
+	)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := client.Start(ctx)
+	require.CmpNoError(err)
+	defer client.Close()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for connCount.Load() < 2 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Gte(connCount.Load(), int32(2))
+}
+
 // ===== Channel-Based Subscription Tests =====
 
 func (s *WSSuite) TestChannelSubscription(assert, require *td.T) {
@@ -234,9 +534,9 @@ func (s *WSSuite) TestChannelSubscription(assert, require *td.T) {
 	client.Close()
 }
 
-// ===== Message Routing Tests =====
+// ===== Post Tests =====
 
-func (s *WSSuite) TestL2BookMessageRouting(assert, require *td.T) {
+func (s *WSSuite) TestPost(assert, require *td.T) {
 	t := require.TB
 	require.Parallel()
 
@@ -249,53 +549,39 @@ func (s *WSSuite) TestL2BookMessageRouting(assert, require *td.T) {
 
 	err := client.Start(ctx)
 	require.CmpNoError(err)
+	defer client.Close()
 
-	// Create subscriber channel
-	msgChan := make(chan L2BookMessage)
-	sub, err := client.SubscribeL2Book(ctx, "BTC", msgChan)
-	require.CmpNoError(err)
-	defer sub.Unsubscribe()
-
-	time.Sleep(10 * time.Millisecond)
-
-	// Manually inject a message for testing
-	msgData := map[string]any{
-		"channel": "l2Book",
-		"data": map[string]any{
-			"coin": "BTC",
-			"levels": [][]map[string]any{
-				{
-					{
-						"px": "50000",
-						"sz": "1.5",
-						"n":  5,
-					},
-				},
-				{
-					{
-						"px": "50100",
-						"sz": "2.0",
-						"n":  3,
-					},
-				},
-			},
-			"time": 1234567890,
-		},
+	// Concurrent posts must each get the response carrying their own id
+	results := make([]json.RawMessage, 3)
+	errs := make([]error, 3)
+	var wg sync.WaitGroup
+	for i := range 3 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], errs[i] = client.Post(
+				ctx,
+				PostTypeInfo,
+				map[string]any{"type": "l2Book", "coin": fmt.Sprint(i)},
+			)
+		}()
 	}
-	msgBytes, _ := json.Marshal(msgData)
-	client.handleMessage(msgBytes)
+	wg.Wait()
 
-	// Receive message from channel
-	select {
-	case received := <-msgChan:
-		require.Cmp(received.Coin, "BTC")
-		require.Cmp(received.Time, int64(1234567890))
-	case <-time.After(1 * time.Second):
-		require.True(false, "timeout waiting for message")
+	for i := range 3 {
+		require.CmpNoError(errs[i])
+		var got map[string]any
+		require.CmpNoError(json.Unmarshal(results[i], &got))
+		assert.Cmp(got, map[string]any{"type": "l2Book", "coin": fmt.Sprint(i)})
 	}
+
+	client.mu.RLock()
+	pending := len(client.pendingPosts)
+	client.mu.RUnlock()
+	assert.Cmp(pending, 0)
 }
 
-func (s *WSSuite) TestTradesMessageRouting(assert, require *td.T) {
+func (s *WSSuite) TestPostError(assert, require *td.T) {
 	t := require.TB
 	require.Parallel()
 
@@ -306,47 +592,874 @@ func (s *WSSuite) TestTradesMessageRouting(assert, require *td.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
+	_, err := client.Post(ctx, PostTypeInfo, map[string]any{})
+	require.True(errors.Is(err, ErrNotConnected), "got %v", err)
+
+	err = client.Start(ctx)
+	require.CmpNoError(err)
+	defer client.Close()
+
+	_, err = client.Post(ctx, "bogus", map[string]any{})
+	require.CmpError(err)
+	assert.Contains(err.Error(), "unknown request type")
+}
+
+func (s *WSSuite) TestPostFailsWhenConnectionDrops(assert, require *td.T) {
+	t := require.TB
+	require.Parallel()
+
+	// The server drops the connection as soon as a post arrives, without
+	// answering it
+	server := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			conn, err := websocket.Accept(w, r, nil)
+			if err != nil {
+				t.Logf("websocket accept error: %v", err)
+				return
+			}
+			defer conn.CloseNow()
+
+			for {
+				_, data, err := conn.Read(context.Background())
+				if err != nil {
+					return
+				}
+				if strings.Contains(string(data), `"method":"post"`) {
+					conn.Close(websocket.StatusGoingAway, "server restart")
+					return
+				}
+			}
+		}),
+	)
+	defer server.Close()
+
+	client := New(server.URL, WithMaxReconnectBackoff(50*time.Millisecond))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
 	err := client.Start(ctx)
 	require.CmpNoError(err)
+	defer client.Close()
 
-	// Create subscriber channel
-	msgChan := make(chan TradesMessage)
-	sub, err := client.SubscribeTrades(ctx, "ETH", msgChan)
+	// The post has no deadline, so only the dropped connection can end it
+	errs := make(chan error, 1)
+	go func() {
+		_, err := client.Post(
+			context.Background(),
+			PostTypeInfo,
+			map[string]any{"type": "allMids"},
+		)
+		errs <- err
+	}()
+
+	select {
+	case err := <-errs:
+		require.True(errors.Is(err, ErrConnectionLost), "got %v", err)
+	case <-time.After(2 * time.Second):
+		require.True(false, "post still waiting after the connection dropped")
+	}
+
+	client.mu.RLock()
+	pending := len(client.pendingPosts)
+	client.mu.RUnlock()
+	assert.Cmp(pending, 0)
+}
+
+func (s *WSSuite) TestPostObserver(assert, require *td.T) {
+	t := require.TB
+	require.Parallel()
+
+	server := newMockWSServer(t)
+	defer server.close()
+
+	var (
+		mu       sync.Mutex
+		types    []string
+		failures []error
+	)
+	observer := logging.ObserverFunc(
+		func(requestType string, duration time.Duration, err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			types = append(types, requestType)
+			failures = append(failures, err)
+			assert.Gte(duration, time.Duration(0))
+		},
+	)
+
+	client := New(server.url, WithObserver(observer))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Nothing is sent while disconnected, so nothing is observed
+	_, err := client.Post(ctx, PostTypeInfo, map[string]any{})
+	require.True(errors.Is(err, ErrNotConnected), "got %v", err)
+
+	err = client.Start(ctx)
+	require.CmpNoError(err)
+	defer client.Close()
+
+	_, err = client.Post(ctx, PostTypeInfo, map[string]any{"type": "meta"})
+	require.CmpNoError(err)
+	_, postErr := client.Post(ctx, "bogus", map[string]any{})
+	require.CmpError(postErr)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Cmp(types, []string{PostTypeInfo, "bogus"})
+	assert.Cmp(failures, []error{nil, postErr})
+}
+
+// ===== Message Routing Tests =====
+
+func (s *WSSuite) TestL2BookMessageRouting(assert, require *td.T) {
+	t := require.TB
+	require.Parallel()
+
+	server := newMockWSServer(t)
+	defer server.close()
+
+	client := New(server.url)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := client.Start(ctx)
+	require.CmpNoError(err)
+
+	// Create subscriber channel
+	msgChan := make(chan L2BookMessage)
+	sub, err := client.SubscribeL2Book(ctx, "BTC", msgChan)
+	require.CmpNoError(err)
+	defer sub.Unsubscribe()
+
+	time.Sleep(10 * time.Millisecond)
+
+	// Manually inject a message for testing
+	msgData := map[string]any{
+		"channel": "l2Book",
+		"data": map[string]any{
+			"coin": "BTC",
+			"levels": [][]map[string]any{
+				{
+					{
+						"px": "50000",
+						"sz": "1.5",
+						"n":  5,
+					},
+				},
+				{
+					{
+						"px": "50100",
+						"sz": "2.0",
+						"n":  3,
+					},
+				},
+			},
+			"time": 1234567890,
+		},
+	}
+	msgBytes, _ := json.Marshal(msgData)
+	client.handleMessage(msgBytes)
+
+	// Receive message from channel
+	select {
+	case received := <-msgChan:
+		require.Cmp(received.Coin, "BTC")
+		require.Cmp(received.Time, int64(1234567890))
+	case <-time.After(1 * time.Second):
+		require.True(false, "timeout waiting for message")
+	}
+}
+
+func (s *WSSuite) TestTradesMessageRouting(assert, require *td.T) {
+	t := require.TB
+	require.Parallel()
+
+	server := newMockWSServer(t)
+	defer server.close()
+
+	client := New(server.url)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := client.Start(ctx)
+	require.CmpNoError(err)
+
+	// Create subscriber channel
+	msgChan := make(chan TradesMessage)
+	sub, err := client.SubscribeTrades(ctx, "ETH", msgChan)
+	require.CmpNoError(err)
+	defer sub.Unsubscribe()
+
+	time.Sleep(10 * time.Millisecond)
+
+	msgData := map[string]any{
+		"channel": "trades",
+		"data": []any{
+			map[string]any{
+				"coin": "ETH",
+				"side": "A",
+				"px":   "3000",
+				"sz":   "10",
+				"hash": "0xabc123",
+				"time": 1234567890,
+			},
+			map[string]any{
+				"coin": "ETH",
+				"side": "B",
+				"px":   "3001",
+				"sz":   "0.0125",
+				"hash": "0xdef456",
+				"time": 1234567891,
+			},
+		},
+	}
+	msgBytes, _ := json.Marshal(msgData)
+	client.handleMessage(msgBytes)
+
+	select {
+	case received := <-msgChan:
+		require.Cmp(len(received.Trades), 2)
+		require.Cmp(received.Trades[0].Coin, "ETH")
+		require.Cmp(received.Trades[1].Sz, "0.0125")
+	case <-time.After(1 * time.Second):
+		require.True(false, "timeout waiting for message")
+	}
+}
+
+func (s *WSSuite) TestUserFundingsRouting(assert, require *td.T) {
+	t := require.TB
+	require.Parallel()
+
+	server := newMockWSServer(t)
+	defer server.close()
+
+	client := New(server.url)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := client.Start(ctx)
+	require.CmpNoError(err)
+
+	msgChan := make(chan UserFundingsMessage)
+	sub, err := client.SubscribeUserFundings(ctx, "0xABC", msgChan)
+	require.CmpNoError(err)
+	defer sub.Unsubscribe()
+
+	time.Sleep(10 * time.Millisecond)
+
+	msgData := map[string]any{
+		"channel": "userFundings",
+		"data": map[string]any{
+			"isSnapshot": true,
+			"user":       "0xabc",
+			"fundings": []any{
+				map[string]any{
+					"time":        1234567890,
+					"coin":        "ETH",
+					"usdc":        "-0.5",
+					"szi":         "2.0",
+					"fundingRate": "0.0000125",
+				},
+			},
+		},
+	}
+	msgBytes, _ := json.Marshal(msgData)
+	client.handleMessage(msgBytes)
+
+	select {
+	case received := <-msgChan:
+		require.Cmp(received["user"], "0xabc")
+		require.Len(received["fundings"], 1)
+	case <-time.After(1 * time.Second):
+		require.True(false, "timeout waiting for message")
+	}
+}
+
+func (s *WSSuite) TestWebData2Routing(assert, require *td.T) {
+	t := require.TB
+	require.Parallel()
+
+	server := newMockWSServer(t)
+	defer server.close()
+
+	client := New(server.url)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := client.Start(ctx)
+	require.CmpNoError(err)
+
+	msgChan := make(chan WebData2Message)
+	sub, err := client.SubscribeWebData2(ctx, "0xABC", msgChan)
+	require.CmpNoError(err)
+	defer sub.Unsubscribe()
+
+	time.Sleep(10 * time.Millisecond)
+
+	msgData := map[string]any{
+		"channel": "webData2",
+		"data": map[string]any{
+			"user": "0xabc",
+			"clearinghouseState": map[string]any{
+				"assetPositions": []any{},
+				"withdrawable":   "100.0",
+			},
+			"openOrders": []any{},
+			"spotState": map[string]any{
+				"balances": []any{},
+			},
+		},
+	}
+	msgBytes, _ := json.Marshal(msgData)
+	client.handleMessage(msgBytes)
+
+	select {
+	case received := <-msgChan:
+		require.Cmp(received["user"], "0xabc")
+		require.ContainsKey(received, "clearinghouseState")
+		require.ContainsKey(received, "openOrders")
+	case <-time.After(1 * time.Second):
+		require.True(false, "timeout waiting for message")
+	}
+}
+
+func (s *WSSuite) TestNotificationRouting(assert, require *td.T) {
+	t := require.TB
+	require.Parallel()
+
+	server := newMockWSServer(t)
+	defer server.close()
+
+	client := New(server.url)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := client.Start(ctx)
+	require.CmpNoError(err)
+
+	user := common.HexToAddress("0xABC")
+	msgChan := make(chan NotificationMessage)
+	sub, err := client.SubscribeNotification(ctx, user, msgChan)
+	require.CmpNoError(err)
+	defer sub.Unsubscribe()
+
+	// Notifications carry no user, so a second subscription is refused
+	_, err = client.SubscribeNotification(ctx, user, msgChan)
+	require.CmpError(err)
+
+	time.Sleep(10 * time.Millisecond)
+
+	msgData := map[string]any{
+		"channel": "notification",
+		"data": map[string]any{
+			"notification": "Liquidation warning: margin ratio above 80%",
+		},
+	}
+	msgBytes, _ := json.Marshal(msgData)
+	client.handleMessage(msgBytes)
+
+	select {
+	case received := <-msgChan:
+		require.Cmp(
+			received.Notification,
+			"Liquidation warning: margin ratio above 80%",
+		)
+	case <-time.After(1 * time.Second):
+		require.True(false, "timeout waiting for message")
+	}
+}
+
+func (s *WSSuite) TestActiveAssetDataRouting(assert, require *td.T) {
+	t := require.TB
+	require.Parallel()
+
+	server := newMockWSServer(t)
+	defer server.close()
+
+	client := New(server.url)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := client.Start(ctx)
+	require.CmpNoError(err)
+
+	msgChan := make(chan ActiveAssetDataMessage)
+	sub, err := client.SubscribeActiveAssetData(ctx, "BTC", "0xABC", msgChan)
+	require.CmpNoError(err)
+	defer sub.Unsubscribe()
+
+	time.Sleep(10 * time.Millisecond)
+
+	msgData := map[string]any{
+		"channel": "activeAssetData",
+		"data": map[string]any{
+			"user":             "0xabc",
+			"coin":             "BTC",
+			"leverage":         map[string]any{"type": "cross", "value": 20},
+			"maxTradeSzs":      []any{"1.5", "1.4"},
+			"availableToTrade": []any{"90000.0", "85000.0"},
+			"markPx":           "60000.0",
+		},
+	}
+	msgBytes, _ := json.Marshal(msgData)
+	client.handleMessage(msgBytes)
+
+	select {
+	case received := <-msgChan:
+		require.Cmp(received.Coin, "BTC")
+		require.Cmp(received.Leverage.Value, int64(20))
+		require.Cmp(received.MaxTradeSzs, [2]string{"1.5", "1.4"})
+		require.Cmp(received.AvailableToTrade, [2]string{"90000.0", "85000.0"})
+	case <-time.After(1 * time.Second):
+		require.True(false, "timeout waiting for message")
+	}
+}
+
+func (s *WSSuite) TestActiveSpotAssetCtxRouting(assert, require *td.T) {
+	t := require.TB
+	require.Parallel()
+
+	server := newMockWSServer(t)
+	defer server.close()
+
+	client := New(server.url)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := client.Start(ctx)
+	require.CmpNoError(err)
+
+	// A perp subscription on the same coin must not receive the spot payload
+	perpChan := make(chan ActiveAssetCtxMessage, 1)
+	perpSub, err := client.SubscribeActiveAssetCtx(ctx, "@107", perpChan)
+	require.CmpNoError(err)
+	defer perpSub.Unsubscribe()
+
+	spotChan := make(chan ActiveSpotAssetCtxMessage)
+	spotSub, err := client.SubscribeActiveSpotAssetCtx(ctx, "@107", spotChan)
+	require.CmpNoError(err)
+	defer spotSub.Unsubscribe()
+
+	time.Sleep(10 * time.Millisecond)
+
+	msgData := map[string]any{
+		"channel": "activeSpotAssetCtx",
+		"data": map[string]any{
+			"coin": "@107",
+			"ctx": map[string]any{
+				"dayNtlVlm":         "8906.0",
+				"markPx":            "0.14",
+				"midPx":             "0.209265",
+				"prevDayPx":         "0.20432",
+				"circulatingSupply": "596952537.3658201694",
+				"coin":              "@107",
+			},
+		},
+	}
+	msgBytes, _ := json.Marshal(msgData)
+	client.handleMessage(msgBytes)
+
+	select {
+	case received := <-spotChan:
+		require.Cmp(received.Coin, "@107")
+		require.Cmp(received.Ctx.CirculatingSupply, "596952537.3658201694")
+	case <-time.After(1 * time.Second):
+		require.True(false, "timeout waiting for message")
+	}
+
+	require.Len(perpChan, 0)
+}
+
+func (s *WSSuite) TestActiveAssetCtxPerpAndSpotShareSubscription(
+	assert, require *td.T,
+) {
+	t := require.TB
+	require.Parallel()
+
+	server := newMockWSServer(t)
+	defer server.close()
+
+	client := New(server.url)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := client.Start(ctx)
+	require.CmpNoError(err)
+	defer client.Close()
+
+	perpChan := make(chan ActiveAssetCtxMessage, 1)
+	perpSub, err := client.SubscribeActiveAssetCtx(ctx, "@107", perpChan)
+	require.CmpNoError(err)
+	spotSub, err := client.SubscribeActiveSpotAssetCtx(
+		ctx,
+		"@107",
+		make(chan ActiveSpotAssetCtxMessage),
+	)
+	require.CmpNoError(err)
+
+	// Dropping the spot subscriber keeps the shared server subscription
+	spotSub.Unsubscribe()
+	time.Sleep(100 * time.Millisecond)
+	require.Cmp(server.count("subscribe"), 1, "subscribe frames")
+	require.Cmp(server.count("unsubscribe"), 0, "unsubscribe frames")
+
+	msgBytes, _ := json.Marshal(map[string]any{
+		"channel": "activeAssetCtx",
+		"data": map[string]any{
+			"coin": "@107",
+			"ctx":  map[string]any{"markPx": "0.14"},
+		},
+	})
+	client.handleMessage(msgBytes)
+
+	select {
+	case received := <-perpChan:
+		require.Cmp(received.Coin, "@107")
+	case <-time.After(1 * time.Second):
+		require.True(false, "timeout waiting for message")
+	}
+
+	perpSub.Unsubscribe()
+	time.Sleep(100 * time.Millisecond)
+	assert.Cmp(server.count("unsubscribe"), 1, "unsubscribe frames")
+}
+
+func (s *WSSuite) TestExplorerMessageRouting(assert, require *td.T) {
+	t := require.TB
+	require.Parallel()
+
+	server := newMockWSServer(t)
+	defer server.close()
+
+	client := New(server.url)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := client.Start(ctx)
+	require.CmpNoError(err)
+
+	blocks := make(chan ExplorerBlockMessage, 1)
+	blockSub, err := client.SubscribeExplorerBlocks(ctx, blocks)
+	require.CmpNoError(err)
+	defer blockSub.Unsubscribe()
+
+	txs := make(chan ExplorerTxsMessage, 1)
+	txsSub, err := client.SubscribeExplorerTxs(ctx, txs)
+	require.CmpNoError(err)
+	defer txsSub.Unsubscribe()
+
+	time.Sleep(10 * time.Millisecond)
+
+	client.handleMessage([]byte(`{"channel":"explorerBlock","data":[{
+		"height":100,"blockTime":1234567890,"hash":"0xabc",
+		"proposer":"0xdef","numTxs":2}]}`))
+	client.handleMessage([]byte(`{"channel":"explorerTxs","data":[{
+		"time":1234567890,"user":"0x123","action":{"type":"order"},
+		"block":100,"hash":"0x456","error":null}]}`))
+
+	select {
+	case received := <-blocks:
+		require.Len(received, 1)
+		require.Cmp(received[0].Height, int64(100))
+		require.Cmp(received[0].NumTxs, int64(2))
+	case <-time.After(1 * time.Second):
+		require.True(false, "timeout waiting for block message")
+	}
+
+	select {
+	case received := <-txs:
+		require.Len(received, 1)
+		require.Cmp(received[0].Block, int64(100))
+		require.Cmp(string(received[0].Action), `{"type":"order"}`)
+		require.Nil(received[0].Error)
+	case <-time.After(1 * time.Second):
+		require.True(false, "timeout waiting for txs message")
+	}
+}
+
+func (s *WSSuite) TestOrderUpdatesRouting(assert, require *td.T) {
+	t := require.TB
+	require.Parallel()
+
+	server := newMockWSServer(t)
+	defer server.close()
+
+	client := New(server.url)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := client.Start(ctx)
+	require.CmpNoError(err)
+
+	msgChan := make(chan OrderUpdatesMessage)
+	sub, err := client.SubscribeOrderUpdates(ctx, "0xabc", msgChan)
+	require.CmpNoError(err)
+	defer sub.Unsubscribe()
+
+	time.Sleep(10 * time.Millisecond)
+
+	msgData := map[string]any{
+		"channel": "orderUpdates",
+		"data": []map[string]any{
+			{
+				"order": map[string]any{
+					"coin":      "BTC",
+					"side":      "B",
+					"limitPx":   "50000.0",
+					"sz":        "0.0",
+					"oid":       123,
+					"timestamp": 1700000000000,
+					"origSz":    "0.01",
+					"cloid":     "0x00000000000000000000000000000001",
+				},
+				"status":          "filled",
+				"statusTimestamp": 1700000000500,
+			},
+		},
+	}
+	msgBytes, _ := json.Marshal(msgData)
+	client.handleMessage(msgBytes)
+
+	select {
+	case received := <-msgChan:
+		require.Len(received, 1)
+		update := received[0]
+		require.Cmp(update.Status, "filled")
+		require.Cmp(update.StatusTimestamp, int64(1700000000500))
+		require.Cmp(update.Order.Oid, int64(123))
+		require.Cmp(update.Order.Coin, "BTC")
+		require.Cmp(update.Order.OrigSz, "0.01")
+		require.Cmp(
+			update.Order.Cloid,
+			td.Ptr("0x00000000000000000000000000000001"),
+		)
+	case <-time.After(1 * time.Second):
+		require.True(false, "timeout waiting for message")
+	}
+}
+
+func (s *WSSuite) TestUserEventsFundingRouting(assert, require *td.T) {
+	t := require.TB
+	require.Parallel()
+
+	server := newMockWSServer(t)
+	defer server.close()
+
+	client := New(server.url)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := client.Start(ctx)
+	require.CmpNoError(err)
+
+	msgChan := make(chan UserEventsMessage)
+	sub, err := client.SubscribeUserEvents(
+		ctx,
+		common.HexToAddress("0xABC"),
+		msgChan,
+	)
+	require.CmpNoError(err)
+	defer sub.Unsubscribe()
+
+	time.Sleep(10 * time.Millisecond)
+
+	msgData := map[string]any{
+		"channel": "user",
+		"data": map[string]any{
+			"funding": map[string]any{
+				"time":        1234567890,
+				"coin":        "BTC",
+				"usdc":        "-1.25",
+				"szi":         "0.5",
+				"fundingRate": "0.0000125",
+			},
+		},
+	}
+	msgBytes, _ := json.Marshal(msgData)
+	client.handleMessage(msgBytes)
+
+	select {
+	case received := <-msgChan:
+		require.NotNil(received.Funding)
+		require.Cmp(received.Funding.Coin, "BTC")
+		require.Cmp(received.Funding.Usdc, "-1.25")
+		require.Cmp(received.Funding.Time, int64(1234567890))
+		require.Nil(received.Liquidation)
+		require.Len(received.Fills, 0)
+	case <-time.After(1 * time.Second):
+		require.True(false, "timeout waiting for message")
+	}
+}
+
+func (s *WSSuite) TestUserEventsLiquidationRouting(assert, require *td.T) {
+	t := require.TB
+	require.Parallel()
+
+	server := newMockWSServer(t)
+	defer server.close()
+
+	client := New(server.url)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := client.Start(ctx)
+	require.CmpNoError(err)
+
+	msgChan := make(chan UserEventsMessage)
+	sub, err := client.SubscribeUserEvents(
+		ctx,
+		common.HexToAddress("0xABC"),
+		msgChan,
+	)
+	require.CmpNoError(err)
+	defer sub.Unsubscribe()
+
+	time.Sleep(10 * time.Millisecond)
+
+	msgData := map[string]any{
+		"channel": "user",
+		"data": map[string]any{
+			"liquidation": map[string]any{
+				"lid":                      42,
+				"liquidator":               "0xdef",
+				"liquidated_user":          "0xabc",
+				"liquidated_ntl_pos":       "1000.0",
+				"liquidated_account_value": "12.5",
+			},
+		},
+	}
+	msgBytes, _ := json.Marshal(msgData)
+	client.handleMessage(msgBytes)
+
+	select {
+	case received := <-msgChan:
+		require.NotNil(received.Liquidation)
+		require.Cmp(received.Liquidation.Lid, int64(42))
+		require.Cmp(received.Liquidation.LiquidatedUser, "0xabc")
+		require.Cmp(received.Liquidation.LiquidatedNtlPos, "1000.0")
+		require.Nil(received.Funding)
+	case <-time.After(1 * time.Second):
+		require.True(false, "timeout waiting for message")
+	}
+}
+
+func (s *WSSuite) TestUserEventsMessageDecoding(assert, require *td.T) {
+	tests := []struct {
+		name string
+		data string
+		want UserEventsMessage
+	}{
+		{
+			name: "fills",
+			data: `{"fills":[{"coin":"BTC","px":"50000","oid":1}]}`,
+			want: UserEventsMessage{
+				Fills: []Fill{{Coin: "BTC", Px: "50000", Oid: 1}},
+			},
+		},
+		{
+			name: "funding",
+			data: `{"funding":{"coin":"ETH","usdc":"-0.5","szi":"2"}}`,
+			want: UserEventsMessage{
+				Funding: &Funding{Coin: "ETH", Usdc: "-0.5", Szi: "2"},
+			},
+		},
+		{
+			name: "liquidation",
+			data: `{"liquidation":{"lid":42,"liquidated_user":"0xabc"}}`,
+			want: UserEventsMessage{
+				Liquidation: &Liquidation{Lid: 42, LiquidatedUser: "0xabc"},
+			},
+		},
+		{
+			name: "nonUserCancel",
+			data: `{"nonUserCancel":[{"coin":"SOL","oid":7}]}`,
+			want: UserEventsMessage{
+				NonUserCancel: []NonUserCancel{{Coin: "SOL", Oid: 7}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		var got UserEventsMessage
+		require.CmpNoError(json.Unmarshal([]byte(tt.data), &got), tt.name)
+		assert.Cmp(got, tt.want, tt.name)
+	}
+}
+
+// ===== First Message Timeout Tests =====
+
+func (s *WSSuite) TestFirstMessageTimeout(assert, require *td.T) {
+	t := require.TB
+	require.Parallel()
+
+	server := newMockWSServer(t)
+	defer server.close()
+
+	client := New(server.url, WithFirstMessageTimeout(100*time.Millisecond))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := client.Start(ctx)
+	require.CmpNoError(err)
+	defer client.Close()
+
+	// The mock server acknowledges the subscription but never sends data
+	msgChan := make(chan L2BookMessage)
+	sub, err := client.SubscribeL2Book(ctx, "DELISTED", msgChan)
+	require.CmpNoError(err)
+	defer sub.Unsubscribe()
+
+	select {
+	case err := <-sub.Err():
+		require.True(
+			errors.Is(err, ErrFirstMessageTimeout),
+			"expected ErrFirstMessageTimeout, got %v",
+			err,
+		)
+	case <-time.After(2 * time.Second):
+		require.True(false, "timeout waiting for first message error")
+	}
+}
+
+func (s *WSSuite) TestFirstMessageTimeoutNotFiredWhenDataArrives(
+	assert, require *td.T,
+) {
+	t := require.TB
+	require.Parallel()
+
+	server := newMockWSServer(t)
+	defer server.close()
+
+	client := New(server.url, WithFirstMessageTimeout(200*time.Millisecond))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := client.Start(ctx)
+	require.CmpNoError(err)
+	defer client.Close()
+
+	msgChan := make(chan L2BookMessage, 1)
+	sub, err := client.SubscribeL2Book(ctx, "BTC", msgChan)
 	require.CmpNoError(err)
 	defer sub.Unsubscribe()
 
-	time.Sleep(10 * time.Millisecond)
-
 	msgData := map[string]any{
-		"channel": "trades",
-		"data": []any{
-			map[string]any{
-				"coin": "ETH",
-				"side": "A",
-				"px":   "3000",
-				"sz":   10,
-				"hash": "0xabc123",
-				"time": 1234567890,
-			},
-			map[string]any{
-				"coin": "ETH",
-				"side": "B",
-				"px":   "3001",
-				"sz":   5,
-				"hash": "0xdef456",
-				"time": 1234567891,
-			},
+		"channel": "l2Book",
+		"data": map[string]any{
+			"coin":   "BTC",
+			"levels": [][]map[string]any{{}, {}},
+			"time":   1234567890,
 		},
 	}
 	msgBytes, _ := json.Marshal(msgData)
 	client.handleMessage(msgBytes)
 
 	select {
-	case received := <-msgChan:
-		require.Cmp(len(received.Trades), 2)
-		require.Cmp(received.Trades[0].Coin, "ETH")
-	case <-time.After(1 * time.Second):
-		require.True(false, "timeout waiting for message")
+	case err := <-sub.Err():
+		require.True(false, "unexpected subscription error: %v", err)
+	case <-time.After(400 * time.Millisecond):
+		// expected - data arrived before the timeout
 	}
 }
 
@@ -462,6 +1575,207 @@ func (s *WSSuite) TestUnsubscribe(assert, require *td.T) {
 	client.Close()
 }
 
+func (s *WSSuite) TestActiveSubscriptions(assert, require *td.T) {
+	t := require.TB
+	require.Parallel()
+
+	server := newMockWSServer(t)
+	defer server.close()
+
+	client := New(server.url)
+	require.False(client.IsConnected(), "connected before Start")
+	require.Cmp(client.ActiveSubscriptions(), []string{})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := client.Start(ctx)
+	require.CmpNoError(err)
+	require.True(client.IsConnected(), "not connected after Start")
+
+	btc1, err := client.SubscribeL2Book(ctx, "BTC", make(chan L2BookMessage))
+	require.CmpNoError(err)
+	btc2, err := client.SubscribeL2Book(ctx, "BTC", make(chan L2BookMessage))
+	require.CmpNoError(err)
+	mids, err := client.SubscribeAllMids(ctx, make(chan AllMidsMessage))
+	require.CmpNoError(err)
+
+	require.Cmp(
+		client.ActiveSubscriptions(),
+		[]string{"allMids", "l2Book:btc"},
+	)
+
+	// Unsubscribing runs asynchronously, so wait for it to take effect
+	waitFor := func(expected []string) {
+		deadline := time.Now().Add(time.Second)
+		for time.Now().Before(deadline) {
+			if slices.Equal(client.ActiveSubscriptions(), expected) {
+				return
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		require.Cmp(client.ActiveSubscriptions(), expected)
+	}
+
+	btc1.Unsubscribe()
+	waitFor([]string{"allMids", "l2Book:btc"})
+
+	btc2.Unsubscribe()
+	waitFor([]string{"allMids"})
+
+	mids.Unsubscribe()
+	waitFor([]string{})
+
+	client.Close()
+	assert.False(client.IsConnected(), "connected after Close")
+}
+
+func (s *WSSuite) TestUnsubscribeLastSubscriber(assert, require *td.T) {
+	t := require.TB
+	require.Parallel()
+
+	server := newMockWSServer(t)
+	defer server.close()
+
+	client := New(server.url)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := client.Start(ctx)
+	require.CmpNoError(err)
+
+	sub1, err := client.SubscribeL2Book(ctx, "BTC", make(chan L2BookMessage))
+	require.CmpNoError(err)
+	sub2, err := client.SubscribeL2Book(ctx, "BTC", make(chan L2BookMessage))
+	require.CmpNoError(err)
+
+	sub1.Unsubscribe()
+	time.Sleep(100 * time.Millisecond)
+	require.Cmp(server.count("unsubscribe"), 0, "first unsubscribe was sent")
+
+	sub2.Unsubscribe()
+	time.Sleep(100 * time.Millisecond)
+
+	assert.Cmp(server.count("subscribe"), 1, "subscribe frames")
+	assert.Cmp(server.count("unsubscribe"), 1, "unsubscribe frames")
+
+	client.Close()
+}
+
+func (s *WSSuite) TestResubscribeAfterUnsubscribe(assert, require *td.T) {
+	t := require.TB
+	require.Parallel()
+
+	server := newMockWSServer(t)
+	defer server.close()
+
+	client := New(server.url)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := client.Start(ctx)
+	require.CmpNoError(err)
+	defer client.Close()
+
+	const rounds = 50
+	for range rounds {
+		old, err := client.SubscribeL2Book(
+			ctx,
+			"BTC",
+			make(chan L2BookMessage),
+		)
+		require.CmpNoError(err)
+
+		// Unsubscribe cleans up asynchronously, so the last unsubscribe
+		// races the new subscribe
+		old.Unsubscribe()
+		sub, err := client.SubscribeL2Book(
+			ctx,
+			"BTC",
+			make(chan L2BookMessage),
+		)
+		require.CmpNoError(err)
+
+		sub.Unsubscribe()
+	}
+
+	time.Sleep(100 * time.Millisecond)
+
+	// Subscribe and unsubscribe frames alternate, so a late unsubscribe never
+	// cancels a newer subscribe
+	frames := server.subscriptionFrames()
+	require.NotEmpty(frames)
+	assert.Cmp(len(frames)%2, 0, "frames %v", frames)
+	for i, frame := range frames {
+		want := "subscribe"
+		if i%2 == 1 {
+			want = "unsubscribe"
+		}
+		assert.Cmp(frame, want, "frame %d", i)
+	}
+}
+
+func (s *WSSuite) TestSubscribeContextBoundsSendOnly(assert, require *td.T) {
+	t := require.TB
+	require.Parallel()
+
+	server := newMockWSServer(t)
+	defer server.close()
+
+	client := New(server.url)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := client.Start(ctx)
+	require.CmpNoError(err)
+	defer client.Close()
+
+	// A subscribe context that is already done subscribes nothing
+	doneCtx, doneCancel := context.WithCancel(ctx)
+	doneCancel()
+	_, err = client.SubscribeTrades(doneCtx, "ETH", make(chan TradesMessage))
+	require.True(errors.Is(err, context.Canceled), "got %v", err)
+	require.Len(client.ActiveSubscriptions(), 0)
+
+	subCtx, subCancel := context.WithCancel(ctx)
+	msgChan := make(chan L2BookMessage, 1)
+	sub, err := client.SubscribeL2Book(subCtx, "BTC", msgChan)
+	require.CmpNoError(err)
+
+	// Cancelling the subscribe context leaves the subscription active
+	subCancel()
+	time.Sleep(100 * time.Millisecond)
+
+	select {
+	case err := <-sub.Err():
+		require.True(false, "subscription ended: %v", err)
+	default:
+	}
+	assert.Cmp(client.ActiveSubscriptions(), []string{"l2Book:btc"})
+	assert.Cmp(server.count("unsubscribe"), 0, "unsubscribe frames")
+
+	msgBytes, _ := json.Marshal(map[string]any{
+		"channel": "l2Book",
+		"data":    map[string]any{"coin": "BTC", "time": 1234567890},
+	})
+	client.handleMessage(msgBytes)
+
+	select {
+	case received := <-msgChan:
+		assert.Cmp(received.Coin, "BTC")
+	case <-time.After(time.Second):
+		require.True(false, "timeout waiting for message")
+	}
+
+	sub.Unsubscribe()
+	require.CmpError(<-sub.Err())
+	time.Sleep(100 * time.Millisecond)
+
+	assert.Len(client.ActiveSubscriptions(), 0)
+	assert.Cmp(server.count("subscribe"), 1, "subscribe frames")
+	assert.Cmp(server.count("unsubscribe"), 1, "unsubscribe frames")
+}
+
 // ===== Multiple Subscriptions Per Channel =====
 
 func (s *WSSuite) TestMultipleSubscriptionsPerChannel(assert, require *td.T) {
@@ -534,6 +1848,73 @@ func (s *WSSuite) TestMultipleSubscriptionsPerChannel(assert, require *td.T) {
 
 // ===== Edge Cases =====
 
+func (s *WSSuite) TestSlowSubscriberDoesNotBlockOthers(
+	assert, require *td.T,
+) {
+	t := require.TB
+	require.Parallel()
+
+	server := newMockWSServer(t)
+	defer server.close()
+
+	client := New(server.url, WithSubscriptionBuffer(1))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := client.Start(ctx)
+	require.CmpNoError(err)
+	defer client.Close()
+
+	// Nobody ever reads from slowChan
+	slowChan := make(chan AllMidsMessage)
+	slowSub, err := client.SubscribeAllMids(ctx, slowChan)
+	require.CmpNoError(err)
+	defer slowSub.Unsubscribe()
+
+	fastChan := make(chan TradesMessage)
+	fastSub, err := client.SubscribeTrades(ctx, "ETH", fastChan)
+	require.CmpNoError(err)
+	defer fastSub.Unsubscribe()
+
+	midsBytes, _ := json.Marshal(map[string]any{
+		"channel": "allMids",
+		"data":    map[string]any{"mids": map[string]any{"BTC": "50000"}},
+	})
+	tradesBytes, _ := json.Marshal(map[string]any{
+		"channel": "trades",
+		"data": []any{
+			map[string]any{
+				"coin": "ETH",
+				"side": "A",
+				"px":   "3000",
+				"sz":   "10",
+				"hash": "0xabc123",
+				"time": 1234567890,
+			},
+		},
+	})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for range 5 {
+			client.handleMessage(midsBytes)
+		}
+		client.handleMessage(tradesBytes)
+	}()
+
+	select {
+	case received := <-fastChan:
+		require.Cmp(len(received.Trades), 1)
+	case <-time.After(1 * time.Second):
+		require.True(false, "slow subscriber blocked the read loop")
+	}
+	<-done
+
+	assert.Gt(slowSub.Dropped(), uint64(0))
+	assert.Cmp(fastSub.Dropped(), uint64(0))
+}
+
 func (s *WSSuite) TestEmptyTradesMessage(assert, require *td.T) {
 	t := require.TB
 	require.Parallel()
@@ -620,6 +2001,30 @@ func (s *WSSuite) TestMissingDataField(assert, require *td.T) {
 
 // ===== Subscription payload shape =====
 
+func (s *WSSuite) TestAllMidsFloats(assert, require *td.T) {
+	require.Parallel()
+
+	msg := AllMidsMessage{Mids: map[string]string{
+		"BTC":   "50000.5",
+		"ETH":   "3000",
+		"@107":  "0.000123",
+		"kPEPE": "0.0101",
+	}}
+
+	mids, err := msg.Floats()
+	require.CmpNoError(err)
+	require.Len(mids, len(msg.Mids))
+	for coin, px := range msg.Mids {
+		want, err := strconv.ParseFloat(px, 64)
+		require.CmpNoError(err)
+		require.Cmp(mids[coin], want, coin)
+	}
+
+	msg.Mids["BAD"] = "not a number"
+	_, err = msg.Floats()
+	require.CmpError(err)
+}
+
 func (s *WSSuite) TestSubscriptionPayload(assert, require *td.T) {
 	require.Parallel()
 
@@ -646,6 +2051,23 @@ func (s *WSSuite) TestSubscriptionPayload(assert, require *td.T) {
 			},
 			expectedKeys: []string{"type", "coin", "user"},
 		},
+		{
+			name: "Notification includes type and user",
+			sub: NotificationSubscription{
+				User: common.HexToAddress("0xABC"),
+			},
+			expectedKeys: []string{"type", "user"},
+		},
+		{
+			name:         "ExplorerBlock includes type",
+			sub:          ExplorerBlockSubscription{},
+			expectedKeys: []string{"type"},
+		},
+		{
+			name:         "ExplorerTxs includes type",
+			sub:          ExplorerTxsSubscription{},
+			expectedKeys: []string{"type"},
+		},
 	}
 
 	for _, tt := range tests {
@@ -668,3 +2090,175 @@ func (s *WSSuite) TestSubscriptionPayload(assert, require *td.T) {
 		}
 	}
 }
+
+// ===== Candle Aggregation Tests =====
+
+func (s *WSSuite) TestAggregateCandles(assert, require *td.T) {
+	require.Parallel()
+
+	trades := make(chan TradesMessage, 3)
+	out := make(chan CandleMessage, 4)
+	minute := int64(time.Minute / time.Millisecond)
+	base := 100 * minute
+
+	trade := func(coin, px, sz string, at int64) Trade {
+		return Trade{Coin: coin, Px: px, Sz: sz, Time: at}
+	}
+	trades <- TradesMessage{Trades: []Trade{
+		trade("ETH", "3000", "0.5", base+1_000),
+		trade("ETH", "3010.5", "1.25", base+20_000),
+		trade("ETH", "2995", "4", base+40_000),
+		trade("BTC", "60000", "0.001", base+50_000),
+	}}
+	trades <- TradesMessage{Trades: []Trade{
+		trade("ETH", "3005", "3", base+59_999),
+		// Crosses the boundary and closes the first ETH candle
+		trade("ETH", "3020", "5", base+minute),
+		// Older than the current ETH candle and skipped
+		trade("ETH", "1", "100", base+30_000),
+	}}
+	trades <- TradesMessage{Trades: []Trade{
+		trade("ETH", "bad", "1", base+minute+1),
+		trade("ETH", "1", "bad", base+minute+1_000),
+		trade("ETH", "3015", "0.25", base+minute+2_000),
+	}}
+	close(trades)
+
+	err := AggregateCandles(context.Background(), trades, time.Minute, out)
+	require.CmpNoError(err)
+	close(out)
+
+	var candles []CandleMessage
+	for c := range out {
+		candles = append(candles, c)
+	}
+	assert.Cmp(candles, []CandleMessage{
+		{
+			S: "ETH", I: "1m", T: base,
+			O: "3000", H: "3010.5", L: "2995", C: "3005", V: "8.75",
+		},
+		// Candles still open when trades closes are flushed in coin order
+		{
+			S: "BTC", I: "1m", T: base,
+			O: "60000", H: "60000", L: "60000", C: "60000", V: "0.001",
+		},
+		{
+			S: "ETH", I: "1m", T: base + minute,
+			O: "3020", H: "3020", L: "3015", C: "3015", V: "5.25",
+		},
+	})
+}
+
+func (s *WSSuite) TestAggregateCandlesContext(assert, require *td.T) {
+	require.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := AggregateCandles(
+		ctx,
+		make(chan TradesMessage),
+		5*time.Minute,
+		make(chan CandleMessage),
+	)
+	assert.True(errors.Is(err, context.Canceled), "got %v", err)
+
+	err = AggregateCandles(
+		context.Background(),
+		make(chan TradesMessage),
+		0,
+		make(chan CandleMessage),
+	)
+	assert.CmpError(err)
+
+	assert.Cmp(intervalLabel(4*time.Hour), "4h")
+	assert.Cmp(intervalLabel(90*time.Second), "90s")
+	assert.Cmp(intervalLabel(24*time.Hour), "1d")
+}
+
+// ===== Order Book Tests =====
+
+func (s *WSSuite) TestOrderBook(assert, require *td.T) {
+	t := require.TB
+	require.Parallel()
+
+	server := newMockWSServer(t)
+	defer server.close()
+
+	client := New(server.url)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := client.Start(ctx)
+	require.CmpNoError(err)
+	defer client.Close()
+
+	book, err := NewOrderBook(ctx, client, "BTC")
+	require.CmpNoError(err)
+	defer book.Close()
+
+	_, ok := book.BestBid()
+	assert.False(ok, "best bid before the first snapshot")
+	_, ok = book.Snapshot()
+	assert.False(ok, "snapshot before the first snapshot")
+
+	snapshot := func(bid, ask string, at int64) []byte {
+		msg, _ := json.Marshal(map[string]any{
+			"channel": "l2Book",
+			"data": map[string]any{
+				"coin": "BTC",
+				"levels": [][]map[string]any{
+					{{"px": bid, "sz": "1", "n": 1}},
+					{{"px": ask, "sz": "2", "n": 2}},
+				},
+				"time": at,
+			},
+		})
+		return msg
+	}
+	waitFor := func(bid string) {
+		deadline := time.Now().Add(time.Second)
+		for time.Now().Before(deadline) {
+			if level, ok := book.BestBid(); ok && level.Px == bid {
+				return
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+		require.True(false, "timeout waiting for best bid %s", bid)
+	}
+
+	client.handleMessage(snapshot("50000", "50010", 1))
+	waitFor("50000")
+	client.handleMessage(snapshot("50100", "50110", 2))
+	waitFor("50100")
+
+	ask, ok := book.BestAsk()
+	require.True(ok)
+	assert.Cmp(ask, L2Level{Px: "50110", Sz: "2", N: 2})
+
+	got, ok := book.Snapshot()
+	require.True(ok)
+	assert.Cmp(got, L2BookMessage{
+		Coin: "BTC",
+		Levels: [2][]L2Level{
+			{{Px: "50100", Sz: "1", N: 1}},
+			{{Px: "50110", Sz: "2", N: 2}},
+		},
+		Time: 2,
+	})
+
+	// Snapshots are copies
+	got.Levels[0][0].Px = "0"
+	bid, _ := book.BestBid()
+	assert.Cmp(bid.Px, "50100")
+
+	// An older snapshot does not replace a newer one
+	client.handleMessage(snapshot("49000", "49010", 1))
+	time.Sleep(50 * time.Millisecond)
+	bid, _ = book.BestBid()
+	assert.Cmp(bid.Px, "50100")
+
+	book.Close()
+	require.CmpError(<-book.Err())
+	assert.Len(client.ActiveSubscriptions(), 0)
+}